@@ -0,0 +1,257 @@
+// Package baseconv formats and parses integers in any base from 2 to 62 and in
+// custom alphabets such as Base58 (Bitcoin) and Crockford Base32.
+//
+// strconv.FormatInt stops at base 36 and only knows "0-9a-z". an Encoding here
+// is an alphabet plus an optional layout (prefix, zero padding, digit grouping
+// and a Crockford check symbol), and every Format method has a Parse method
+// that accepts exactly what it produced:
+//
+//	hex := baseconv.Base(16).WithPrefix("0x").WithGrouping(4, '_')
+//	hex.FormatUint(0xdeadbeef)          // "0xdead_beef"
+//	hex.ParseUint("0xdead_beef")        // 3735928559
+//
+//	bin := baseconv.Base(2).WithPrefix("0b").WithWidth(8).WithGrouping(4, '_')
+//	bin.FormatUint(0xaa)                // "0b1010_1010"
+package baseconv
+
+import (
+	"fmt"
+	"strings"
+)
+
+// standard digit order: the first 36 digits match strconv so Base(16) gives
+// the same output as strconv.FormatInt(n, 16)
+const stdDigits = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+const (
+	bitcoin58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	// crockford check symbols: the 32 digits plus 5 extra symbols, value mod 37
+	crockfordCheck = crockfordAlphabet + "*~$=U"
+)
+
+// invalid marks a byte that is not a digit in the decode table
+const invalid = -1
+
+// Encoding is an alphabet plus layout options. Encodings are immutable, the
+// With* methods return modified copies so predefined encodings can be shared.
+type Encoding struct {
+	alphabet string
+	decode   [256]int16
+
+	prefix   string
+	width    int
+	group    int
+	sep      byte
+	checksum bool
+}
+
+var (
+	// Base58 is the Bitcoin Base58 alphabet (no 0, O, I or l).
+	Base58 = mustEncoding(bitcoin58Alphabet)
+
+	// Base62 is Base(62): 0-9, a-z, A-Z.
+	Base62 = Base(62)
+
+	// Crockford is Crockford's Base32. decoding is case-insensitive, reads
+	// I and L as 1 and O as 0, and accepts '-' between digits.
+	Crockford = newCrockford()
+)
+
+// NewEncoding returns an encoding using the given alphabet, where alphabet[i]
+// is the digit for value i. the alphabet must be ASCII, have at least two
+// digits and contain no duplicates. decoding is case-sensitive.
+func NewEncoding(alphabet string) (*Encoding, error) {
+	if len(alphabet) < 2 {
+		return nil, fmt.Errorf("baseconv: alphabet %q needs at least 2 digits", alphabet)
+	}
+	e := &Encoding{alphabet: alphabet}
+	for i := range e.decode {
+		e.decode[i] = invalid
+	}
+	for i := 0; i < len(alphabet); i++ {
+		c := alphabet[i]
+		if c >= 0x80 {
+			return nil, fmt.Errorf("baseconv: alphabet %q contains non-ASCII byte %#x", alphabet, c)
+		}
+		if c == '+' || c == '-' {
+			return nil, fmt.Errorf("baseconv: alphabet %q contains sign character %q", alphabet, c)
+		}
+		if e.decode[c] != invalid {
+			return nil, fmt.Errorf("baseconv: alphabet %q repeats digit %q", alphabet, c)
+		}
+		e.decode[c] = int16(i)
+	}
+	return e, nil
+}
+
+func mustEncoding(alphabet string) *Encoding {
+	e, err := NewEncoding(alphabet)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// Base returns the standard encoding for base b (2 <= b <= 62). bases up to 36
+// use lowercase letters like strconv and also accept uppercase when parsing.
+func Base(b int) *Encoding {
+	if b < 2 || b > len(stdDigits) {
+		panic(fmt.Sprintf("baseconv: base %d out of range [2, %d]", b, len(stdDigits)))
+	}
+	e := mustEncoding(stdDigits[:b])
+	if b <= 36 {
+		e.foldCase()
+	}
+	return e
+}
+
+func newCrockford() *Encoding {
+	e := mustEncoding(crockfordAlphabet)
+	e.foldCase()
+	e.decode['I'], e.decode['i'] = 1, 1
+	e.decode['L'], e.decode['l'] = 1, 1
+	e.decode['O'], e.decode['o'] = 0, 0
+	e.sep = '-'
+	return e
+}
+
+// foldCase makes the other case of every letter decode to the same value.
+func (e *Encoding) foldCase() {
+	for i := 0; i < len(e.alphabet); i++ {
+		c := e.alphabet[i]
+		switch {
+		case 'a' <= c && c <= 'z':
+			e.decode[c-'a'+'A'] = int16(i)
+		case 'A' <= c && c <= 'Z':
+			e.decode[c-'A'+'a'] = int16(i)
+		}
+	}
+}
+
+// Base reports the number of digits in the alphabet.
+func (e *Encoding) Base() int { return len(e.alphabet) }
+
+// Alphabet returns the digits of the encoding in value order.
+func (e *Encoding) Alphabet() string { return e.alphabet }
+
+// WithPrefix returns a copy that writes prefix (e.g. "0x") before the digits.
+// when parsing the prefix is optional and matched case-insensitively.
+func (e *Encoding) WithPrefix(prefix string) *Encoding {
+	c := *e
+	c.prefix = prefix
+	return &c
+}
+
+// WithWidth returns a copy that left-pads the digits with the zero digit
+// (alphabet[0]) to at least width digits. prefix, sign and separators do not
+// count towards the width. EncodeToString does not pad: its leading zero
+// digits stand for zero bytes.
+func (e *Encoding) WithWidth(width int) *Encoding {
+	c := *e
+	c.width = max(width, 0)
+	return &c
+}
+
+// WithGrouping returns a copy that inserts sep between every size digits,
+// counted from the right: Base(16).WithGrouping(4, '_') gives "dead_beef".
+// the separator is also accepted when parsing, following Go literal rules:
+// only between two digits or right after the prefix. size 0 disables grouping
+// on output but keeps sep valid for parsing.
+func (e *Encoding) WithGrouping(size int, sep byte) *Encoding {
+	if sep == 0 || sep >= 0x80 || e.decode[sep] != invalid {
+		panic(fmt.Sprintf("baseconv: separator %q is a digit or not ASCII", sep))
+	}
+	c := *e
+	c.group = max(size, 0)
+	c.sep = sep
+	return &c
+}
+
+// WithChecksum returns a copy of a Crockford encoding that appends the mod 37
+// check symbol when formatting and verifies it when parsing.
+func (e *Encoding) WithChecksum() *Encoding {
+	if e.alphabet != crockfordAlphabet {
+		panic("baseconv: checksum is only defined for the Crockford alphabet")
+	}
+	c := *e
+	c.checksum = true
+	return &c
+}
+
+// FormatUint returns n in the encoding's base and layout.
+func (e *Encoding) FormatUint(n uint64) string {
+	return string(e.AppendUint(nil, n))
+}
+
+// FormatInt returns n in the encoding's base and layout, with a leading '-'
+// (before the prefix) for negative numbers: -0xff.
+func (e *Encoding) FormatInt(n int64) string {
+	return string(e.AppendInt(nil, n))
+}
+
+// AppendUint appends the formatted form of n to dst.
+func (e *Encoding) AppendUint(dst []byte, n uint64) []byte {
+	var buf [64]byte // base 2 is the longest: 64 digits
+	b := uint64(len(e.alphabet))
+	check := n % 37
+	i := len(buf)
+	for {
+		i--
+		buf[i] = e.alphabet[n%b]
+		n /= b
+		if n == 0 {
+			break
+		}
+	}
+	return e.appendLayout(dst, buf[i:], check)
+}
+
+// AppendInt appends the formatted form of n to dst.
+func (e *Encoding) AppendInt(dst []byte, n int64) []byte {
+	if n < 0 {
+		dst = append(dst, '-')
+		// -n overflows for MinInt64 but the uint64 conversion is still right
+		return e.AppendUint(dst, uint64(-n))
+	}
+	return e.AppendUint(dst, uint64(n))
+}
+
+// appendLayout writes prefix, padding, grouped digits and the check symbol.
+func (e *Encoding) appendLayout(dst, digits []byte, check uint64) []byte {
+	dst = append(dst, e.prefix...)
+	total := max(len(digits), e.width)
+	for i := 0; i < total; i++ {
+		if i > 0 && e.group > 0 && (total-i)%e.group == 0 {
+			dst = append(dst, e.sep)
+		}
+		if pad := total - len(digits); i < pad {
+			dst = append(dst, e.alphabet[0])
+		} else {
+			dst = append(dst, digits[i-pad])
+		}
+	}
+	if e.checksum {
+		dst = append(dst, crockfordCheck[check])
+	}
+	return dst
+}
+
+// String describes the encoding, e.g. "base16 prefix=0x group=4 sep='_'".
+func (e *Encoding) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "base%d", len(e.alphabet))
+	if e.prefix != "" {
+		fmt.Fprintf(&sb, " prefix=%s", e.prefix)
+	}
+	if e.width > 0 {
+		fmt.Fprintf(&sb, " width=%d", e.width)
+	}
+	if e.sep != 0 {
+		fmt.Fprintf(&sb, " group=%d sep=%q", e.group, e.sep)
+	}
+	if e.checksum {
+		sb.WriteString(" checksum")
+	}
+	return sb.String()
+}
@@ -0,0 +1,91 @@
+package baseconv
+
+// EncodeToString encodes src as one big-endian number, the way Base58 is used
+// for keys and hashes. each leading zero byte becomes one leading zero digit
+// (alphabet[0], '1' for Base58) so the byte length survives a round trip.
+// for the same reason WithWidth does not apply: padding digits would decode
+// as extra zero bytes.
+func (e *Encoding) EncodeToString(src []byte) string {
+	zeros := 0
+	for zeros < len(src) && src[zeros] == 0 {
+		zeros++
+	}
+
+	// repeated division of the byte string by the base, digits come out
+	// least significant first
+	b := len(e.alphabet)
+	num := append([]byte(nil), src[zeros:]...)
+	var digits []byte
+	for len(num) > 0 {
+		rem := 0
+		out := num[:0]
+		for _, c := range num {
+			acc := rem*256 + int(c)
+			q := acc / b
+			rem = acc % b
+			if len(out) > 0 || q != 0 {
+				out = append(out, byte(q))
+			}
+		}
+		digits = append(digits, e.alphabet[rem])
+		num = out
+	}
+	for range zeros {
+		digits = append(digits, e.alphabet[0])
+	}
+	for i, j := 0, len(digits)-1; i < j; i, j = i+1, j-1 {
+		digits[i], digits[j] = digits[j], digits[i]
+	}
+	unpadded := *e
+	unpadded.width = 0
+	return string(unpadded.appendLayout(nil, digits, uint64(mod37(src))))
+}
+
+// DecodeString is the inverse of EncodeToString. leading zero digits become
+// leading zero bytes.
+func (e *Encoding) DecodeString(s string) ([]byte, error) {
+	const fn = "DecodeString"
+	if s == "" {
+		return []byte{}, nil
+	}
+
+	b := len(e.alphabet)
+	zeros := 0
+	var num []byte // big-endian magnitude
+	err := e.scan(fn, s, 0, func(d int) error {
+		if d == 0 && len(num) == 0 {
+			zeros++
+			return nil
+		}
+		// num = num*b + d
+		carry := d
+		for j := len(num) - 1; j >= 0; j-- {
+			acc := int(num[j])*b + carry
+			num[j] = byte(acc)
+			carry = acc >> 8
+		}
+		for carry > 0 {
+			num = append([]byte{byte(carry)}, num...)
+			carry >>= 8
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := append(make([]byte, zeros, zeros+len(num)), num...)
+	if err := e.verifyCheck(fn, s, mod37(out)); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// mod37 returns the big-endian number in src modulo 37.
+func mod37(src []byte) int {
+	r := 0
+	for _, c := range src {
+		r = (r*256 + int(c)) % 37
+	}
+	return r
+}
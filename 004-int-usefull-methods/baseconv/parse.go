@@ -0,0 +1,157 @@
+package baseconv
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	// ErrSyntax means the input contains a byte that is not a digit, sign,
+	// prefix or correctly placed separator.
+	ErrSyntax = errors.New("invalid syntax")
+	// ErrRange means the value does not fit in the result type.
+	ErrRange = errors.New("value out of range")
+	// ErrChecksum means the Crockford check symbol does not match the value.
+	ErrChecksum = errors.New("checksum mismatch")
+)
+
+// Error records a failed parse and where in the input it failed.
+type Error struct {
+	Func   string // the failing function (ParseUint, ParseInt, DecodeString)
+	Input  string // the input
+	Offset int    // byte offset of the offending character, len(Input) if input ended early
+	Err    error  // ErrSyntax, ErrRange or ErrChecksum
+}
+
+func (e *Error) Error() string {
+	at := "end of input"
+	if e.Offset < len(e.Input) {
+		r, _ := utf8.DecodeRuneInString(e.Input[e.Offset:])
+		at = fmt.Sprintf("%q at offset %d", r, e.Offset)
+	}
+	return fmt.Sprintf("baseconv.%s: parsing %q: %v: %s", e.Func, e.Input, e.Err, at)
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// ParseUint parses s as an unsigned number in the encoding. the prefix is
+// optional, separators must sit between digits and, with a checksum, the
+// final character must be the matching check symbol.
+func (e *Encoding) ParseUint(s string) (uint64, error) {
+	return e.parse("ParseUint", s, 0, ^uint64(0))
+}
+
+// ParseInt parses s as a signed number in the encoding, with an optional
+// leading '+' or '-' before the prefix.
+func (e *Encoding) ParseInt(s string) (int64, error) {
+	start, neg := 0, false
+	if s != "" && (s[0] == '+' || s[0] == '-') {
+		start, neg = 1, s[0] == '-'
+	}
+	limit := uint64(1<<63 - 1)
+	if neg {
+		limit = 1 << 63
+	}
+	n, err := e.parse("ParseInt", s, start, limit)
+	if err != nil {
+		return 0, err
+	}
+	if neg {
+		return -int64(n), nil
+	}
+	return int64(n), nil
+}
+
+// parse reads s[start:] and fails with ErrRange as soon as the value would
+// exceed limit, pointing at the digit that overflowed.
+func (e *Encoding) parse(fn, s string, start int, limit uint64) (uint64, error) {
+	b := uint64(len(e.alphabet))
+	var n uint64
+	err := e.scan(fn, s, start, func(d int) error {
+		if n > (limit-uint64(d))/b {
+			return ErrRange
+		}
+		n = n*b + uint64(d)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := e.verifyCheck(fn, s, int(n%37)); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// scan validates the layout of s[start:] and calls digit with the value of
+// each digit in order. an error from digit is reported at that digit's offset.
+func (e *Encoding) scan(fn, s string, start int, digit func(d int) error) error {
+	fail := func(off int, err error) error {
+		return &Error{Func: fn, Input: s, Offset: off, Err: err}
+	}
+
+	i := start
+	if e.prefix != "" && len(s)-i >= len(e.prefix) && strings.EqualFold(s[i:i+len(e.prefix)], e.prefix) {
+		i += len(e.prefix)
+	}
+	afterPrefix := i > start
+
+	end := len(s)
+	if e.checksum {
+		end--
+	}
+	digits := 0
+	for ; i < end; i++ {
+		c := s[i]
+		if e.sep != 0 && c == e.sep {
+			// go literal rule: a separator follows a digit or the prefix and
+			// is followed by a digit
+			if !(digits > 0 || afterPrefix) || s[i-1] == e.sep || i+1 >= end {
+				return fail(i, ErrSyntax)
+			}
+			continue
+		}
+		d := e.decode[c]
+		if d == invalid {
+			return fail(i, ErrSyntax)
+		}
+		if err := digit(int(d)); err != nil {
+			return fail(i, err)
+		}
+		digits++
+	}
+	if digits == 0 {
+		return fail(max(end, i), ErrSyntax)
+	}
+	return nil
+}
+
+// verifyCheck compares the trailing check symbol of s with want (value mod 37).
+func (e *Encoding) verifyCheck(fn, s string, want int) error {
+	if !e.checksum {
+		return nil
+	}
+	end := len(s) - 1
+	if got := checkValue(s[end]); got < 0 {
+		return &Error{Func: fn, Input: s, Offset: end, Err: ErrSyntax}
+	} else if got != want {
+		return &Error{Func: fn, Input: s, Offset: end, Err: ErrChecksum}
+	}
+	return nil
+}
+
+// checkValue decodes a Crockford check symbol, -1 if c is not one.
+func checkValue(c byte) int {
+	switch c {
+	case 'i', 'I', 'l', 'L':
+		return 1
+	case 'o', 'O':
+		return 0
+	}
+	if 'a' <= c && c <= 'z' {
+		c -= 'a' - 'A'
+	}
+	return strings.IndexByte(crockfordCheck, c)
+}
@@ -0,0 +1,273 @@
+// bits draws the binary tables from the BITWISE OPERATORS section of 004 for
+// any integer expression:
+//
+//	go run ./cmd/bits '5 &^ 3'
+//	go run ./cmd/bits -x 'int8(-7) >> 2'
+//	go run ./cmd/bits -w 16 '^0x0f'
+//
+// untyped expressions use the smallest of 8, 16, 32 or 64 bits that holds
+// every row unless -w is given. typed expressions use the width of the type.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+
+	"int-methods/intexpr"
+)
+
+func main() {
+	width := flag.Int("w", 0, "width in bits for untyped expressions: 8, 16, 32 or 64")
+	radix := flag.Bool("x", false, "also print hex and octal columns")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: bits [-w bits] [-x] [--] 'expression'")
+		fmt.Fprintln(os.Stderr, "use -- before an expression that starts with '-'")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	src := strings.Join(flag.Args(), " ")
+	t, err := build(src, *width)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "bits:", err)
+		os.Exit(1)
+	}
+	t.radix = *radix
+	t.print(os.Stdout)
+}
+
+// row is one line of the table: an operator, a value and its bit pattern.
+type row struct {
+	op    string
+	value *big.Int
+	count bool // a shift count, printed as a plain number
+}
+
+type table struct {
+	title string
+	width int
+	rows  []row // operands, then the result after the rule
+	mark  struct {
+		from, to int // bit positions (0 is the lowest) to underline
+		note     string
+	}
+	notes []string // extra explanations, printed after the table
+	radix bool
+}
+
+// build evaluates the expression and lays out its table.
+func build(src string, width int) (*table, error) {
+	e, err := intexpr.Parse(src)
+	if err != nil {
+		return nil, err
+	}
+	res, err := intexpr.EvalExpr(e)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p, ok := e.(*ast.ParenExpr)
+		if !ok {
+			break
+		}
+		e = p.X
+	}
+
+	t := &table{title: fmt.Sprintf("%s = %s", src, res)}
+	t.mark.from = -1
+	var operands []*big.Int // the rows that are drawn in binary
+	switch e := e.(type) {
+	case *ast.BinaryExpr:
+		x, err := intexpr.EvalExpr(e.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := intexpr.EvalExpr(e.Y)
+		if err != nil {
+			return nil, err
+		}
+		shift := e.Op == token.SHL || e.Op == token.SHR
+		t.rows = []row{{value: x.Int}, {op: e.Op.String(), value: y.Int, count: shift}}
+		operands = []*big.Int{x.Int}
+		if !shift {
+			operands = append(operands, y.Int)
+		}
+	case *ast.UnaryExpr:
+		x, err := intexpr.EvalExpr(e.X)
+		if err != nil {
+			return nil, err
+		}
+		t.rows = []row{{op: e.Op.String(), value: x.Int}}
+		operands = []*big.Int{x.Int}
+	}
+	t.rows = append(t.rows, row{value: res.Int})
+
+	if t.width, err = pickWidth(res.Type, width, append(operands, res.Int)); err != nil {
+		return nil, err
+	}
+	if b, ok := e.(*ast.BinaryExpr); ok && (b.Op == token.SHL || b.Op == token.SHR) {
+		t.explainShift(b.Op, res.Type)
+	}
+	seen := map[string]bool{}
+	for _, v := range append(operands, res.Int) {
+		if v.Sign() < 0 && !seen[v.String()] {
+			seen[v.String()] = true
+			t.explainNegative(v)
+		}
+	}
+	return t, nil
+}
+
+// pickWidth uses the type's width for typed results and otherwise the flag
+// or the smallest standard width that holds every value.
+func pickWidth(typ *intexpr.Type, flagWidth int, vals []*big.Int) (int, error) {
+	if typ != nil {
+		if flagWidth != 0 && flagWidth != typ.Bits {
+			return 0, fmt.Errorf("-w %d conflicts with type %s (%d bits)", flagWidth, typ, typ.Bits)
+		}
+		return typ.Bits, nil
+	}
+	if flagWidth != 0 {
+		switch flagWidth {
+		case 8, 16, 32, 64:
+			return flagWidth, nil
+		}
+		return 0, fmt.Errorf("-w must be 8, 16, 32 or 64, not %d", flagWidth)
+	}
+	for _, w := range []int{8, 16, 32, 64} {
+		s := &intexpr.Type{Bits: w, Signed: true}
+		u := &intexpr.Type{Bits: w}
+		ok := true
+		for _, v := range vals {
+			if !s.Fits(v) && !u.Fits(v) {
+				ok = false
+			}
+		}
+		if ok {
+			return w, nil
+		}
+	}
+	return 0, fmt.Errorf("value needs more than 64 bits, convert it to a sized type")
+}
+
+// maxExactShift is the largest shift whose exact result explainShift
+// prints in full; beyond it the result is written as x×2^n.
+const maxExactShift = 256
+
+// explainShift underlines the bits that the shift filled in.
+func (t *table) explainShift(op token.Token, typ *intexpr.Type) {
+	x, count := t.rows[0].value, t.rows[1].value
+	// the table only has width bits to mark; the exact result uses count
+	n := t.width
+	if count.IsInt64() && count.Int64() < int64(n) {
+		n = int(count.Int64())
+	}
+	if n == 0 {
+		return
+	}
+	if op == token.SHL {
+		t.mark.from, t.mark.to = 0, n-1
+		t.mark.note = "zero-filled"
+		if typ == nil || x.Sign() == 0 {
+			return
+		}
+		if !count.IsInt64() || count.Int64() > maxExactShift {
+			t.notes = append(t.notes, fmt.Sprintf("bits shifted out of the top were lost: exact result %s×2^%s does not fit %s", x, count, typ))
+			return
+		}
+		lost := new(big.Int).Lsh(x, uint(count.Int64()))
+		if lost.Cmp(t.rows[2].value) != 0 {
+			t.notes = append(t.notes, fmt.Sprintf("bits shifted out of the top were lost: exact result %s does not fit %s", lost, typ))
+		}
+		return
+	}
+	t.mark.from, t.mark.to = t.width-n, t.width-1
+	switch {
+	case typ != nil && !typ.Signed:
+		t.mark.note = "zero-filled (logical shift, unsigned)"
+	case x.Sign() < 0:
+		t.mark.note = "sign bit copied in (arithmetic shift)"
+	default:
+		t.mark.note = "sign bit (0) copied in (arithmetic shift)"
+	}
+}
+
+// explainNegative shows how the two's complement pattern of v is built.
+func (t *table) explainNegative(v *big.Int) {
+	abs := new(big.Int).Abs(v)
+	mask := new(big.Int).Lsh(big.NewInt(1), uint(t.width))
+	mask.Sub(mask, big.NewInt(1))
+	inv := new(big.Int).Xor(abs, mask)
+	t.notes = append(t.notes, strings.Join([]string{
+		fmt.Sprintf("two's complement of %s in %d bits:", v, t.width),
+		fmt.Sprintf("    %s  %s is |%s|", t.binary(abs), abs, v),
+		fmt.Sprintf("    %s  invert all bits", t.binary(inv)),
+		fmt.Sprintf("    %s  add 1 -> %s", t.binary(v), v),
+	}, "\n"))
+}
+
+// binary returns the low width bits of v, a space between bytes.
+func (t *table) binary(v *big.Int) string {
+	p := intexpr.Value{Int: v}.Pattern(t.width)
+	s := fmt.Sprintf("%0*b", t.width, p)
+	return group(s)
+}
+
+func group(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if i > 0 && (len(s)-i)%8 == 0 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
+
+func (t *table) print(w io.Writer) {
+	fmt.Fprintln(w, t.title)
+	fmt.Fprintln(w)
+	rule := strings.Repeat("-", t.width)
+	for i, r := range t.rows {
+		if i > 0 && i == len(t.rows)-1 {
+			fmt.Fprintf(w, "    %s\n", group(rule))
+		}
+		if r.count {
+			fmt.Fprintf(w, "%3s %s\n", r.op, r.value)
+			continue
+		}
+		line := fmt.Sprintf("%3s %s  %s", r.op, t.binary(r.value), r.value)
+		if t.radix {
+			p := intexpr.Value{Int: r.value}.Pattern(t.width)
+			line = fmt.Sprintf("%3s %s  %-*s  0x%0*x  0o%0*o", r.op, t.binary(r.value), 20, r.value,
+				t.width/4, p, (t.width+2)/3, p)
+		}
+		fmt.Fprintln(w, line)
+	}
+	if t.mark.from >= 0 {
+		marks := make([]byte, t.width)
+		for i := range marks {
+			// marks[0] is the highest bit
+			if bit := t.width - 1 - i; bit >= t.mark.from && bit <= t.mark.to {
+				marks[i] = '^'
+			} else {
+				marks[i] = ' '
+			}
+		}
+		fmt.Fprintf(w, "    %s  %s\n", group(string(marks)), t.mark.note)
+	}
+	for _, n := range t.notes {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, n)
+	}
+}
@@ -0,0 +1,119 @@
+// intrepl is a REPL for typed go integer expressions. it prints the result,
+// its type and bits, and flags every wraparound or truncation compared with
+// exact arithmetic:
+//
+//	>>> int8(127) + 1
+//	-128  int8  10000000
+//	  ! wraparound: int8(127) + 1 = 128 does not fit int8 [-128, 127], got -128
+//	  ! constant expression: the compiler rejects this (constant 128 overflows int8),
+//	    the result above is what a variable of that type does at run time
+//
+// variables work like in go: x := int32(5), var y uint8 = 200, y += 100.
+// run it with: go run ./cmd/intrepl
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+
+	"int-methods/intexpr"
+)
+
+const help = `type a go integer expression or statement, for example
+  int8(127) + 1        uint8(0) - 1        math.MaxInt64 + 1
+  x := int32(1 << 20)  x * x               var b byte = 250; b += 10
+commands: :vars lists variables, :help shows this, :quit or ctrl-d exits`
+
+func main() {
+	env := intexpr.NewEnv()
+	in := bufio.NewScanner(os.Stdin)
+	fmt.Println("integer REPL, :help for help")
+	for {
+		fmt.Print(">>> ")
+		if !in.Scan() {
+			fmt.Println()
+			return
+		}
+		line := strings.TrimSpace(in.Text())
+		switch line {
+		case "":
+			continue
+		case ":quit", ":q", "exit":
+			return
+		case ":help":
+			fmt.Println(help)
+			continue
+		case ":vars":
+			for _, name := range env.Names() {
+				v, _ := env.Lookup(name)
+				fmt.Printf("%s = %s\n", name, v)
+			}
+			continue
+		}
+		// "a; b" runs both, like statements on one line in go
+		for _, stmt := range strings.Split(line, ";") {
+			if stmt = strings.TrimSpace(stmt); stmt != "" {
+				run(os.Stdout, env, stmt)
+			}
+		}
+	}
+}
+
+func run(w io.Writer, env *intexpr.Env, line string) {
+	name, v, err := env.Exec(line)
+	if err != nil {
+		if e, ok := err.(*intexpr.Error); ok {
+			// point at the offending column under the prompt
+			fmt.Fprintf(w, "    %s^\n", strings.Repeat(" ", e.Offset))
+			fmt.Fprintf(w, "error: %s\n", e.Msg)
+			return
+		}
+		fmt.Fprintf(w, "error: %v\n", err)
+		return
+	}
+
+	prefix := ""
+	if name != "" {
+		prefix = name + " = "
+	}
+	fmt.Fprintf(w, "%s%s  %s  %s\n", prefix, v.Int, v.TypeName(), binary(v))
+
+	for _, e := range v.Events {
+		fmt.Fprintf(w, "  ! %s\n", e)
+	}
+	if n := len(v.Events); n > 0 && v.Exact.Cmp(v.Events[n-1].Want) != 0 {
+		// an earlier wrap changed the operands of a later operation
+		fmt.Fprintf(w, "  ! exact arithmetic gives %s, the result is off by %s\n", v.Exact, new(big.Int).Sub(v.Int, v.Exact))
+	}
+	if len(v.Events) > 0 && v.Const {
+		first := v.Events[0]
+		fmt.Fprintf(w, "  ! constant expression: the compiler rejects this (constant %s overflows %s),\n", first.Want, first.Type)
+		fmt.Fprintln(w, "    the result above is what a variable of that type does at run time")
+	}
+	if v.Type == nil && name == "" && !intexpr.Int.Fits(v.Int) {
+		// an untyped constant only becomes a value (and gets the default
+		// type int) when it is used, e.g. passed to fmt.Println
+		fmt.Fprintf(w, "  ! untyped constant overflow: using this as a value is a compile error (cannot use %s (untyped int constant) as int value: overflows)\n", v.Int)
+	}
+}
+
+// binary shows typed values in their full width and two's complement,
+// untyped constants as exact signed binary.
+func binary(v intexpr.Value) string {
+	if v.Type == nil {
+		return v.Int.Text(2)
+	}
+	s := fmt.Sprintf("%0*b", v.Type.Bits, v.Pattern(v.Type.Bits))
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if i > 0 && (len(s)-i)%8 == 0 {
+			sb.WriteByte('_')
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
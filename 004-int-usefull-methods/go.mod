@@ -0,0 +1,3 @@
+module int-methods
+
+go 1.24.0
@@ -0,0 +1,225 @@
+package intexpr
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"math/big"
+	"slices"
+)
+
+// Env holds the variables of a session, e.g. a REPL. a variable always has a
+// type: "x := 5" gives x the default type int, as in go.
+type Env struct {
+	vars map[string]Value
+}
+
+// NewEnv returns an empty environment.
+func NewEnv() *Env {
+	return &Env{vars: map[string]Value{}}
+}
+
+// Names returns the defined variable names in sorted order.
+func (env *Env) Names() []string {
+	var names []string
+	for name := range env.vars {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Lookup returns the current value of a variable.
+func (env *Env) Lookup(name string) (Value, bool) {
+	v, ok := env.vars[name]
+	return v, ok
+}
+
+// Eval parses and evaluates the expression src using the variables of env.
+func (env *Env) Eval(src string) (Value, error) {
+	e, err := Parse(src)
+	if err != nil {
+		return Value{}, err
+	}
+	return env.EvalExpr(e)
+}
+
+// EvalExpr evaluates an expression returned by Parse.
+func (env *Env) EvalExpr(e ast.Expr) (Value, error) {
+	// ParseExpr positions start at 1
+	ev := &evaluator{env: env, base: 1}
+	return ev.eval(e)
+}
+
+// stmtPrefix wraps a statement so go/parser can parse it.
+const stmtPrefix = "package p;func _(){"
+
+// Exec runs one line: either an expression or one of the statements
+//
+//	x := expr    var x T = expr    var x = expr    var x T
+//	x = expr     x += expr (any arithmetic operator)    x++    x--
+//
+// it returns the assigned variable name ("" for a plain expression) and the
+// value. the value keeps Exact and Events of the right-hand side so callers
+// can report wraparound that happened on the way.
+func (env *Env) Exec(line string) (name string, v Value, err error) {
+	e, exprErr := Parse(line)
+	if exprErr == nil {
+		v, err := env.EvalExpr(e)
+		return "", v, err
+	}
+
+	f, err := parser.ParseFile(token.NewFileSet(), "", stmtPrefix+line+"\n}", 0)
+	if err != nil {
+		return "", Value{}, exprErr
+	}
+	body := f.Decls[0].(*ast.FuncDecl).Body.List
+	if len(body) != 1 {
+		return "", Value{}, fmt.Errorf("one statement per line")
+	}
+	ev := &evaluator{env: env, base: 1 + len(stmtPrefix)}
+
+	switch s := body[0].(type) {
+	case *ast.AssignStmt:
+		return ev.assignStmt(s)
+	case *ast.IncDecStmt:
+		op := token.ADD
+		if s.Tok == token.DEC {
+			op = token.SUB
+		}
+		one := &ast.BasicLit{ValuePos: s.TokPos, Kind: token.INT, Value: "1"}
+		return ev.update(s.X, &ast.BinaryExpr{X: s.X, OpPos: s.TokPos, Op: op, Y: one})
+	case *ast.DeclStmt:
+		return ev.varDecl(s)
+	}
+	return "", Value{}, ev.errorf(body[0], "unsupported statement")
+}
+
+func (ev *evaluator) assignStmt(s *ast.AssignStmt) (string, Value, error) {
+	if len(s.Lhs) != 1 || len(s.Rhs) != 1 {
+		return "", Value{}, ev.errorf(s, "only single assignments are supported")
+	}
+	id, ok := s.Lhs[0].(*ast.Ident)
+	if !ok {
+		return "", Value{}, ev.errorf(s.Lhs[0], "cannot assign to %s", types.ExprString(s.Lhs[0]))
+	}
+
+	switch s.Tok {
+	case token.DEFINE:
+		v, err := ev.eval(s.Rhs[0])
+		if err != nil {
+			return "", Value{}, err
+		}
+		t := v.Type
+		if t == nil {
+			t = Int
+		}
+		v, err = ev.assign(s.Rhs[0], v, t, "variable declaration")
+		if err != nil {
+			return "", Value{}, err
+		}
+		ev.env.vars[id.Name] = v
+		return id.Name, v, nil
+	case token.ASSIGN:
+		return ev.update(id, s.Rhs[0])
+	}
+
+	// x op= y is x = x op y
+	ops := map[token.Token]token.Token{
+		token.ADD_ASSIGN: token.ADD, token.SUB_ASSIGN: token.SUB, token.MUL_ASSIGN: token.MUL,
+		token.QUO_ASSIGN: token.QUO, token.REM_ASSIGN: token.REM, token.AND_ASSIGN: token.AND,
+		token.OR_ASSIGN: token.OR, token.XOR_ASSIGN: token.XOR, token.SHL_ASSIGN: token.SHL,
+		token.SHR_ASSIGN: token.SHR, token.AND_NOT_ASSIGN: token.AND_NOT,
+	}
+	op, ok := ops[s.Tok]
+	if !ok {
+		return "", Value{}, ev.errorf(s, "unsupported assignment %s", s.Tok)
+	}
+	return ev.update(id, &ast.BinaryExpr{X: id, OpPos: s.TokPos, Op: op, Y: s.Rhs[0]})
+}
+
+// update assigns rhs to the existing variable lhs.
+func (ev *evaluator) update(lhs, rhs ast.Expr) (string, Value, error) {
+	id, ok := lhs.(*ast.Ident)
+	if !ok {
+		return "", Value{}, ev.errorf(lhs, "cannot assign to %s", types.ExprString(lhs))
+	}
+	old, ok := ev.env.vars[id.Name]
+	if !ok {
+		return "", Value{}, ev.errorf(id, "undefined: %s", id.Name)
+	}
+	v, err := ev.eval(rhs)
+	if err != nil {
+		return "", Value{}, err
+	}
+	v, err = ev.assign(rhs, v, old.Type, "assignment")
+	if err != nil {
+		return "", Value{}, err
+	}
+	ev.env.vars[id.Name] = v
+	return id.Name, v, nil
+}
+
+func (ev *evaluator) varDecl(s *ast.DeclStmt) (string, Value, error) {
+	gen, ok := s.Decl.(*ast.GenDecl)
+	if !ok || gen.Tok != token.VAR || len(gen.Specs) != 1 {
+		return "", Value{}, ev.errorf(s, "only var declarations are supported")
+	}
+	spec := gen.Specs[0].(*ast.ValueSpec)
+	if len(spec.Names) != 1 || len(spec.Values) > 1 {
+		return "", Value{}, ev.errorf(s, "declare one variable per line")
+	}
+	name := spec.Names[0].Name
+
+	var t *Type
+	if spec.Type != nil {
+		id, ok := spec.Type.(*ast.Ident)
+		if !ok || LookupType(id.Name) == nil {
+			return "", Value{}, ev.errorf(spec.Type, "%s is not an integer type", types.ExprString(spec.Type))
+		}
+		t = LookupType(id.Name)
+	}
+	if len(spec.Values) == 0 {
+		zero := new(big.Int)
+		v := Value{Type: t, Int: zero, Exact: zero}
+		ev.env.vars[name] = v
+		return name, v, nil
+	}
+
+	v, err := ev.eval(spec.Values[0])
+	if err != nil {
+		return "", Value{}, err
+	}
+	if t == nil {
+		t = v.Type
+		if t == nil {
+			t = Int
+		}
+	}
+	v, err = ev.assign(spec.Values[0], v, t, "variable declaration")
+	if err != nil {
+		return "", Value{}, err
+	}
+	ev.env.vars[name] = v
+	return name, v, nil
+}
+
+// assign checks go assignability of v to a variable of type t: untyped
+// constants must fit, typed values must have exactly that type.
+func (ev *evaluator) assign(n ast.Expr, v Value, t *Type, context string) (Value, error) {
+	if v.Type == nil {
+		if !t.Fits(v.Int) {
+			return Value{}, ev.errorf(n, "cannot use %s (untyped int constant %s) as %s value in %s (overflows)",
+				types.ExprString(n), v.Int, t, context)
+		}
+		v.Type = t
+		return v, nil
+	}
+	if v.Type != t {
+		return Value{}, ev.errorf(n, "cannot use %s (value of type %s) as %s value in %s",
+			types.ExprString(n), v.Type, t, context)
+	}
+	return v, nil
+}
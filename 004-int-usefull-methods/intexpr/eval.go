@@ -0,0 +1,292 @@
+package intexpr
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"math/big"
+	"strconv"
+)
+
+// maxShift keeps untyped shifts like 1 << 1e9 from eating all memory. go
+// itself limits constant shift counts to a few thousand bits.
+const maxShift = 10000
+
+// Error is a parse or evaluation error at a byte offset of the source.
+type Error struct {
+	Offset int
+	Msg    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("offset %d: %s", e.Offset, e.Msg)
+}
+
+// Parse parses src as a single go expression.
+func Parse(src string) (ast.Expr, error) {
+	return parser.ParseExpr(src)
+}
+
+// Eval parses and evaluates src without any variables.
+func Eval(src string) (Value, error) {
+	return NewEnv().Eval(src)
+}
+
+// EvalExpr evaluates an expression returned by Parse without any variables.
+func EvalExpr(e ast.Expr) (Value, error) {
+	return NewEnv().EvalExpr(e)
+}
+
+// evaluator walks one expression. base turns ast positions into byte offsets
+// of the source the caller passed in.
+type evaluator struct {
+	env  *Env
+	base int
+}
+
+func (ev *evaluator) errorf(n ast.Node, format string, args ...any) error {
+	return &Error{Offset: int(n.Pos()) - ev.base, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (ev *evaluator) eval(e ast.Expr) (Value, error) {
+	switch e := e.(type) {
+	case *ast.ParenExpr:
+		return ev.eval(e.X)
+	case *ast.BasicLit:
+		return ev.evalLit(e)
+	case *ast.UnaryExpr:
+		return ev.evalUnary(e)
+	case *ast.BinaryExpr:
+		return ev.evalBinary(e)
+	case *ast.CallExpr:
+		return ev.evalCall(e)
+	case *ast.SelectorExpr:
+		return ev.evalSelector(e)
+	case *ast.Ident:
+		if v, ok := ev.env.vars[e.Name]; ok {
+			v.Exact, v.Const, v.Events = v.Int, false, nil
+			return v, nil
+		}
+		if LookupType(e.Name) != nil {
+			return Value{}, ev.errorf(e, "%s (type) is not an expression", e.Name)
+		}
+		return Value{}, ev.errorf(e, "undefined: %s", e.Name)
+	}
+	return Value{}, ev.errorf(e, "unsupported expression %s", types.ExprString(e))
+}
+
+func (ev *evaluator) evalLit(e *ast.BasicLit) (Value, error) {
+	switch e.Kind {
+	case token.INT:
+		n, ok := new(big.Int).SetString(e.Value, 0)
+		if !ok {
+			return Value{}, ev.errorf(e, "invalid integer literal %s", e.Value)
+		}
+		return constant(n), nil
+	case token.CHAR:
+		r, _, _, err := strconv.UnquoteChar(e.Value[1:len(e.Value)-1], '\'')
+		if err != nil {
+			return Value{}, ev.errorf(e, "invalid rune literal %s", e.Value)
+		}
+		return constant(big.NewInt(int64(r))), nil
+	}
+	return Value{}, ev.errorf(e, "%s is not an integer", e.Value)
+}
+
+// evalSelector handles the integer limit constants of package math.
+func (ev *evaluator) evalSelector(e *ast.SelectorExpr) (Value, error) {
+	if pkg, ok := e.X.(*ast.Ident); ok && pkg.Name == "math" {
+		if n, ok := mathConst(e.Sel.Name); ok {
+			return constant(n), nil
+		}
+		return Value{}, ev.errorf(e.Sel, "undefined: math.%s (only the integer limits like math.MaxInt8 are known)", e.Sel.Name)
+	}
+	return Value{}, ev.errorf(e, "unsupported expression %s", types.ExprString(e))
+}
+
+// evalCall handles conversions like int8(x).
+func (ev *evaluator) evalCall(e *ast.CallExpr) (Value, error) {
+	id, ok := e.Fun.(*ast.Ident)
+	if !ok || LookupType(id.Name) == nil {
+		return Value{}, ev.errorf(e, "only integer conversions like int8(x) are supported")
+	}
+	if len(e.Args) != 1 {
+		return Value{}, ev.errorf(e, "conversion to %s needs exactly one argument", id.Name)
+	}
+	x, err := ev.eval(e.Args[0])
+	if err != nil {
+		return Value{}, err
+	}
+	return ev.convert(e, x, LookupType(id.Name))
+}
+
+// convert is a go conversion: untyped constants must fit, typed values are
+// truncated like int8(x) truncates a variable at run time.
+func (ev *evaluator) convert(e *ast.CallExpr, x Value, t *Type) (Value, error) {
+	if x.Type == nil && !t.Fits(x.Int) {
+		return Value{}, ev.errorf(e.Args[0], "cannot convert %s (untyped int constant) to type %s: constant overflows %s", x.Int, t, t)
+	}
+	v := Value{Type: t, Int: t.Wrap(x.Int), Exact: x.Exact, Const: x.Const, Events: x.Events}
+	if v.Int.Cmp(x.Int) != 0 {
+		v.Events = append(v.Events, Event{
+			Offset: int(e.Pos()) - ev.base, Expr: types.ExprString(e),
+			Kind: Truncation, Type: t, Want: x.Int, Got: v.Int,
+		})
+	}
+	return v, nil
+}
+
+func (ev *evaluator) evalUnary(e *ast.UnaryExpr) (Value, error) {
+	x, err := ev.eval(e.X)
+	if err != nil {
+		return Value{}, err
+	}
+	apply := func(a *big.Int) *big.Int {
+		r := new(big.Int)
+		switch e.Op {
+		case token.ADD:
+			r.Set(a)
+		case token.SUB:
+			r.Neg(a)
+		case token.XOR:
+			// ^x is -x-1 for signed and untyped values, "flip all bits of
+			// the type" for unsigned ones, the same thing after wrapping
+			if x.Type != nil && !x.Type.Signed {
+				r.Sub(x.Type.Max(), a)
+			} else {
+				r.Not(a)
+			}
+		}
+		return r
+	}
+	switch e.Op {
+	case token.ADD, token.SUB, token.XOR:
+	default:
+		return Value{}, ev.errorf(e, "operator %s not supported", e.Op)
+	}
+	return ev.result(e, x.Type, apply(x.Int), apply(x.Exact), x.Const, x.Events), nil
+}
+
+func (ev *evaluator) evalBinary(e *ast.BinaryExpr) (Value, error) {
+	x, err := ev.eval(e.X)
+	if err != nil {
+		return Value{}, err
+	}
+	y, err := ev.eval(e.Y)
+	if err != nil {
+		return Value{}, err
+	}
+	events := append(append([]Event(nil), x.Events...), y.Events...)
+	isConst := x.Const && y.Const
+
+	if e.Op == token.SHL || e.Op == token.SHR {
+		if y.Int.Sign() < 0 {
+			return Value{}, ev.errorf(e.Y, "invalid shift count %s (negative)", y.Int)
+		}
+		if !y.Int.IsInt64() || y.Int.Int64() > maxShift {
+			return Value{}, ev.errorf(e.Y, "shift count %s too large", y.Int)
+		}
+		// the result keeps the type of the left operand. big.Int Rsh rounds
+		// toward -inf, which is exactly an arithmetic shift for negatives
+		n := uint(y.Int.Int64())
+		sh := func(a *big.Int) *big.Int {
+			if e.Op == token.SHL {
+				return new(big.Int).Lsh(a, n)
+			}
+			return new(big.Int).Rsh(a, n)
+		}
+		return ev.result(e, x.Type, sh(x.Int), sh(x.Exact), isConst, events), nil
+	}
+
+	t, err := ev.unify(e, x, y)
+	if err != nil {
+		return Value{}, err
+	}
+	if (e.Op == token.QUO || e.Op == token.REM) && y.Int.Sign() == 0 {
+		if isConst {
+			return Value{}, ev.errorf(e.Y, "invalid operation: division by zero")
+		}
+		return Value{}, ev.errorf(e.Y, "runtime error: integer divide by zero")
+	}
+	apply := func(a, b *big.Int) *big.Int {
+		r := new(big.Int)
+		switch e.Op {
+		case token.ADD:
+			r.Add(a, b)
+		case token.SUB:
+			r.Sub(a, b)
+		case token.MUL:
+			r.Mul(a, b)
+		case token.QUO:
+			// go division truncates toward zero, like big.Int Quo
+			if b.Sign() != 0 {
+				r.Quo(a, b)
+			}
+		case token.REM:
+			if b.Sign() != 0 {
+				r.Rem(a, b)
+			}
+		case token.AND:
+			r.And(a, b)
+		case token.OR:
+			r.Or(a, b)
+		case token.XOR:
+			r.Xor(a, b)
+		case token.AND_NOT:
+			r.AndNot(a, b)
+		}
+		return r
+	}
+	switch e.Op {
+	case token.ADD, token.SUB, token.MUL, token.QUO, token.REM,
+		token.AND, token.OR, token.XOR, token.AND_NOT:
+	default:
+		return Value{}, ev.errorf(e, "operator %s not supported", e.Op)
+	}
+	return ev.result(e, t, apply(x.Int, y.Int), apply(x.Exact, y.Exact), isConst, events), nil
+}
+
+// unify picks the operand type of a binary operation: two typed operands must
+// match, an untyped constant takes the type of the other side.
+func (ev *evaluator) unify(e *ast.BinaryExpr, x, y Value) (*Type, error) {
+	switch {
+	case x.Type == nil && y.Type == nil:
+		return nil, nil
+	case x.Type == nil:
+		if !y.Type.Fits(x.Int) {
+			return nil, ev.errorf(e.X, "%s (untyped int constant) overflows %s", x.Int, y.Type)
+		}
+		return y.Type, nil
+	case y.Type == nil:
+		if !x.Type.Fits(y.Int) {
+			return nil, ev.errorf(e.Y, "%s (untyped int constant) overflows %s", y.Int, x.Type)
+		}
+		return x.Type, nil
+	case x.Type != y.Type:
+		return nil, ev.errorf(e, "invalid operation: mismatched types %s and %s", x.Type, y.Type)
+	}
+	return x.Type, nil
+}
+
+// result wraps the result of an operation to its type and records a
+// Wraparound event when the operation itself did not fit.
+func (ev *evaluator) result(e ast.Expr, t *Type, r, exact *big.Int, isConst bool, events []Event) Value {
+	v := Value{Type: t, Int: r, Exact: exact, Const: isConst, Events: events}
+	if t == nil {
+		return v
+	}
+	v.Int = t.Wrap(r)
+	if v.Int.Cmp(r) != 0 {
+		v.Events = append(v.Events, Event{
+			Offset: int(e.Pos()) - ev.base, Expr: types.ExprString(e),
+			Kind: Wraparound, Type: t, Want: r, Got: v.Int,
+		})
+	}
+	return v
+}
+
+func constant(n *big.Int) Value {
+	return Value{Int: n, Exact: n, Const: true}
+}
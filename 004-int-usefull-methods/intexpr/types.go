@@ -0,0 +1,178 @@
+// Package intexpr parses and evaluates Go integer expressions such as
+// "5 &^ 3" or "int8(-7) >> 2" with Go's typing rules: untyped constants are
+// exact, typed values have a fixed width and wrap around like a variable of
+// that type would at run time.
+package intexpr
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+// Type is a sized integer type.
+type Type struct {
+	Name   string
+	Bits   int
+	Signed bool
+}
+
+// the predeclared integer types. byte and rune are aliases, so they resolve
+// to uint8 and int32 just like in go error messages
+var (
+	Int8    = &Type{"int8", 8, true}
+	Int16   = &Type{"int16", 16, true}
+	Int32   = &Type{"int32", 32, true}
+	Int64   = &Type{"int64", 64, true}
+	Int     = &Type{"int", strconv.IntSize, true}
+	Uint8   = &Type{"uint8", 8, false}
+	Uint16  = &Type{"uint16", 16, false}
+	Uint32  = &Type{"uint32", 32, false}
+	Uint64  = &Type{"uint64", 64, false}
+	Uint    = &Type{"uint", strconv.IntSize, false}
+	Uintptr = &Type{"uintptr", strconv.IntSize, false}
+)
+
+var typesByName = map[string]*Type{
+	"int8": Int8, "int16": Int16, "int32": Int32, "int64": Int64, "int": Int,
+	"uint8": Uint8, "uint16": Uint16, "uint32": Uint32, "uint64": Uint64, "uint": Uint,
+	"uintptr": Uintptr, "byte": Uint8, "rune": Int32,
+}
+
+// LookupType returns the integer type with the given name, or nil.
+func LookupType(name string) *Type { return typesByName[name] }
+
+func (t *Type) String() string { return t.Name }
+
+// Min returns the smallest value of the type.
+func (t *Type) Min() *big.Int {
+	if !t.Signed {
+		return new(big.Int)
+	}
+	return new(big.Int).Lsh(big.NewInt(-1), uint(t.Bits-1))
+}
+
+// Max returns the largest value of the type.
+func (t *Type) Max() *big.Int {
+	n := t.Bits
+	if t.Signed {
+		n--
+	}
+	m := new(big.Int).Lsh(big.NewInt(1), uint(n))
+	return m.Sub(m, big.NewInt(1))
+}
+
+// Fits reports whether v is representable in the type.
+func (t *Type) Fits(v *big.Int) bool {
+	return v.Cmp(t.Min()) >= 0 && v.Cmp(t.Max()) <= 0
+}
+
+// Wrap truncates v to the type's width the way the hardware does: keep the
+// low Bits bits and read them back as two's complement if the type is signed.
+func (t *Type) Wrap(v *big.Int) *big.Int {
+	mod := new(big.Int).Lsh(big.NewInt(1), uint(t.Bits))
+	w := new(big.Int).Mod(v, mod) // Mod is euclidean, always >= 0
+	if t.Signed && w.Cmp(t.Max()) > 0 {
+		w.Sub(w, mod)
+	}
+	return w
+}
+
+// Value is the result of an expression. Type is nil for an untyped constant.
+type Value struct {
+	Type *Type
+	Int  *big.Int
+
+	// Exact is the result with unlimited precision: the same expression
+	// evaluated as if no operation or conversion ever wrapped.
+	Exact *big.Int
+	// Const reports that the value uses no variables, so the go compiler
+	// would evaluate it at compile time and reject any overflow.
+	Const bool
+	// Events lists every wraparound and truncation, innermost first.
+	Events []Event
+}
+
+// EventKind says how a value lost information.
+type EventKind int
+
+const (
+	// Wraparound is an arithmetic or shift result that did not fit its type.
+	Wraparound EventKind = iota
+	// Truncation is a conversion to a type that cannot hold the value.
+	Truncation
+)
+
+func (k EventKind) String() string {
+	if k == Truncation {
+		return "truncation"
+	}
+	return "wraparound"
+}
+
+// Event is one operation whose exact result did not fit its type.
+type Event struct {
+	Offset int    // byte offset of the operation in the source
+	Expr   string // the operation, e.g. "x + 1"
+	Kind   EventKind
+	Type   *Type
+	Want   *big.Int // the exact result of this operation
+	Got    *big.Int // what the type could hold
+}
+
+func (e Event) String() string {
+	return fmt.Sprintf("%s: %s = %s does not fit %s [%s, %s], got %s",
+		e.Kind, e.Expr, e.Want, e.Type, e.Type.Min(), e.Type.Max(), e.Got)
+}
+
+// TypeName returns the go name of the value's type, "untyped int" for
+// constants.
+func (v Value) TypeName() string {
+	if v.Type == nil {
+		return "untyped int"
+	}
+	return v.Type.Name
+}
+
+// Pattern returns the low width bits of v in two's complement.
+func (v Value) Pattern(width int) uint64 {
+	mod := new(big.Int).Lsh(big.NewInt(1), uint(width))
+	return new(big.Int).Mod(v.Int, mod).Uint64()
+}
+
+func (v Value) String() string {
+	return fmt.Sprintf("%s (%s)", v.Int, v.TypeName())
+}
+
+// mathConst returns the integer limit constants of package math.
+func mathConst(name string) (*big.Int, bool) {
+	var t *Type
+	switch name {
+	case "MaxInt8", "MinInt8":
+		t = Int8
+	case "MaxInt16", "MinInt16":
+		t = Int16
+	case "MaxInt32", "MinInt32":
+		t = Int32
+	case "MaxInt64", "MinInt64":
+		t = Int64
+	case "MaxInt", "MinInt":
+		t = Int
+	case "MaxUint8":
+		t = Uint8
+	case "MaxUint16":
+		t = Uint16
+	case "MaxUint32":
+		t = Uint32
+	case "MaxUint64":
+		t = Uint64
+	case "MaxUint":
+		t = Uint
+	default:
+		return nil, false
+	}
+	if name[:3] == "Min" {
+		return t.Min(), true
+	}
+	return t.Max(), true
+}
@@ -0,0 +1,151 @@
+// Package intparse parses integers the way people write them in config files.
+//
+// strconv.ParseInt(s, 0, 64) understands Go literal prefixes and underscores,
+// but rejects "1,234,567", "64KiB" or "-2k". this package accepts:
+//
+//	1_000_000  0x1F  0o755  0755  0b1010     go integer literal syntax
+//	1,234,567                                thousands separators (decimal only)
+//	2k  -2k  1.5GB  10 MB                    SI units: k K M G T P E (powers of 1000)
+//	64KiB  4Mi  1.5GiB                       IEC units: Ki Mi Gi Ti Pi Ei (powers of 1024)
+//
+// a trailing "B" after a unit (or alone) is allowed and means nothing. a
+// decimal fraction is allowed as long as the scaled result is a whole number:
+// "1.5k" is 1500 but "1.5" and "1.0005k" fail with ErrInexact.
+//
+// errors are *NumError values that wrap strconv.ErrSyntax or strconv.ErrRange
+// like *strconv.NumError does, and on ErrRange the returned value is clamped
+// to the limit of the requested size, again like strconv.
+package intparse
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+)
+
+// ErrInexact means a value with a fraction does not scale to a whole number,
+// like "1.5" or "0.0001k".
+var ErrInexact = errors.New("value is not a whole number")
+
+// NumError records a failed conversion. it has the same shape as
+// *strconv.NumError plus the byte offset where the input went wrong.
+type NumError struct {
+	Func   string // the failing function (ParseInt, ParseUint)
+	Num    string // the input
+	Offset int    // byte offset of the offending character, or of the number for range errors
+	Err    error  // strconv.ErrSyntax, strconv.ErrRange or ErrInexact
+}
+
+func (e *NumError) Error() string {
+	return "intparse." + e.Func + ": parsing " + strconv.Quote(e.Num) + ": " + e.Err.Error() + " at offset " + strconv.Itoa(e.Offset)
+}
+
+func (e *NumError) Unwrap() error { return e.Err }
+
+// Parser holds the accepted input forms. the zero Parser accepts only Go
+// literal syntax plus units. use Default for the documented behaviour.
+type Parser struct {
+	// Separators lists the characters accepted between groups of three
+	// decimal digits, e.g. "," or ",'". '_' is always accepted per Go rules.
+	Separators string
+
+	// NoUnits rejects SI and IEC unit suffixes.
+	NoUnits bool
+}
+
+// Default accepts ',' as thousands separator and both unit families.
+var Default = &Parser{Separators: ","}
+
+// ParseInt parses s with the Default parser into a signed integer of bitSize
+// bits (0 means int). see the package doc for accepted forms.
+func ParseInt(s string, bitSize int) (int64, error) {
+	return Default.ParseInt(s, bitSize)
+}
+
+// ParseUint is like ParseInt for unsigned integers. a sign is not allowed.
+func ParseUint(s string, bitSize int) (uint64, error) {
+	return Default.ParseUint(s, bitSize)
+}
+
+// Integer is the set of integer types Parse can return.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Parse parses s with the Default parser into T, choosing the size and
+// signedness from the type: Parse[uint16]("64Ki") fails with ErrRange.
+func Parse[T Integer](s string) (T, error) {
+	bitSize := bitsOf[T]()
+	var zero T
+	if zero-1 < 0 {
+		n, err := Default.ParseInt(s, bitSize)
+		return T(n), err
+	}
+	n, err := Default.ParseUint(s, bitSize)
+	return T(n), err
+}
+
+// bitsOf returns the width of T by finding the highest bit that survives.
+func bitsOf[T Integer]() int {
+	var one T = 1
+	n := 0
+	for v := one; v != 0; v <<= 1 {
+		n++
+	}
+	return n
+}
+
+// ParseInt parses s into a signed integer of bitSize bits (0 means int).
+func (p *Parser) ParseInt(s string, bitSize int) (int64, error) {
+	const fn = "ParseInt"
+	bitSize = checkBitSize(fn, bitSize)
+	v, err := p.parse(fn, s, true)
+	if err != nil {
+		return 0, err
+	}
+	min := new(big.Int).Lsh(big.NewInt(-1), uint(bitSize-1))
+	max := new(big.Int).Sub(new(big.Int).Neg(min), big.NewInt(1))
+	switch {
+	case v.Cmp(max) > 0:
+		return max.Int64(), rangeError(fn, s)
+	case v.Cmp(min) < 0:
+		return min.Int64(), rangeError(fn, s)
+	}
+	return v.Int64(), nil
+}
+
+// ParseUint parses s into an unsigned integer of bitSize bits (0 means uint).
+func (p *Parser) ParseUint(s string, bitSize int) (uint64, error) {
+	const fn = "ParseUint"
+	bitSize = checkBitSize(fn, bitSize)
+	v, err := p.parse(fn, s, false)
+	if err != nil {
+		return 0, err
+	}
+	max := new(big.Int).Lsh(big.NewInt(1), uint(bitSize))
+	max.Sub(max, big.NewInt(1))
+	if v.Cmp(max) > 0 {
+		return max.Uint64(), rangeError(fn, s)
+	}
+	return v.Uint64(), nil
+}
+
+func checkBitSize(fn string, bitSize int) int {
+	if bitSize == 0 {
+		return strconv.IntSize
+	}
+	if bitSize < 0 || bitSize > 64 {
+		panic("intparse." + fn + ": invalid bit size " + strconv.Itoa(bitSize))
+	}
+	return bitSize
+}
+
+// rangeError points at the number, after any sign.
+func rangeError(fn, s string) *NumError {
+	off := 0
+	if s != "" && (s[0] == '+' || s[0] == '-') {
+		off = 1
+	}
+	return &NumError{Func: fn, Num: s, Offset: off, Err: strconv.ErrRange}
+}
@@ -0,0 +1,172 @@
+package intparse
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// unit is a suffix and the power it scales by.
+type unit struct {
+	name string
+	mult *big.Int
+}
+
+// units is ordered so that longer names are tried first ("Ki" before "K").
+var units = func() []unit {
+	pow := func(base, exp int64) *big.Int {
+		return new(big.Int).Exp(big.NewInt(base), big.NewInt(exp), nil)
+	}
+	var us []unit
+	for i, p := range []string{"K", "M", "G", "T", "P", "E"} {
+		us = append(us, unit{p + "i", pow(1024, int64(i+1))})
+	}
+	us = append(us, unit{"k", pow(1000, 1)})
+	for i, p := range []string{"K", "M", "G", "T", "P", "E"} {
+		us = append(us, unit{p, pow(1000, int64(i+1))})
+	}
+	return us
+}()
+
+// parse turns s into an exact big.Int, leaving the range check to the caller.
+func (p *Parser) parse(fn, s string, signed bool) (*big.Int, error) {
+	fail := func(off int, err error) (*big.Int, error) {
+		return nil, &NumError{Func: fn, Num: s, Offset: off, Err: err}
+	}
+	syntax := func(off int) (*big.Int, error) { return fail(off, strconv.ErrSyntax) }
+
+	i := 0
+	neg := false
+	if i < len(s) && (s[i] == '+' || s[i] == '-') {
+		if !signed {
+			return syntax(i)
+		}
+		neg = s[i] == '-'
+		i++
+	}
+
+	// base prefix, as in go: 0x 0o 0b, or a leading 0 for legacy octal
+	base := 10
+	prefixed := false
+	if i+1 < len(s) && s[i] == '0' {
+		switch lower(s[i+1]) {
+		case 'x':
+			base, prefixed = 16, true
+		case 'o':
+			base, prefixed = 8, true
+		case 'b':
+			base, prefixed = 2, true
+		default:
+			if isDigit(s[i+1]) || s[i+1] == '_' {
+				base = 8
+			}
+		}
+	}
+	if prefixed {
+		i += 2
+	}
+
+	// integer digits. '_' follows go rules, the thousands separators must
+	// split decimal digits into groups of three
+	var digits []byte
+	var sepUsed byte
+	groupLen := -1 // digits since the last thousands separator
+digits:
+	for ; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '_':
+			if sepUsed != 0 && sepUsed != '_' || !(len(digits) > 0 || prefixed) ||
+				s[i-1] == '_' || i+1 >= len(s) || digitValue(s[i+1]) >= base {
+				return syntax(i)
+			}
+			sepUsed = '_'
+		case base == 10 && strings.IndexByte(p.Separators, c) >= 0:
+			if sepUsed != 0 && sepUsed != c || len(digits) == 0 ||
+				groupLen < 0 && len(digits) > 3 || groupLen >= 0 && groupLen != 3 {
+				return syntax(i)
+			}
+			sepUsed, groupLen = c, 0
+		case digitValue(c) < base:
+			digits = append(digits, c)
+			if groupLen >= 0 {
+				groupLen++
+			}
+		case base != 10:
+			return syntax(i)
+		default:
+			// the start of a fraction or a unit
+			break digits
+		}
+	}
+	if len(digits) == 0 || groupLen >= 0 && groupLen != 3 {
+		return syntax(i)
+	}
+
+	// optional fraction, decimal only
+	var frac []byte
+	fracStart := -1
+	if base == 10 && i < len(s) && s[i] == '.' {
+		fracStart = i
+		for i++; i < len(s) && isDigit(s[i]); i++ {
+			frac = append(frac, s[i])
+		}
+		if len(frac) == 0 {
+			return syntax(i)
+		}
+	}
+
+	// optional unit, separated by at most one space, and an optional "B"
+	mult := big.NewInt(1)
+	rest := s[i:]
+	if !p.NoUnits && base == 10 && rest != "" {
+		r := strings.TrimPrefix(rest, " ")
+		matched := false
+		for _, u := range units {
+			if strings.HasPrefix(r, u.name) {
+				mult, matched = u.mult, true
+				r = r[len(u.name):]
+				break
+			}
+		}
+		if strings.HasPrefix(r, "B") {
+			r, matched = r[1:], true
+		}
+		if matched {
+			rest = r
+		}
+	}
+	if rest != "" {
+		return syntax(len(s) - len(rest))
+	}
+
+	v, _ := new(big.Int).SetString(string(digits)+string(frac), base)
+	v.Mul(v, mult)
+	if len(frac) > 0 {
+		scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(len(frac))), nil)
+		var rem big.Int
+		v.QuoRem(v, scale, &rem)
+		if rem.Sign() != 0 {
+			return fail(fracStart, ErrInexact)
+		}
+	}
+	if neg {
+		v.Neg(v)
+	}
+	return v, nil
+}
+
+func isDigit(c byte) bool { return '0' <= c && c <= '9' }
+
+func lower(c byte) byte { return c | 0x20 }
+
+// digitValue returns the value of c as a hex digit, or 99 if it is not one.
+func digitValue(c byte) int {
+	switch {
+	case isDigit(c):
+		return int(c - '0')
+	case 'a' <= lower(c) && lower(c) <= 'f':
+		return int(lower(c)-'a') + 10
+	}
+	return 99
+}
@@ -57,6 +57,9 @@ func main() {
 
 	fmt.Println("============================ BITWISE OPERATORS ===============================")
 
+	// TIP: the tables below are drawn by hand, to draw them for any expression run
+	// go run ./cmd/bits '5 &^ 3'   or   go run ./cmd/bits -x 'int8(-7) >> 2'
+
 	// Declare two integer numbers
 	// num1 in binary  = 0101 (5)
 	// num2 in binary  = 0011 (3)
@@ -129,6 +132,10 @@ func main() {
 
 	// -------------------- Common Math Functions --------------------
 	// NOTE: Most math functions work with float64 only
+	// float64 holds integers exactly only up to 2^53, so math.Pow and math.Sqrt
+	// give wrong answers for big integers. exact integer versions (gcd, lcm,
+	// modular pow/inverse, isqrt, log2/log10, primality, factorization) are
+	// in the ./numtheory package
 
 	// Absolute value
 	// Converts negative number to positive
@@ -192,6 +199,10 @@ func main() {
 	stringBase16 := strconv.FormatInt(42, 16)
 	fmt.Println("FormatInt (base 16):", stringBase16)
 
+	// NOTE: FormatInt only supports base 2 to 36 with the digits 0-9a-z
+	// for base 62, Base58, Crockford Base32, custom alphabets, padding and
+	// grouping like 0xdead_beef see the ./baseconv package
+
 	// -------------------- String to Int --------------------
 
 	// Convert string to int
@@ -216,6 +227,8 @@ func main() {
 	// FormatInt works with int64
 	// ParseInt returns int64
 	// Always handle error when converting string → number
+	// ParseInt rejects "1_000_000" with base 10, "1,234,567" and "64KiB"
+	// for config-file style input with units see the ./intparse package
 
 	fmt.Println("============================ TYPE CONVERSION ===============================")
 	// 7. type conversation
@@ -259,6 +272,8 @@ func main() {
 	// 	Important Notes (Must Remember)
 	// Go does NOT allow implicit type conversion
 	// Explicit conversion may cause overflow
+	// try it interactively: go run ./cmd/intrepl
+	// then type int8(127) + 1, uint8(0) - 1 or math.MaxInt64 + 1
 
 }
 
@@ -0,0 +1,175 @@
+// Package numtheory has exact integer versions of the math the lesson does
+// with float64: math.Pow, math.Sqrt and math.Log2 only have 53 bits of
+// precision, so math.Sqrt(float64(1<<62-1)) or math.Pow(3, 40) are silently
+// wrong. everything here works on uint64 without ever overflowing: products
+// are done in 128 bits with math/bits.Mul64 and reduced with bits.Rem64.
+package numtheory
+
+import (
+	"math"
+	"math/bits"
+)
+
+// GCD returns the greatest common divisor of a and b. GCD(0, 0) is 0.
+func GCD(a, b uint64) uint64 {
+	// binary gcd (stein's algorithm): only shifts and subtractions
+	if a == 0 {
+		return b
+	}
+	if b == 0 {
+		return a
+	}
+	shift := bits.TrailingZeros64(a | b)
+	a >>= bits.TrailingZeros64(a)
+	for b != 0 {
+		b >>= bits.TrailingZeros64(b)
+		if a > b {
+			a, b = b, a
+		}
+		b -= a
+	}
+	return a << shift
+}
+
+// LCM returns the least common multiple of a and b, and overflow = true when
+// it does not fit in a uint64. LCM(0, x) is 0.
+func LCM(a, b uint64) (lcm uint64, overflow bool) {
+	if a == 0 || b == 0 {
+		return 0, false
+	}
+	hi, lo := bits.Mul64(a/GCD(a, b), b)
+	return lo, hi != 0
+}
+
+// ExtendedGCD returns g = gcd(a, b) and the bezout coefficients x, y with
+// a*x + b*y = g. g is never negative, with one exception: a gcd of 2^63,
+// which only math.MinInt64 and 0 have, does not fit in an int64, so
+// ExtendedGCD(math.MinInt64, 0) is g = math.MinInt64, x = 1, y = 0.
+func ExtendedGCD(a, b int64) (g, x, y int64) {
+	oldR, r := a, b
+	oldS, s := int64(1), int64(0)
+	oldT, t := int64(0), int64(1)
+	for r != 0 {
+		q := oldR / r
+		oldR, r = r, oldR-q*r
+		oldS, s = s, oldS-q*s
+		oldT, t = t, oldT-q*t
+	}
+	if oldR < 0 && oldR != math.MinInt64 { // -MinInt64 overflows
+		oldR, oldS, oldT = -oldR, -oldS, -oldT
+	}
+	return oldR, oldS, oldT
+}
+
+// AddMod returns (a + b) mod m without overflowing. it panics if m is 0.
+func AddMod(a, b, m uint64) uint64 {
+	a, b = a%m, b%m
+	sum, carry := bits.Add64(a, b, 0)
+	if carry != 0 || sum >= m {
+		sum -= m
+	}
+	return sum
+}
+
+// SubMod returns (a - b) mod m in [0, m). it panics if m is 0.
+func SubMod(a, b, m uint64) uint64 {
+	a, b = a%m, b%m
+	if a >= b {
+		return a - b
+	}
+	return m - (b - a)
+}
+
+// MulMod returns (a * b) mod m using the full 128-bit product. it panics if
+// m is 0.
+func MulMod(a, b, m uint64) uint64 {
+	hi, lo := bits.Mul64(a%m, b%m)
+	return bits.Rem64(hi, lo, m)
+}
+
+// PowMod returns base^exp mod m by square and multiply. PowMod(x, 0, m) is
+// 1 mod m. it panics if m is 0.
+func PowMod(base, exp, m uint64) uint64 {
+	result := 1 % m
+	base %= m
+	for exp > 0 {
+		if exp&1 == 1 {
+			result = MulMod(result, base, m)
+		}
+		base = MulMod(base, base, m)
+		exp >>= 1
+	}
+	return result
+}
+
+// InverseMod returns x with a*x = 1 (mod m), and ok = false when a and m are
+// not coprime so no inverse exists.
+func InverseMod(a, m uint64) (x uint64, ok bool) {
+	if m == 0 {
+		return 0, false
+	}
+	if m == 1 {
+		return 0, true
+	}
+	// extended euclid keeping the coefficient mod m, so it never needs
+	// more than 64 bits even when m is above MaxInt64
+	oldR, r := a%m, m
+	oldS, s := uint64(1), uint64(0)
+	for r != 0 {
+		q := oldR / r
+		oldR, r = r, oldR-q*r
+		oldS, s = s, SubMod(oldS, MulMod(q, s, m), m)
+	}
+	if oldR != 1 {
+		return 0, false
+	}
+	return oldS, true
+}
+
+// Sqrt returns the integer square root of n: the largest r with r*r <= n.
+func Sqrt(n uint64) uint64 {
+	if n < 2 {
+		return n
+	}
+	// newton's method from a power of two above the root converges from
+	// above and stops as soon as it stops decreasing
+	x := uint64(1) << ((bits.Len64(n) + 1) / 2)
+	for {
+		y := (x + n/x) / 2
+		if y >= x {
+			return x
+		}
+		x = y
+	}
+}
+
+// Log2 returns floor(log2(n)), the index of the highest set bit. Log2(0) is
+// -1 since no bit is set.
+func Log2(n uint64) int {
+	return bits.Len64(n) - 1
+}
+
+// pow10 holds 10^0 through 10^19, every power of ten that fits a uint64.
+var pow10 = func() [20]uint64 {
+	var p [20]uint64
+	p[0] = 1
+	for i := 1; i < len(p); i++ {
+		p[i] = p[i-1] * 10
+	}
+	return p
+}()
+
+// Log10 returns floor(log10(n)), one less than the number of decimal digits.
+// Log10(0) is -1.
+func Log10(n uint64) int {
+	if n == 0 {
+		return -1
+	}
+	// log10(n) = log2(n) * log10(2) and 1233/4096 is log10(2) rounded up,
+	// so the estimate is exact or one too big
+	d := bits.Len64(n) * 1233 >> 12
+	if n < pow10[d] {
+		d--
+	}
+	return d
+}
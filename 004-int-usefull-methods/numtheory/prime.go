@@ -0,0 +1,140 @@
+package numtheory
+
+import (
+	"math/bits"
+	"slices"
+)
+
+// millerRabinBases are the first 12 primes. testing against all of them is
+// deterministic (no false positives) for every n < 318665857834031151167461,
+// about 3.2 * 10^23, which covers all of uint64.
+var millerRabinBases = [...]uint64{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37}
+
+// IsPrime reports whether n is prime using deterministic Miller-Rabin.
+func IsPrime(n uint64) bool {
+	if n < 2 {
+		return false
+	}
+	for _, p := range millerRabinBases {
+		if n%p == 0 {
+			return n == p
+		}
+	}
+	if n < 41*41 {
+		return true
+	}
+
+	// n-1 = d * 2^s with d odd
+	d := n - 1
+	s := bits.TrailingZeros64(d)
+	d >>= s
+	for _, a := range millerRabinBases {
+		if !millerRabinRound(n, d, s, a) {
+			return false
+		}
+	}
+	return true
+}
+
+// millerRabinRound reports whether n passes the strong probable prime test
+// to base a.
+func millerRabinRound(n, d uint64, s int, a uint64) bool {
+	x := PowMod(a, d, n)
+	if x == 1 || x == n-1 {
+		return true
+	}
+	for range s - 1 {
+		x = MulMod(x, x, n)
+		if x == n-1 {
+			return true
+		}
+	}
+	return false
+}
+
+// Factor is a prime and how many times it divides the number.
+type Factor struct {
+	Prime uint64
+	Exp   int
+}
+
+// Factorize returns the prime factorization of n in increasing order of
+// primes: Factorize(360) is [{2 3} {3 2} {5 1}]. 0 and 1 have no factors.
+func Factorize(n uint64) []Factor {
+	if n < 2 {
+		return nil
+	}
+	var primes []uint64
+	// trial division gets the small factors fast, pollard's rho splits
+	// whatever is left, which only has prime factors above 1000
+	for _, p := range []uint64{2, 3, 5} {
+		for n%p == 0 {
+			primes = append(primes, p)
+			n /= p
+		}
+	}
+	for p, i := uint64(7), 0; p <= 1000 && p*p <= n; p, i = p+wheel[i], (i+1)%len(wheel) {
+		for n%p == 0 {
+			primes = append(primes, p)
+			n /= p
+		}
+	}
+	if n > 1 {
+		primes = splitLarge(n, primes)
+	}
+	slices.Sort(primes)
+
+	var fs []Factor
+	for _, p := range primes {
+		if len(fs) > 0 && fs[len(fs)-1].Prime == p {
+			fs[len(fs)-1].Exp++
+		} else {
+			fs = append(fs, Factor{Prime: p, Exp: 1})
+		}
+	}
+	return fs
+}
+
+// wheel skips multiples of 2, 3 and 5 starting from 7.
+var wheel = [...]uint64{4, 2, 4, 2, 4, 6, 2, 6}
+
+// splitLarge appends the prime factors of n (which has no factor below 1000)
+// to primes.
+func splitLarge(n uint64, primes []uint64) []uint64 {
+	if n == 1 {
+		return primes
+	}
+	if IsPrime(n) {
+		return append(primes, n)
+	}
+	if r := Sqrt(n); r*r == n {
+		return splitLarge(r, splitLarge(r, primes))
+	}
+	d := pollardRho(n)
+	return splitLarge(n/d, splitLarge(d, primes))
+}
+
+// pollardRho returns a non-trivial divisor of the composite n using brent's
+// cycle detection, retrying with a new constant when a walk fails.
+func pollardRho(n uint64) uint64 {
+	for c := uint64(1); ; c++ {
+		f := func(x uint64) uint64 { return AddMod(MulMod(x, x, n), c, n) }
+		x, y, d := uint64(2), uint64(2), uint64(1)
+		power, lam := 1, 1
+		for d == 1 {
+			if power == lam {
+				x, power, lam = y, power*2, 0
+			}
+			y = f(y)
+			lam++
+			if x > y {
+				d = GCD(x-y, n)
+			} else {
+				d = GCD(y-x, n)
+			}
+		}
+		if d != n {
+			return d
+		}
+	}
+}
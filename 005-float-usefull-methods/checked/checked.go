@@ -0,0 +1,267 @@
+// Package checked is float64 arithmetic that reports what went wrong, in the
+// spirit of the lesson's safeDivide but for every operation.
+//
+// IEEE-754 never stops a computation: 0/0 is NaN, 1e308*10 is +Inf, and the
+// NaN then flows through every later step, so by the time a report shows NaN
+// nobody knows where it came from. each function here returns the IEEE
+// result and an *Error classifying the first problem:
+//
+//	ErrDomain     the input is outside the function's domain: Sqrt(-1), Log(-1)
+//	ErrInvalid    an invalid operation made NaN: 0/0, Inf-Inf, 0*Inf
+//	ErrDivByZero  finite / 0, or a pole like Log(0): the result is ±Inf
+//	ErrOverflow   finite inputs, infinite result: 1e308 * 10
+//	ErrUnderflow  nonzero exact result rounded to zero: 1e-200 * 1e-200
+//	ErrNaNInput   an operand already was NaN, it came from somewhere else
+//	ErrInexact    a conversion changed the value: int64 above 2^53 to float64
+//
+// the F64 wrapper chains operations and keeps the first error, so a whole
+// formula is checked once at the end. Trace records the first NaN even when
+// callers ignore the errors.
+package checked
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrDomain    = errors.New("argument outside the domain")
+	ErrInvalid   = errors.New("invalid operation")
+	ErrDivByZero = errors.New("division by zero")
+	ErrOverflow  = errors.New("overflow")
+	ErrUnderflow = errors.New("underflow to zero")
+	ErrNaNInput  = errors.New("NaN operand")
+	ErrInexact   = errors.New("inexact conversion")
+)
+
+// Error describes an operation that produced a NaN, an infinity or a lost
+// value. Err is one of the sentinel errors above.
+type Error struct {
+	Op     string    // "Div", "Sqrt", "ToInt64", ...
+	Args   []float64 // the operands
+	Input  string    // the operand of FromInt64, which has no float64 form
+	Result float64   // what IEEE-754 arithmetic returned
+	Err    error
+	Caller string // file:line of the call into this package
+	Stack  string // the whole call stack, only recorded by Trace
+}
+
+func (e *Error) Error() string {
+	args := make([]string, len(e.Args))
+	for i, a := range e.Args {
+		args[i] = strconv.FormatFloat(a, 'g', -1, 64)
+	}
+	if e.Input != "" {
+		args = []string{e.Input}
+	}
+	s := fmt.Sprintf("checked: %s(%s) = %v: %v", e.Op, strings.Join(args, ", "), e.Result, e.Err)
+	if e.Caller != "" {
+		s += " at " + e.Caller
+	}
+	return s
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// check classifies the result r of op on args and returns an *Error or nil.
+// nonzero reports whether the exact result is known to be nonzero, so a
+// zero r is an underflow. skip is the number of frames between check and
+// the caller outside the package.
+func check(op string, r float64, nonzero bool, skip int, args ...float64) *Error {
+	var err error
+	finite := true
+	for _, a := range args {
+		if math.IsNaN(a) {
+			err = ErrNaNInput
+			break
+		}
+		if math.IsInf(a, 0) {
+			finite = false
+		}
+	}
+	if err == nil {
+		switch {
+		case math.IsNaN(r):
+			err = ErrInvalid
+			if domainOps[op] {
+				err = ErrDomain
+			}
+		case math.IsInf(r, 0) && finite:
+			err = ErrOverflow
+			if poleOps[op] && isPole(op, args) {
+				err = ErrDivByZero
+			}
+		case r == 0 && nonzero:
+			err = ErrUnderflow
+		}
+	}
+	if err == nil {
+		return nil
+	}
+	e := &Error{Op: op, Args: args, Result: r, Err: err}
+	if _, file, line, ok := runtime.Caller(skip + 1); ok {
+		e.Caller = shortFile(file) + ":" + strconv.Itoa(line)
+	}
+	traceError(e, skip+1)
+	return e
+}
+
+// domainOps return NaN for finite arguments outside their domain, as
+// opposed to arithmetic, where NaN means an invalid operation.
+var domainOps = map[string]bool{
+	"Sqrt": true, "Log": true, "Log2": true, "Log10": true, "Pow": true,
+}
+
+// poleOps can return ±Inf from finite arguments without overflowing.
+var poleOps = map[string]bool{
+	"Div": true, "Log": true, "Log2": true, "Log10": true, "Pow": true,
+}
+
+// isPole reports whether an infinite result comes from dividing by zero
+// rather than from a result too large to represent.
+func isPole(op string, args []float64) bool {
+	switch op {
+	case "Div":
+		return args[1] == 0
+	case "Pow":
+		return args[0] == 0 // 0 to a negative power
+	}
+	return args[0] == 0 // logarithms of zero
+}
+
+func shortFile(file string) string {
+	if i := strings.LastIndexByte(file, '/'); i >= 0 {
+		if j := strings.LastIndexByte(file[:i], '/'); j >= 0 {
+			return file[j+1:]
+		}
+	}
+	return file
+}
+
+// result turns a *Error into a plain error without the nil-interface trap.
+func result(r float64, e *Error) (float64, error) {
+	if e == nil {
+		return r, nil
+	}
+	return r, e
+}
+
+// Add returns a + b.
+func Add(a, b float64) (float64, error) {
+	r := a + b
+	return result(r, check("Add", r, false, 1, a, b))
+}
+
+// Sub returns a - b.
+func Sub(a, b float64) (float64, error) {
+	r := a - b
+	return result(r, check("Sub", r, false, 1, a, b))
+}
+
+// Mul returns a * b.
+func Mul(a, b float64) (float64, error) {
+	r := a * b
+	return result(r, check("Mul", r, a != 0 && b != 0, 1, a, b))
+}
+
+// Div returns a / b. unlike the lesson's safeDivide it still returns the
+// IEEE result (±Inf for x/0) along with ErrDivByZero.
+func Div(a, b float64) (float64, error) {
+	r := a / b
+	return result(r, check("Div", r, a != 0 && !math.IsInf(b, 0), 1, a, b))
+}
+
+// Sqrt returns the square root of x; ErrDomain for x < 0.
+func Sqrt(x float64) (float64, error) {
+	r := math.Sqrt(x)
+	return result(r, check("Sqrt", r, false, 1, x))
+}
+
+// Log returns the natural logarithm of x; ErrDomain for x < 0 and
+// ErrDivByZero for x == 0 (the result is -Inf).
+func Log(x float64) (float64, error) {
+	r := math.Log(x)
+	return result(r, check("Log", r, false, 1, x))
+}
+
+// Log2 is Log in base 2.
+func Log2(x float64) (float64, error) {
+	r := math.Log2(x)
+	return result(r, check("Log2", r, false, 1, x))
+}
+
+// Log10 is Log in base 10.
+func Log10(x float64) (float64, error) {
+	r := math.Log10(x)
+	return result(r, check("Log10", r, false, 1, x))
+}
+
+// Exp returns e^x; ErrOverflow above about 709.78 and ErrUnderflow below
+// about -745.13.
+func Exp(x float64) (float64, error) {
+	r := math.Exp(x)
+	return result(r, check("Exp", r, !math.IsInf(x, -1), 1, x))
+}
+
+// Pow returns x^y; ErrDomain for a negative x and a non-integer y, and
+// ErrDivByZero for 0 to a negative power.
+func Pow(x, y float64) (float64, error) {
+	r := math.Pow(x, y)
+	return result(r, check("Pow", r, x != 0 && !math.IsInf(x, 0) && !math.IsInf(y, 0), 1, x, y))
+}
+
+// ToInt64 converts x to int64, truncating toward zero like int64(x). unlike
+// the conversion, which is implementation-defined for NaN and out of range
+// values (on amd64 it gives math.MinInt64), it fails with ErrNaNInput or
+// ErrOverflow.
+func ToInt64(x float64) (int64, error) {
+	var err error
+	switch {
+	case math.IsNaN(x):
+		err = ErrNaNInput
+	case x >= 1<<63 || x < -1<<63: // both bounds are exact float64 values
+		err = ErrOverflow
+	default:
+		return int64(x), nil
+	}
+	e := conversionError("ToInt64", 0, err)
+	e.Args = []float64{x}
+	return 0, e
+}
+
+// ToFloat32 converts x to float32. it fails with ErrOverflow when a finite
+// x becomes ±Inf and ErrUnderflow when a nonzero x becomes 0; ordinary
+// rounding to the nearest float32 is not an error.
+func ToFloat32(x float64) (float32, error) {
+	r := float32(x)
+	e := check("ToFloat32", float64(r), x != 0, 1, x)
+	if e == nil {
+		return r, nil
+	}
+	return r, e
+}
+
+// FromInt64 converts i to float64 like float64(i), with ErrInexact when i
+// has more than 53 significant bits and is rounded.
+func FromInt64(i int64) (float64, error) {
+	r := float64(i)
+	// r can be 2^63, which int64() would not round-trip, so compare with care
+	if r >= 1<<63 || int64(r) != i {
+		e := conversionError("FromInt64", r, ErrInexact)
+		e.Input = strconv.FormatInt(i, 10)
+		return r, e
+	}
+	return r, nil
+}
+
+func conversionError(op string, r float64, err error) *Error {
+	e := &Error{Op: op, Result: r, Err: err}
+	if _, file, line, ok := runtime.Caller(2); ok {
+		e.Caller = shortFile(file) + ":" + strconv.Itoa(line)
+	}
+	return e
+}
@@ -0,0 +1,129 @@
+package checked
+
+import (
+	"math"
+	"strconv"
+)
+
+// F64 is a float64 that remembers the first error of the computation that
+// made it. operations keep going with the IEEE result after an error, like
+// plain floats, but the original *Error sticks, so
+//
+//	v, err := checked.New(a).Sub(checked.New(b)).Sqrt().Div(checked.New(c)).Value()
+//
+// reports the Sqrt of a negative number, not a vague NaN at the end. when
+// both operands carry an error, the receiver's wins.
+type F64 struct {
+	v   float64
+	err *Error
+}
+
+// New wraps v. a NaN v is reported as ErrNaNInput by the first operation.
+func New(v float64) F64 { return F64{v: v} }
+
+// Float64 returns the value, whatever errors happened on the way.
+func (x F64) Float64() float64 { return x.v }
+
+// Err returns the first error, or nil.
+func (x F64) Err() error {
+	if x.err == nil {
+		return nil
+	}
+	return x.err
+}
+
+// Value returns the value and the first error.
+func (x F64) Value() (float64, error) { return x.v, x.Err() }
+
+func (x F64) String() string {
+	s := strconv.FormatFloat(x.v, 'g', -1, 64)
+	if x.err != nil {
+		s += " (" + x.err.Err.Error() + " in " + x.err.Op + ")"
+	}
+	return s
+}
+
+// sticky returns the error already carried by x or y. operations only
+// check their own result when there is none, so a NaN is reported once,
+// where it was made, and not again as ErrNaNInput by every later step.
+func (x F64) sticky(y F64) *Error {
+	if x.err == nil {
+		return y.err
+	}
+	return x.err
+}
+
+// Add returns x + y.
+func (x F64) Add(y F64) F64 {
+	r := x.v + y.v
+	if e := x.sticky(y); e != nil {
+		return F64{r, e}
+	}
+	return F64{r, check("Add", r, false, 1, x.v, y.v)}
+}
+
+// Sub returns x - y.
+func (x F64) Sub(y F64) F64 {
+	r := x.v - y.v
+	if e := x.sticky(y); e != nil {
+		return F64{r, e}
+	}
+	return F64{r, check("Sub", r, false, 1, x.v, y.v)}
+}
+
+// Mul returns x * y.
+func (x F64) Mul(y F64) F64 {
+	r := x.v * y.v
+	if e := x.sticky(y); e != nil {
+		return F64{r, e}
+	}
+	return F64{r, check("Mul", r, x.v != 0 && y.v != 0, 1, x.v, y.v)}
+}
+
+// Div returns x / y.
+func (x F64) Div(y F64) F64 {
+	r := x.v / y.v
+	if e := x.sticky(y); e != nil {
+		return F64{r, e}
+	}
+	return F64{r, check("Div", r, x.v != 0 && !math.IsInf(y.v, 0), 1, x.v, y.v)}
+}
+
+// Pow returns x^y.
+func (x F64) Pow(y F64) F64 {
+	r := math.Pow(x.v, y.v)
+	if e := x.sticky(y); e != nil {
+		return F64{r, e}
+	}
+	return F64{r, check("Pow", r, x.v != 0 && !math.IsInf(x.v, 0) && !math.IsInf(y.v, 0), 1, x.v, y.v)}
+}
+
+// Neg returns -x; it cannot fail.
+func (x F64) Neg() F64 { return F64{-x.v, x.err} }
+
+// Sqrt returns the square root of x.
+func (x F64) Sqrt() F64 {
+	r := math.Sqrt(x.v)
+	if x.err != nil {
+		return F64{r, x.err}
+	}
+	return F64{r, check("Sqrt", r, false, 1, x.v)}
+}
+
+// Log returns the natural logarithm of x.
+func (x F64) Log() F64 {
+	r := math.Log(x.v)
+	if x.err != nil {
+		return F64{r, x.err}
+	}
+	return F64{r, check("Log", r, false, 1, x.v)}
+}
+
+// Exp returns e^x.
+func (x F64) Exp() F64 {
+	r := math.Exp(x.v)
+	if x.err != nil {
+		return F64{r, x.err}
+	}
+	return F64{r, check("Exp", r, !math.IsInf(x.v, -1), 1, x.v)}
+}
@@ -0,0 +1,77 @@
+package checked
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	tracing  atomic.Bool
+	traceMu  sync.Mutex
+	firstNaN *Error
+)
+
+// Trace starts recording the first operation of this package that makes a
+// NaN out of non-NaN operands (ErrDomain or ErrInvalid), with its whole call
+// stack in Error.Stack. it catches the origin even when callers drop the
+// errors. the returned function stops tracing and returns that operation,
+// or nil if no NaN was made:
+//
+//	stop := checked.Trace()
+//	runPipeline()
+//	if e := stop(); e != nil {
+//		log.Printf("first NaN: %v\n%s", e, e.Stack)
+//	}
+//
+// tracing is process wide and costs nothing until an error happens. calling
+// Trace again restarts it.
+func Trace() (stop func() *Error) {
+	traceMu.Lock()
+	firstNaN = nil
+	traceMu.Unlock()
+	tracing.Store(true)
+	return func() *Error {
+		tracing.Store(false)
+		traceMu.Lock()
+		defer traceMu.Unlock()
+		e := firstNaN
+		firstNaN = nil
+		return e
+	}
+}
+
+// traceError records e if it is the first NaN while tracing. skip counts
+// the frames between traceError's caller and the code outside the package.
+func traceError(e *Error, skip int) {
+	if !tracing.Load() || !errors.Is(e, ErrDomain) && !errors.Is(e, ErrInvalid) {
+		return
+	}
+	traceMu.Lock()
+	defer traceMu.Unlock()
+	if firstNaN != nil {
+		return
+	}
+	e.Stack = stack(skip + 1)
+	firstNaN = e
+}
+
+// stack formats the call stack starting skip frames above its caller, like
+// a goroutine dump.
+func stack(skip int) string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(skip+2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	var b strings.Builder
+	for {
+		f, more := frames.Next()
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", f.Function, f.File, f.Line)
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
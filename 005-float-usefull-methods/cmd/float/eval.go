@@ -0,0 +1,304 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"strconv"
+)
+
+// kind is the type of a value. literals stay untyped until they meet a
+// typed operand, so float32(0.1) rounds the decimal 0.1 straight to float32
+// instead of going through float64 first.
+type kind int
+
+const (
+	untyped kind = iota
+	f64
+	f32
+)
+
+type value struct {
+	kind kind
+	f64  float64 // for untyped and f64
+	f32  float32 // for f32
+	lit  string  // the literal text, for an untyped literal
+}
+
+// float64 returns v widened to float64.
+func (v value) float64() float64 {
+	if v.kind == f32 {
+		return float64(v.f32)
+	}
+	return v.f64
+}
+
+// to32 converts v to float32, parsing a literal directly.
+func (v value) to32() value {
+	switch {
+	case v.kind == f32:
+		return v
+	case v.lit != "":
+		f, _ := strconv.ParseFloat(v.lit, 32)
+		return value{kind: f32, f32: float32(f)}
+	}
+	return value{kind: f32, f32: float32(v.f64)}
+}
+
+// to64 converts v to float64, which is exact for float32.
+func (v value) to64() value {
+	return value{kind: f64, f64: v.float64()}
+}
+
+// eval parses and evaluates a float expression. arithmetic happens at run
+// time in the operand type, as with variables: Go evaluates constant
+// expressions exactly, so a constant 0.1 + 0.2 is 0.3, but here it is
+// 0.30000000000000004 like x + y would be.
+func eval(src string) (value, error) {
+	e, err := parser.ParseExpr(src)
+	if err != nil {
+		return value{}, err
+	}
+	return evalExpr(e)
+}
+
+func evalExpr(e ast.Expr) (value, error) {
+	switch e := e.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.INT && e.Kind != token.FLOAT {
+			return value{}, fmt.Errorf("%s is not a number", e.Value)
+		}
+		f, err := strconv.ParseFloat(e.Value, 64)
+		if err != nil && !isRange(err) {
+			return value{}, err
+		}
+		return value{f64: f, lit: e.Value}, nil
+	case *ast.ParenExpr:
+		return evalExpr(e.X)
+	case *ast.UnaryExpr:
+		x, err := evalExpr(e.X)
+		if err != nil {
+			return value{}, err
+		}
+		switch e.Op {
+		case token.ADD:
+			return x, nil
+		case token.SUB:
+			// negation is exact, so keep the literal for float32(-0.1)
+			x.f64, x.f32 = -x.f64, -x.f32
+			if len(x.lit) > 0 && x.lit[0] == '-' {
+				x.lit = x.lit[1:]
+			} else if x.lit != "" {
+				x.lit = "-" + x.lit
+			}
+			return x, nil
+		}
+		return value{}, fmt.Errorf("operator %s not supported", e.Op)
+	case *ast.BinaryExpr:
+		return evalBinary(e)
+	case *ast.SelectorExpr:
+		return mathConst(e)
+	case *ast.CallExpr:
+		return evalCall(e)
+	}
+	return value{}, fmt.Errorf("unsupported expression %T", e)
+}
+
+func isRange(err error) bool {
+	ne, ok := err.(*strconv.NumError)
+	return ok && ne.Err == strconv.ErrRange
+}
+
+func evalBinary(e *ast.BinaryExpr) (value, error) {
+	x, err := evalExpr(e.X)
+	if err != nil {
+		return value{}, err
+	}
+	y, err := evalExpr(e.Y)
+	if err != nil {
+		return value{}, err
+	}
+	if x.kind != untyped && y.kind != untyped && x.kind != y.kind {
+		return value{}, fmt.Errorf("mismatched types float32 and float64 in %s", e.Op)
+	}
+	if x.kind == f32 || y.kind == f32 {
+		a, b := x.to32().f32, y.to32().f32
+		var r float32
+		switch e.Op {
+		case token.ADD:
+			r = a + b
+		case token.SUB:
+			r = a - b
+		case token.MUL:
+			r = a * b
+		case token.QUO:
+			r = a / b
+		default:
+			return value{}, fmt.Errorf("operator %s not supported", e.Op)
+		}
+		return value{kind: f32, f32: r}, nil
+	}
+	a, b := x.f64, y.f64
+	var r float64
+	switch e.Op {
+	case token.ADD:
+		r = a + b
+	case token.SUB:
+		r = a - b
+	case token.MUL:
+		r = a * b
+	case token.QUO:
+		r = a / b
+	default:
+		return value{}, fmt.Errorf("operator %s not supported", e.Op)
+	}
+	// two untyped operands give an untyped (but no longer exact) result
+	return value{kind: max(x.kind, y.kind), f64: r}, nil
+}
+
+var mathConsts = map[string]float64{
+	"E": math.E, "Pi": math.Pi, "Phi": math.Phi,
+	"Sqrt2": math.Sqrt2, "SqrtE": math.SqrtE, "SqrtPi": math.SqrtPi, "SqrtPhi": math.SqrtPhi,
+	"Ln2": math.Ln2, "Log2E": math.Log2E, "Ln10": math.Ln10, "Log10E": math.Log10E,
+	"MaxFloat64": math.MaxFloat64, "SmallestNonzeroFloat64": math.SmallestNonzeroFloat64,
+}
+
+// mathConsts32 are the float32 limits, shown as the type they describe.
+var mathConsts32 = map[string]float32{
+	"MaxFloat32": math.MaxFloat32, "SmallestNonzeroFloat32": math.SmallestNonzeroFloat32,
+}
+
+func mathConst(e *ast.SelectorExpr) (value, error) {
+	if id, ok := e.X.(*ast.Ident); !ok || id.Name != "math" {
+		return value{}, fmt.Errorf("unknown selector %s", exprString(e))
+	}
+	if f, ok := mathConsts[e.Sel.Name]; ok {
+		return value{f64: f}, nil
+	}
+	if f, ok := mathConsts32[e.Sel.Name]; ok {
+		return value{kind: f32, f32: f}, nil
+	}
+	return value{}, fmt.Errorf("unknown constant math.%s", e.Sel.Name)
+}
+
+// mathFuncs are the float64 functions of one or two arguments.
+var mathFuncs = map[string]any{
+	"Sqrt": math.Sqrt, "Abs": math.Abs, "Cbrt": math.Cbrt, "Exp": math.Exp,
+	"Log": math.Log, "Log2": math.Log2, "Log10": math.Log10,
+	"Sin": math.Sin, "Cos": math.Cos, "Tan": math.Tan,
+	"Floor": math.Floor, "Ceil": math.Ceil, "Trunc": math.Trunc, "Round": math.Round,
+	"Pow": math.Pow, "Copysign": math.Copysign, "Nextafter": math.Nextafter,
+	"Mod": math.Mod, "Remainder": math.Remainder, "Hypot": math.Hypot, "Max": math.Max, "Min": math.Min,
+}
+
+func evalCall(e *ast.CallExpr) (value, error) {
+	name := exprString(e.Fun)
+	args := make([]value, 0, len(e.Args))
+	argc := func(n int) error {
+		if len(e.Args) != n {
+			return fmt.Errorf("%s takes %d argument(s)", name, n)
+		}
+		return nil
+	}
+
+	// these need the integer literal itself, not a float
+	switch name {
+	case "math.Float64frombits", "math.Float32frombits", "math.Inf":
+		if err := argc(1); err != nil {
+			return value{}, err
+		}
+		n, err := intArg(e.Args[0])
+		if err != nil {
+			return value{}, fmt.Errorf("%s: %v", name, err)
+		}
+		switch name {
+		case "math.Float64frombits":
+			return value{kind: f64, f64: math.Float64frombits(n)}, nil
+		case "math.Float32frombits":
+			if n > math.MaxUint32 {
+				return value{}, fmt.Errorf("%s: %#x overflows uint32", name, n)
+			}
+			return value{kind: f32, f32: math.Float32frombits(uint32(n))}, nil
+		}
+		return value{kind: f64, f64: math.Inf(int(int64(n)))}, nil
+	case "math.NaN":
+		if err := argc(0); err != nil {
+			return value{}, err
+		}
+		return value{kind: f64, f64: math.NaN()}, nil
+	}
+
+	for _, a := range e.Args {
+		v, err := evalExpr(a)
+		if err != nil {
+			return value{}, err
+		}
+		args = append(args, v)
+	}
+	switch name {
+	case "float32":
+		if err := argc(1); err != nil {
+			return value{}, err
+		}
+		return args[0].to32(), nil
+	case "float64":
+		if err := argc(1); err != nil {
+			return value{}, err
+		}
+		return args[0].to64(), nil
+	case "math.Nextafter32":
+		if err := argc(2); err != nil {
+			return value{}, err
+		}
+		return value{kind: f32, f32: math.Nextafter32(args[0].to32().f32, args[1].to32().f32)}, nil
+	}
+
+	if len(name) > 5 && name[:5] == "math." {
+		switch f := mathFuncs[name[5:]].(type) {
+		case func(float64) float64:
+			if err := argc(1); err != nil {
+				return value{}, err
+			}
+			return value{kind: f64, f64: f(args[0].float64())}, nil
+		case func(float64, float64) float64:
+			if err := argc(2); err != nil {
+				return value{}, err
+			}
+			return value{kind: f64, f64: f(args[0].float64(), args[1].float64())}, nil
+		}
+	}
+	return value{}, fmt.Errorf("unknown function %s", name)
+}
+
+// intArg reads an integer literal, possibly negated, as a uint64 bit
+// pattern: -1 is 0xffffffffffffffff.
+func intArg(e ast.Expr) (uint64, error) {
+	neg := false
+	if u, ok := e.(*ast.UnaryExpr); ok && u.Op == token.SUB {
+		neg, e = true, u.X
+	}
+	lit, ok := e.(*ast.BasicLit)
+	if !ok || lit.Kind != token.INT {
+		return 0, fmt.Errorf("argument must be an integer literal")
+	}
+	n, err := strconv.ParseUint(lit.Value, 0, 64)
+	if err != nil {
+		return 0, err
+	}
+	if neg {
+		n = -n
+	}
+	return n, nil
+}
+
+func exprString(e ast.Expr) string {
+	switch e := e.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		return exprString(e.X) + "." + e.Sel.Name
+	}
+	return fmt.Sprintf("%T", e)
+}
@@ -0,0 +1,275 @@
+// float shows how a value is stored in IEEE-754 float32 and float64: the
+// sign, exponent and mantissa bits, the class of the value, the exact
+// decimal it holds and its neighbours. it makes SECTION 3 and 4 (NaN and
+// Inf) and Q31/Q32 (float32 and float64 precision) of 005 visible:
+//
+//	go run ./cmd/float 0.1
+//	go run ./cmd/float 'float32(1.23456789)'
+//	go run ./cmd/float 'math.Inf(1)'
+//	go run ./cmd/float 'math.Float64frombits(0x7ff0000000000001)'   // signaling NaN
+//	go run ./cmd/float -- -0.0
+//
+// the expression may use literals (decimal or hex like 0x1p-2), + - * /,
+// float32() and float64(), math constants and the common math functions.
+// an untyped literal is shown as float64 and as float32.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"math/bits"
+	"os"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	only32 := flag.Bool("32", false, "only show float32")
+	only64 := flag.Bool("64", false, "only show float64")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: float [-32|-64] [--] 'expression'")
+		fmt.Fprintln(os.Stderr, "use -- before an expression that starts with '-'")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	src := strings.Join(flag.Args(), " ")
+	v, err := eval(src)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "float:", err)
+		os.Exit(1)
+	}
+
+	// the value in its own type first, then converted to the other one
+	show64 := func(note string) {
+		x := v.float64()
+		describe(os.Stdout, view64(x), note, v.lit)
+	}
+	show32 := func(note string) {
+		describe(os.Stdout, view32(v.to32().f32), note, v.lit)
+	}
+	switch {
+	case *only32:
+		show32("")
+	case *only64:
+		show64("")
+	case v.kind == f32:
+		show32("")
+		show64("float64(x): widening is exact, the extra mantissa bits are 0")
+	default:
+		show64("")
+		if v.lit != "" {
+			show32("float32 of the same literal")
+		} else {
+			show32(convertNote(v.f64))
+		}
+	}
+}
+
+// convertNote says what float32(x) did to a float64 x.
+func convertNote(x float64) string {
+	y := float32(x)
+	switch {
+	case math.IsNaN(x):
+		return "float32(x): NaN stays NaN, the low 29 payload bits are dropped"
+	case float64(y) == x:
+		return "float32(x): exact, x fits in float32"
+	case math.IsInf(float64(y), 0):
+		return "float32(x): overflows to Inf"
+	case y == 0:
+		return "float32(x): underflows to zero"
+	}
+	return "float32(x): rounded to the nearest float32"
+}
+
+// layout is the bit layout of one IEEE-754 binary format.
+type layout struct {
+	name     string
+	bits     int
+	expBits  int
+	fracBits int
+	bias     int
+}
+
+var (
+	layout64 = layout{"float64", 64, 11, 52, 1023}
+	layout32 = layout{"float32", 32, 8, 23, 127}
+)
+
+// view is a value and its neighbours, widened to float64 (always exact).
+type view struct {
+	l          layout
+	raw        uint64
+	x          float64
+	prev, next float64
+	short      string // shortest decimal that round-trips in the type
+}
+
+func view64(x float64) view {
+	return view{
+		l: layout64, raw: math.Float64bits(x), x: x,
+		prev:  math.Nextafter(x, math.Inf(-1)),
+		next:  math.Nextafter(x, math.Inf(1)),
+		short: strconv.FormatFloat(x, 'g', -1, 64),
+	}
+}
+
+func view32(x float32) view {
+	inf := float32(math.Inf(1))
+	return view{
+		l: layout32, raw: uint64(math.Float32bits(x)), x: float64(x),
+		prev:  float64(math.Nextafter32(x, -inf)),
+		next:  float64(math.Nextafter32(x, inf)),
+		short: strconv.FormatFloat(float64(x), 'g', -1, 32),
+	}
+}
+
+func describe(w io.Writer, v view, note, lit string) {
+	l := v.l
+	sign := v.raw >> (l.bits - 1)
+	exp := int(v.raw>>l.fracBits) & (1<<l.expBits - 1)
+	frac := v.raw & (1<<l.fracBits - 1)
+	bitsOf := func(n uint64, width int) string {
+		return fmt.Sprintf("%0*b", width, n)
+	}
+
+	fmt.Fprintf(w, "%s  %s\n", l.name, v.short)
+	if note != "" {
+		fmt.Fprintf(w, "  %s\n", note)
+	}
+	row := func(label, format string, args ...any) {
+		fmt.Fprintf(w, "  %-10s %s\n", label, fmt.Sprintf(format, args...))
+	}
+	row("bits", "%d %s %s", sign, bitsOf(uint64(exp), l.expBits), bitsOf(frac, l.fracBits))
+	row("", "s %-*s mantissa (%d bits)", l.expBits, "exponent", l.fracBits)
+	row("hex", "0x%0*x", l.bits/4, v.raw)
+	if sign == 1 {
+		row("sign", "1 (negative)")
+	} else {
+		row("sign", "0 (positive)")
+	}
+	maxExp := 1<<l.expBits - 1
+	switch exp {
+	case 0:
+		row("exponent", "%d: all zeros, zero or subnormal (scale 2^%d, no implicit 1)", exp, 1-l.bias)
+	case maxExp:
+		row("exponent", "%d: all ones, Inf or NaN", exp)
+	default:
+		row("exponent", "%d biased, %d - %d = %d unbiased", exp, exp, l.bias, exp-l.bias)
+	}
+	row("mantissa", "%#x = %d", frac, frac)
+
+	sgn := "+"
+	if sign == 1 {
+		sgn = "-"
+	}
+	switch {
+	case exp == maxExp && frac == 0:
+		row("class", "%sInf", sgn)
+	case exp == maxExp:
+		quiet := frac>>(l.fracBits-1) == 1
+		payload := frac &^ (1 << (l.fracBits - 1))
+		kind := "signaling (top mantissa bit clear)"
+		if quiet {
+			kind = "quiet (top mantissa bit set)"
+		}
+		row("class", "NaN, %s, payload %#x", kind, payload)
+		row("", "NaN != NaN, and any arithmetic on it gives NaN")
+	case exp == 0 && frac == 0:
+		row("class", "zero (%s0), equal to %s0 but 1/x is %sInf", sgn, flip(sgn), sgn)
+	case exp == 0:
+		row("class", "subnormal: %s0.mantissa x 2^%d = %s%d x 2^%d", sgn, 1-l.bias, sgn, frac, 1-l.bias-l.fracBits)
+		row("", "precision is lost: %d of %d significant bits left", bits.Len64(frac), l.fracBits+1)
+	default:
+		row("class", "normal: %s1.mantissa x 2^%d = %s%d x 2^%d", sgn, exp-l.bias, sgn, frac|1<<l.fracBits, exp-l.bias-l.fracBits)
+	}
+
+	if math.IsNaN(v.x) {
+		fmt.Fprintln(w)
+		return
+	}
+	if !math.IsInf(v.x, 0) {
+		row("stored", "%s", exact(v.x))
+		if diff, ok := literalError(v.x, lit); ok {
+			row("error", "stored - %s = %s", lit, diff)
+		}
+	}
+	if v.prev != v.x && !math.IsInf(v.prev, 0) {
+		row("previous", "%s", neighbour(v.prev, l))
+	}
+	if v.next != v.x && !math.IsInf(v.next, 0) {
+		row("next", "%s", neighbour(v.next, l))
+	}
+	if !math.IsInf(v.x, 0) {
+		up, down := v.next-v.x, v.x-v.prev
+		if math.IsInf(v.next, 0) {
+			up = down
+		}
+		if math.IsInf(v.prev, 0) {
+			down = up
+		}
+		if up == down {
+			row("ulp", "%s", power(up))
+		} else {
+			// at a power of two the spacing doubles on the side away from zero
+			row("ulp", "%s above, %s below", power(up), power(down))
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+func flip(sgn string) string {
+	if sgn == "+" {
+		return "-"
+	}
+	return "+"
+}
+
+// exact returns every decimal digit of x. a float is m x 2^e, so with e < 0
+// it has exactly -e digits after the point and the expansion always ends.
+func exact(x float64) string {
+	if x == 0 && math.Signbit(x) {
+		return "-0"
+	}
+	r := new(big.Rat).SetFloat64(x)
+	s := r.FloatString(r.Denom().BitLen() - 1)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
+
+func neighbour(x float64, l layout) string {
+	return fmt.Sprintf("%s (%s)", exact(x), strconv.FormatFloat(x, 'g', -1, l.bits))
+}
+
+// power prints a gap between neighbours, always a power of two.
+func power(d float64) string {
+	_, e := math.Frexp(d)
+	return fmt.Sprintf("%g = 2^%d", d, e-1)
+}
+
+// literalError returns stored - written for a decimal literal.
+func literalError(x float64, lit string) (string, bool) {
+	if lit == "" {
+		return "", false
+	}
+	want, ok := new(big.Rat).SetString(strings.ReplaceAll(lit, "_", ""))
+	if !ok {
+		return "", false
+	}
+	d := new(big.Rat).Sub(new(big.Rat).SetFloat64(x), want)
+	if d.Sign() == 0 {
+		return "0 (the literal is exact)", true
+	}
+	// big.Float, since the difference can be far below the smallest float64
+	f := new(big.Float).SetRat(d)
+	return f.Text('g', 3), true
+}
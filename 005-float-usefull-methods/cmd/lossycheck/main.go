@@ -0,0 +1,439 @@
+// lossycheck finds constants that Go silently rounds when they become a
+// float32 or float64, like vet would:
+//
+//	var x float32 = 1.23456789   // stored as 1.2345679
+//	f(16777217)                  // a float32 parameter: 16777216
+//	float64(1<<53 + 1)           // 9007199254740992
+//	math.Pi * float32(r)         // math.Pi rounded to float32
+//
+// the compiler accepts all of these because rounding a constant to a float
+// type is legal; only overflow is an error. lossycheck type-checks each
+// package, works out the exact value of every constant that ends up with a
+// float type and reports those that differ from what is stored:
+//
+//	go run ./cmd/lossycheck ./...
+//	go run ./cmd/lossycheck -32 main.go   // only float32 roundings
+//
+// arguments are files, directories or dir/... patterns. it exits with status
+// 1 when it reports anything.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	only32 := flag.Bool("32", false, "only report constants rounded to float32")
+	verbose := flag.Bool("v", false, "also print type-checking errors")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: lossycheck [-32] [-v] [file.go | dir | dir/...]...")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	args := flag.Args()
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+
+	groups, err := packages(args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "lossycheck:", err)
+		os.Exit(1)
+	}
+	found := 0
+	for _, files := range groups {
+		for _, f := range check(files, *verbose) {
+			if *only32 && f.typ != "float32" {
+				continue
+			}
+			fmt.Println(f)
+			found++
+		}
+	}
+	if found > 0 {
+		os.Exit(1)
+	}
+}
+
+// packages expands the arguments into groups of files type-checked together:
+// the non-test files of one package in one directory.
+func packages(args []string) ([][]string, error) {
+	var dirs []string
+	var groups [][]string
+	for _, arg := range args {
+		if dir, ok := strings.CutSuffix(arg, "/..."); ok {
+			err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				name := d.Name()
+				if d.IsDir() && path != dir && (strings.HasPrefix(name, ".") || name == "testdata" || name == "vendor") {
+					return filepath.SkipDir
+				}
+				if d.IsDir() {
+					dirs = append(dirs, path)
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+		if info.IsDir() {
+			dirs = append(dirs, arg)
+		} else {
+			groups = append(groups, []string{arg})
+		}
+	}
+	for _, dir := range dirs {
+		files, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+		byPkg := map[string][]string{}
+		for _, file := range files {
+			if strings.HasSuffix(file, "_test.go") {
+				continue
+			}
+			f, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.PackageClauseOnly)
+			if err != nil {
+				continue // reported when the group is checked
+			}
+			byPkg[f.Name.Name] = append(byPkg[f.Name.Name], file)
+		}
+		for _, name := range sortedKeys(byPkg) {
+			groups = append(groups, byPkg[name])
+		}
+	}
+	return groups, nil
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// finding is one rounded constant.
+type finding struct {
+	pos    token.Position
+	expr   string
+	typ    string // "float32" or "float64"
+	exact  constant.Value
+	stored float64
+}
+
+func (f finding) String() string {
+	want := bigFloat(f.exact)
+	diff := new(big.Float).SetPrec(256).SetFloat64(f.stored)
+	diff.Sub(diff, want)
+	rel := new(big.Float).Quo(diff, want)
+	bits := 64
+	if f.typ == "float32" {
+		bits = 32
+	}
+	exact := f.exact.ExactString()
+	if len(exact) > 40 || strings.Contains(exact, "/") {
+		exact = want.Text('g', 20)
+	}
+	return fmt.Sprintf("%s: %s: constant %s rounds to %s %s (error %s, rel %s)",
+		f.pos, f.expr, exact, f.typ, formatFloat(f.stored, bits),
+		diff.Text('g', 2), rel.Abs(rel).Text('g', 2))
+}
+
+// formatFloat writes the shortest decimal of x, without an exponent for
+// integers like 16777216.
+func formatFloat(x float64, bits int) string {
+	if x == math.Trunc(x) && math.Abs(x) < 1e21 {
+		return strconv.FormatFloat(x, 'f', -1, bits)
+	}
+	return strconv.FormatFloat(x, 'g', -1, bits)
+}
+
+// bigFloat converts an Int or Float constant to a 256-bit big.Float.
+func bigFloat(v constant.Value) *big.Float {
+	z := new(big.Float).SetPrec(256)
+	switch x := constant.Val(v).(type) {
+	case int64:
+		z.SetInt64(x)
+	case *big.Int:
+		z.SetInt(x)
+	case *big.Rat:
+		z.SetRat(x)
+	case *big.Float:
+		z.Set(x)
+	}
+	return z
+}
+
+// check type-checks one group of files and returns its rounded constants.
+func check(files []string, verbose bool) []finding {
+	fset := token.NewFileSet()
+	var parsed []*ast.File
+	for _, name := range files {
+		f, err := parser.ParseFile(fset, name, nil, parser.SkipObjectResolution)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "lossycheck:", err)
+			continue
+		}
+		parsed = append(parsed, f)
+	}
+	info := &types.Info{
+		Types: map[ast.Expr]types.TypeAndValue{},
+		Uses:  map[*ast.Ident]types.Object{},
+	}
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		// keep going: a missing import only hides the constants that use it
+		Error: func(err error) {
+			if verbose {
+				fmt.Fprintln(os.Stderr, "lossycheck:", err)
+			}
+		},
+	}
+	conf.Check("", fset, parsed, info)
+
+	c := checker{fset: fset, info: info}
+	for _, f := range parsed {
+		c.walk(f)
+	}
+	return c.found
+}
+
+type checker struct {
+	fset  *token.FileSet
+	info  *types.Info
+	found []finding
+}
+
+// walk visits every expression bottom up and reports the innermost one that
+// rounds, so float32(1.23456789) points at the literal and an expression
+// built from a rounded constant is not reported twice.
+func (c *checker) walk(root ast.Node) {
+	reported := map[ast.Node]bool{}
+	var stack []ast.Node
+	ast.Inspect(root, func(n ast.Node) bool {
+		if n != nil {
+			stack = append(stack, n)
+			return true
+		}
+		n = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if reported[n] {
+			if len(stack) > 0 {
+				reported[stack[len(stack)-1]] = true
+			}
+			return true
+		}
+		if e, ok := n.(ast.Expr); ok && c.report(e) && len(stack) > 0 {
+			reported[stack[len(stack)-1]] = true
+		}
+		return true
+	})
+}
+
+// report records e if it is a float32 or float64 constant that does not keep
+// the digits it was written with. every 0.1 rounds, but it prints as 0.1
+// again, so only a stored value whose shortest decimal differs from the
+// constant counts: 1.23456789 stored as float32 1.2345679. constants with
+// more digits than float64 holds, like math.Pi, or no finite decimal, like
+// 1.0/3, are compared with their float64 value instead, so they only count
+// as float32. a hex literal, like 0x1.fffffffffffff8p0, spells out its bits
+// instead of decimal digits, so any rounding of it counts.
+func (c *checker) report(e ast.Expr) bool {
+	tv, ok := c.info.Types[e]
+	if !ok || tv.Value == nil || tv.IsType() {
+		return false
+	}
+	basic, ok := tv.Type.Underlying().(*types.Basic)
+	if !ok || basic.Kind() != types.Float32 && basic.Kind() != types.Float64 {
+		return false
+	}
+	exact, ok := c.exact(e)
+	if !ok || exact.Kind() != constant.Int && exact.Kind() != constant.Float {
+		return false
+	}
+	bits := 64
+	stored, _ := constant.Float64Val(exact)
+	if basic.Kind() == types.Float32 {
+		f, _ := constant.Float32Val(exact)
+		stored, bits = float64(f), 32
+	}
+	if constant.Compare(constant.MakeFloat64(stored), token.EQL, exact) {
+		return false // no rounding at all
+	}
+	if !hexLiteral(e) {
+		want := exact
+		if n, ok := decimalDigits(exact); !ok || n > maxDigits {
+			f64, _ := constant.Float64Val(exact)
+			want = shortest(f64, 64)
+		}
+		if constant.Compare(shortest(stored, bits), token.EQL, want) {
+			return false
+		}
+	}
+	c.found = append(c.found, finding{
+		pos:    c.fset.Position(e.Pos()),
+		expr:   types.ExprString(e),
+		typ:    basic.Name(),
+		exact:  exact,
+		stored: stored,
+	})
+	return true
+}
+
+// maxDigits is the most significant digits a constant may have to count as
+// written by hand; math.Pi and friends have over 60.
+const maxDigits = 30
+
+// hexLiteral reports whether e is a literal written in hex, 0x1p-3 or 0xFF,
+// with or without parentheses and a sign. go/types gives a float type only
+// to the whole of -0x1p-3, so that is where report sees it.
+func hexLiteral(e ast.Expr) bool {
+	switch x := e.(type) {
+	case *ast.ParenExpr:
+		return hexLiteral(x.X)
+	case *ast.UnaryExpr:
+		return (x.Op == token.SUB || x.Op == token.ADD) && hexLiteral(x.X)
+	case *ast.BasicLit:
+		v := strings.ToLower(x.Value)
+		return strings.HasPrefix(v, "0x")
+	}
+	return false
+}
+
+// shortest returns the shortest decimal that reads back as x in a float of
+// the given size.
+func shortest(x float64, bits int) constant.Value {
+	return constant.MakeFromLiteral(strconv.FormatFloat(x, 'g', -1, bits), token.FLOAT, 0)
+}
+
+// decimalDigits returns the number of significant digits of v, or false if
+// v has no finite decimal expansion.
+func decimalDigits(v constant.Value) (int, bool) {
+	var r *big.Rat
+	switch x := constant.Val(v).(type) {
+	case int64:
+		r = new(big.Rat).SetInt64(x)
+	case *big.Int:
+		r = new(big.Rat).SetInt(x)
+	case *big.Rat:
+		r = x
+	case *big.Float:
+		r, _ = x.Rat(nil)
+	}
+	if r == nil {
+		return 0, false
+	}
+	// a finite decimal has only 2s and 5s in the denominator, and needs as
+	// many digits after the point as the larger count
+	d := new(big.Int).Set(r.Denom())
+	places := 0
+	for _, p := range []int64{2, 5} {
+		n := 0
+		for q := big.NewInt(p); new(big.Int).Mod(d, q).Sign() == 0; n++ {
+			d.Quo(d, q)
+		}
+		places = max(places, n)
+	}
+	if d.Cmp(big.NewInt(1)) != 0 {
+		return 0, false
+	}
+	digits := strings.Trim(strings.NewReplacer("-", "", ".", "").Replace(r.FloatString(places)), "0")
+	return len(digits), true
+}
+
+// exact evaluates a constant expression without the rounding go/types
+// applies once the expression has a float type.
+func (c *checker) exact(e ast.Expr) (constant.Value, bool) {
+	switch e := e.(type) {
+	case *ast.BasicLit:
+		v := constant.MakeFromLiteral(e.Value, e.Kind, 0)
+		return v, v.Kind() != constant.Unknown
+	case *ast.Ident:
+		return c.constObject(e)
+	case *ast.SelectorExpr:
+		return c.constObject(e.Sel)
+	case *ast.ParenExpr:
+		return c.exact(e.X)
+	case *ast.UnaryExpr:
+		x, ok := c.exact(e.X)
+		if !ok {
+			return nil, false
+		}
+		return constant.UnaryOp(e.Op, x, 0), true
+	case *ast.BinaryExpr:
+		x, ok1 := c.exact(e.X)
+		y, ok2 := c.exact(e.Y)
+		if !ok1 || !ok2 {
+			return nil, false
+		}
+		switch e.Op {
+		case token.SHL, token.SHR:
+			s, ok := constant.Uint64Val(y)
+			if !ok || x.Kind() != constant.Int {
+				return nil, false
+			}
+			return constant.Shift(x, e.Op, uint(s)), true
+		case token.QUO:
+			if constant.Sign(y) == 0 {
+				return nil, false
+			}
+			if x.Kind() == constant.Int && y.Kind() == constant.Int {
+				// untyped integers: 7 / 2 is 3 even in var f float64 = 7 / 2
+				return constant.BinaryOp(x, token.QUO_ASSIGN, y), true
+			}
+		case token.ADD, token.SUB, token.MUL:
+		default:
+			return nil, false
+		}
+		return constant.BinaryOp(x, e.Op, y), true
+	case *ast.CallExpr:
+		// a conversion float32(x) or float64(x): the rounding happens to x
+		if tv, ok := c.info.Types[e.Fun]; ok && tv.IsType() && len(e.Args) == 1 {
+			v, ok := c.exact(e.Args[0])
+			return toFloat(v, tv.Type), ok
+		}
+	}
+	return nil, false
+}
+
+// constObject returns the value of a named constant. an untyped constant
+// like math.Pi keeps every digit; a typed one was already rounded (and
+// reported) where it was declared.
+func (c *checker) constObject(id *ast.Ident) (constant.Value, bool) {
+	obj, ok := c.info.Uses[id].(*types.Const)
+	if !ok {
+		return nil, false
+	}
+	return toFloat(obj.Val(), obj.Type()), true
+}
+
+// toFloat marks the value of a float-typed constant as a float, so that
+// float64(7) / 2 is 3.5 and only untyped integers divide as integers.
+func toFloat(v constant.Value, t types.Type) constant.Value {
+	if b, ok := t.Underlying().(*types.Basic); ok && b.Info()&types.IsFloat != 0 && v != nil {
+		return constant.ToFloat(v)
+	}
+	return v
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// TestCheck runs the checker on the files in testdata, where every line that
+// should be reported ends with a "// want float32" or "// want float64"
+// comment, and every other line should not be.
+func TestCheck(t *testing.T) {
+	groups, err := packages([]string{"testdata"})
+	if err != nil || len(groups) != 1 {
+		t.Fatalf("packages(testdata) = %v, %v, want one package", groups, err)
+	}
+	want := map[token.Position]string{} // file and line of each want comment
+	wantRE := regexp.MustCompile(`^// want (float32|float64)$`)
+	fset := token.NewFileSet()
+	for _, name := range groups[0] {
+		f, err := parser.ParseFile(fset, name, nil, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, cg := range f.Comments {
+			if m := wantRE.FindStringSubmatch(cg.List[0].Text); m != nil {
+				pos := fset.Position(cg.Pos())
+				want[token.Position{Filename: pos.Filename, Line: pos.Line}] = m[1]
+			}
+		}
+	}
+	if len(want) < 10 {
+		t.Fatalf("found only %d want comments in testdata", len(want))
+	}
+
+	for _, f := range check(groups[0], true) {
+		line := token.Position{Filename: f.pos.Filename, Line: f.pos.Line}
+		switch typ, ok := want[line]; {
+		case !ok:
+			t.Errorf("unexpected report: %v", f)
+		case typ != f.typ:
+			t.Errorf("%v: reported as %s, want %s", f, f.typ, typ)
+		}
+		if s := f.String(); !strings.Contains(s, "rounds to "+f.typ) {
+			t.Errorf("report %q does not say what it rounds to", s)
+		}
+		delete(want, line)
+	}
+	for pos, typ := range want {
+		t.Errorf("%s:%d: not reported, want a %s rounding", pos.Filename, pos.Line, typ)
+	}
+}
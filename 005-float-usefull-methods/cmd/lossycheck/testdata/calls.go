@@ -0,0 +1,15 @@
+package p
+
+import "math"
+
+func f32(x float32) float32 { return x }
+
+func calls(r float32) {
+	f32(16777217) // want float32
+	f32(0.1)
+	f32(untyped)    // want float32
+	_ = math.Pi * r // want float32
+	_ = 2 * r
+	_ = math.Sqrt(0x1.fffffffffffff8p0) // want float64
+	_ = math.Sqrt(2)
+}
@@ -0,0 +1,33 @@
+package p
+
+import "math"
+
+// reported: the stored value reads back as another number
+
+var a float32 = 1.23456789                     // want float32
+var b float32 = 16777217                       // want float32
+var c = float64(1<<53 + 1)                     // want float64
+var d = 1.00000000000000011                    // want float64
+var e = -0.1000000000000000000001              // want float64
+var h = 0x1.fffffffffffff8p0                   // want float64
+var h32 float32 = 0x1.000001p0                 // want float32
+var hn = -0x1.00000000000008p-1022             // want float64
+var hi = float64(0x1FFFFFFFFFFFFFFFFFFFFFFFFF) // want float64
+var pi32 = float32(math.Pi)                    // want float32
+var third32 float32 = 1.0 / 3                  // want float32
+
+// not reported: no rounding, or one that keeps the digits as written
+
+var k = 0.1
+var k32 float32 = 0.1
+var pi = math.Pi
+var third = 1.0 / 3
+var x = 0x1p-3
+var y float32 = 0x1.8p1
+var z = 1e300
+var q float32 = 16777216
+var he = float64(0x1FFFFFFFFFFFFF)
+var i int64 = 1<<62 + 1
+var s = float64(7 / 2)
+
+const untyped = 1.23456789
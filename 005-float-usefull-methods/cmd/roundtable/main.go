@@ -0,0 +1,68 @@
+// roundtable rounds the values given to n places with the lesson's
+// roundToDecimal, math.Round(x*10^n)/10^n, next to what the rounding package
+// gives in every mode, and marks where roundToDecimal is wrong:
+//
+//	go run ./cmd/roundtable 1.005 2.675 1.45
+//	go run ./cmd/roundtable -n 1 4.35 0.25 -0.25
+//
+// the tricky cases, and what every mode should give for them, are checked
+// by the rounding package's tests.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"float-methods/rounding"
+)
+
+// roundToDecimal is the lesson's Q43 answer, for comparison.
+func roundToDecimal(x float64, decimals int) float64 {
+	shift := math.Pow(10, float64(decimals))
+	return math.Round(x*shift) / shift
+}
+
+func main() {
+	n := flag.Int("n", 2, "decimal places, 0 or more")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: roundtable [-n places] value ...")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 || *n < 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprint(tw, "x\troundToDecimal")
+	for _, m := range rounding.Modes {
+		fmt.Fprintf(tw, "\t%v", m)
+	}
+	fmt.Fprintln(tw, "\t")
+	wrong := 0
+	for _, arg := range flag.Args() {
+		x, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "roundtable:", err)
+			os.Exit(1)
+		}
+		// roundToDecimal uses math.Round, which is HalfAwayFromZero
+		naive := strconv.FormatFloat(roundToDecimal(x, *n), 'f', *n, 64)
+		if naive != rounding.FormatPlaces(x, *n, rounding.HalfAwayFromZero) {
+			naive += " (wrong)"
+			wrong++
+		}
+		fmt.Fprintf(tw, "%s\t%s", arg, naive)
+		for _, m := range rounding.Modes {
+			fmt.Fprintf(tw, "\t%s", rounding.FormatPlaces(x, *n, m))
+		}
+		fmt.Fprintln(tw, "\t")
+	}
+	tw.Flush()
+	fmt.Printf("\nroundToDecimal is wrong on %d of %d values\n", wrong, flag.NArg())
+}
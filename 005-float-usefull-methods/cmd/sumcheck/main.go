@@ -0,0 +1,277 @@
+// sumcheck compares the stable package with plain += loops: how far each sum
+// is from the exact one, how the variance formulas cope with a large mean,
+// and what NaN does to a hand-written min loop:
+//
+//	go run ./cmd/sumcheck
+//
+// it exits with status 1 if a stable result is not as accurate as it should
+// be. the same bounds are tests of the package, and its benchmarks time the
+// sums: go test -bench . ./stable
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"sync"
+	"text/tabwriter"
+
+	"float-methods/stable"
+)
+
+func main() {
+	failed := check(os.Stdout)
+	if failed > 0 {
+		fmt.Printf("FAIL: %d checks failed\n", failed)
+		os.Exit(1)
+	}
+}
+
+type sumCase struct {
+	name string
+	xs   []float64
+	// the largest error Neumaier and Kahan may have; -1 skips Kahan, which
+	// is not meant for terms larger than the running sum
+	maxULP, kahanULP float64
+}
+
+func sumCases() []sumCase {
+	r := rand.New(rand.NewSource(1))
+	tenths := make([]float64, 10_000_000)
+	for i := range tenths {
+		tenths[i] = 0.1
+	}
+	harmonic := make([]float64, 1_000_000)
+	for i := range harmonic {
+		harmonic[i] = 1 / float64(i+1)
+	}
+	mixed := make([]float64, 1_000_000)
+	for i := range mixed {
+		mixed[i] = r.NormFloat64() * math.Pow(10, float64(r.Intn(21)-10))
+	}
+	positive := make([]float64, 1_000_000)
+	for i := range positive {
+		positive[i] = r.Float64()
+	}
+	return []sumCase{
+		{"0.1 ten million times", tenths, 0, 0},
+		{"1/i for i = 1..1e6", harmonic, 0, 1},
+		{"1e6 uniform in [0,1)", positive, 0, 1},
+		{"1e6 mixed signs, 1e-10..1e10", mixed, 1, -1},
+		{"[1, 1e100, 1, -1e100]", []float64{1, 1e100, 1, -1e100}, 0, -1},
+	}
+}
+
+// ulps returns how many units in the last place of want got is away.
+func ulps(got, want float64) float64 {
+	if got == want {
+		return 0
+	}
+	if math.IsInf(got, 0) || math.IsNaN(got) {
+		return math.Inf(1)
+	}
+	a := math.Abs(want)
+	ulp := math.Nextafter(a, math.Inf(1)) - a
+	if math.IsInf(ulp, 0) {
+		ulp = a - math.Nextafter(a, 0)
+	}
+	return math.Abs(got-want) / ulp
+}
+
+func check(out io.Writer) int {
+	failed := 0
+	fail := func(format string, args ...any) {
+		failed++
+		fmt.Fprintf(out, "FAIL "+format+"\n", args...)
+	}
+
+	fmt.Fprintln(out, "sums, error in ulps of the correctly rounded sum:")
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "values\texact\t+=\tKahan\tNeumaier\tPairwise\t")
+	for _, c := range sumCases() {
+		exact := stable.Exact(c.xs)
+		kahan, neumaier := stable.Kahan(c.xs), stable.Neumaier(c.xs)
+		fmt.Fprintf(tw, "%s\t%.17g\t%.3g\t%.3g\t%.3g\t%.3g\t\n", c.name, exact,
+			ulps(stable.Naive(c.xs), exact), ulps(kahan, exact),
+			ulps(neumaier, exact), ulps(stable.Pairwise(c.xs), exact))
+		if e := ulps(neumaier, exact); e > c.maxULP {
+			fail("Neumaier(%s) is %.3g ulps off", c.name, e)
+		}
+		if e := ulps(kahan, exact); c.kahanULP >= 0 && e > c.kahanULP {
+			fail("Kahan(%s) is %.3g ulps off", c.name, e)
+		}
+	}
+	tw.Flush()
+
+	fmt.Fprintln(out, "\ndot products:")
+	tw = tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "vectors\texact\t+=\tDot\t")
+	for _, c := range dotCases() {
+		exact, got := stable.ExactDot(c.x, c.y), stable.Dot(c.x, c.y)
+		fmt.Fprintf(tw, "%s\t%.17g\t%.17g\t%.17g\t\n", c.name, exact, stable.NaiveDot(c.x, c.y), got)
+		if math.Abs(got-exact) > dotBound(c.x, c.y, exact) {
+			fail("Dot(%s) = %v, want %v", c.name, got, exact)
+		}
+	}
+	tw.Flush()
+
+	failed += checkVariance(out)
+	failed += checkMinMax(out)
+	return failed
+}
+
+type dotCase struct {
+	name string
+	x, y []float64
+}
+
+func dotCases() []dotCase {
+	// ill-conditioned: products up to 1e20 that cancel to a small result
+	r := rand.New(rand.NewSource(2))
+	n := 1000
+	x, y := make([]float64, 2*n), make([]float64, 2*n)
+	for i := range n {
+		x[i] = r.NormFloat64() * 1e10
+		y[i] = r.NormFloat64() * 1e10
+		x[n+i], y[n+i] = -x[i], y[i]*(1+r.NormFloat64()*1e-15)
+	}
+	return []dotCase{
+		{"[1e16, 1, -1e16]·[1, 1, 1]", []float64{1e16, 1, -1e16}, []float64{1, 1, 1}},
+		{"[1+2⁻³⁰, -1]·[1-2⁻³⁰, 1]", []float64{1 + 0x1p-30, -1}, []float64{1 - 0x1p-30, 1}}, // -2⁻⁶⁰
+		{"2000 cancelling products", x, y},
+	}
+}
+
+// dotBound is the error bound of Dot: one rounding of the result plus the
+// error of twice the precision, (n u)² sum |x[i] y[i]| with u = 2^-53. the
+// second term is what cancellation makes visible.
+func dotBound(x, y []float64, exact float64) float64 {
+	const u = 0x1p-53
+	abs := 0.0
+	for i := range x {
+		abs += math.Abs(x[i] * y[i])
+	}
+	g := float64(len(x)) * u
+	return 2*u*math.Abs(exact) + g*g*abs*1.01
+}
+
+// checkVariance compares variance formulas on values with a large mean, the
+// case where sum(x²)/n - mean² cancels away every correct digit.
+func checkVariance(out io.Writer) int {
+	failed := 0
+	base := []float64{4, 7, 13, 16} // mean 10, population variance 22.5
+	for _, offset := range []float64{0, 1e8, 1e9} {
+		var xs []float64
+		for range 25_000 {
+			for _, b := range base {
+				xs = append(xs, offset+b)
+			}
+		}
+		want := 22.5 * float64(len(xs)) / float64(len(xs)-1)
+
+		var s stable.Stats
+		for _, x := range xs {
+			s.Add(x)
+		}
+		merged := parallel(xs, 4)
+		if offset == 0 {
+			fmt.Fprintf(out, "\nsample variance of %d values 4, 7, 13, 16 plus an offset, exact %.10g:\n", len(xs), want)
+			fmt.Fprintf(out, "  %-6s %-22s %-22s %-22s %s\n", "offset", "sum(x²) formula", "two pass", "Welford", "Welford, 4 merged")
+		}
+		fmt.Fprintf(out, "  %-6g %-22.10g %-22.10g %-22.10g %.10g\n", offset,
+			textbook(xs), twoPass(xs), s.SampleVariance(), merged.SampleVariance())
+		if e := math.Abs(s.SampleVariance()-want) / want; e > 1e-9 {
+			failed++
+			fmt.Fprintf(out, "FAIL Welford variance with offset %g is off by %.3g relative\n", offset, e)
+		}
+		if e := math.Abs(merged.SampleVariance()-want) / want; e > 1e-9 || math.Abs(merged.Mean()-s.Mean()) > 1e-9*math.Abs(s.Mean()) {
+			failed++
+			fmt.Fprintf(out, "FAIL merged Welford with offset %g: variance %v, mean %v\n", offset, merged.SampleVariance(), merged.Mean())
+		}
+	}
+	return failed
+}
+
+// textbook is the one-pass formula (sum(x²) - sum(x)²/n) / (n-1).
+func textbook(xs []float64) float64 {
+	sum, sumSq := 0.0, 0.0
+	for _, x := range xs {
+		sum += x
+		sumSq += x * x
+	}
+	n := float64(len(xs))
+	return (sumSq - sum*sum/n) / (n - 1)
+}
+
+// twoPass computes the mean first, then the squared distances from it.
+func twoPass(xs []float64) float64 {
+	mean := stable.Naive(xs) / float64(len(xs))
+	m2 := 0.0
+	for _, x := range xs {
+		m2 += (x - mean) * (x - mean)
+	}
+	return m2 / float64(len(xs)-1)
+}
+
+// parallel splits xs into parts, one goroutine each, and merges the Stats.
+func parallel(xs []float64, parts int) stable.Stats {
+	results := make([]stable.Stats, parts)
+	var wg sync.WaitGroup
+	for p := range parts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, x := range xs[p*len(xs)/parts : (p+1)*len(xs)/parts] {
+				results[p].Add(x)
+			}
+		}()
+	}
+	wg.Wait()
+	var s stable.Stats
+	for _, r := range results {
+		s.Merge(r)
+	}
+	return s
+}
+
+// checkMinMax shows the NaN trap of a hand-written min loop: x < min is
+// false for NaN, so the NaN disappears, or wins if it comes first.
+func checkMinMax(out io.Writer) int {
+	failed := 0
+	fmt.Fprintln(out, "\nminimum with NaN:")
+	for _, xs := range [][]float64{{3, math.NaN(), -1, 2}, {math.NaN(), 3, -1, 2}, {0, math.Copysign(0, -1)}} {
+		loop := xs[0]
+		for _, x := range xs[1:] {
+			if x < loop {
+				loop = x
+			}
+		}
+		var s, skip stable.Stats
+		skip.SkipNaN = true
+		for _, x := range xs {
+			s.Add(x)
+			skip.Add(x)
+		}
+		fmt.Fprintf(out, "  %-16s if x < min: %-4v Stats: %-4v SkipNaN: %v\n", fmt.Sprint(xs), loop, s.Min(), skip.Min())
+
+		want, hasNaN := math.Inf(1), false
+		for _, x := range xs {
+			if math.IsNaN(x) {
+				hasNaN = true
+			} else {
+				want = math.Min(want, x)
+			}
+		}
+		if got := skip.Min(); got != want || math.Signbit(got) != math.Signbit(want) {
+			failed++
+			fmt.Fprintf(out, "FAIL Min with SkipNaN of %v = %v, want %v\n", xs, got, want)
+		}
+		if math.IsNaN(s.Min()) != hasNaN {
+			failed++
+			fmt.Fprintf(out, "FAIL Min of %v = %v\n", xs, s.Min())
+		}
+	}
+	return failed
+}
@@ -0,0 +1,144 @@
+package decimal
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"float-methods/rounding"
+)
+
+// Big is an arbitrary-precision decimal: unscaled / 10^scale with a
+// math/big unscaled value, so it never overflows. the zero value is 0 and a
+// Big is immutable: every method returns a new value and never modifies its
+// operands, so Bigs can be copied and shared like Fixed.
+type Big struct {
+	u     *big.Int // nil means 0
+	scale int
+}
+
+// NewBig returns unscaled / 10^scale. unscaled is copied. it panics if scale
+// is negative.
+func NewBig(unscaled *big.Int, scale int) Big {
+	if scale < 0 {
+		panic("decimal: NewBig negative scale")
+	}
+	return Big{new(big.Int).Set(unscaled), scale}
+}
+
+// ParseBig parses s exactly, keeping the written number of decimal places
+// as the scale. a large exponent is expanded, so "1e30" has 31 digits.
+func ParseBig(s string) (Big, error) {
+	u, scale, err := parse(s)
+	if err != nil {
+		return Big{}, err
+	}
+	return Big{u, scale}, nil
+}
+
+// MustParseBig is ParseBig for constants; it panics on error.
+func MustParseBig(s string) Big {
+	x, err := ParseBig(s)
+	if err != nil {
+		panic(err)
+	}
+	return x
+}
+
+// int returns the unscaled value, never nil. callers must not modify it.
+func (x Big) int() *big.Int {
+	if x.u == nil {
+		return new(big.Int)
+	}
+	return x.u
+}
+
+// Unscaled returns a copy of the unscaled integer.
+func (x Big) Unscaled() *big.Int { return new(big.Int).Set(x.int()) }
+
+// Scale returns the number of decimal places.
+func (x Big) Scale() int { return x.scale }
+
+// Sign returns -1, 0 or +1.
+func (x Big) Sign() int { return x.int().Sign() }
+
+// IsZero reports whether x is 0 at any scale.
+func (x Big) IsZero() bool { return x.Sign() == 0 }
+
+// Neg returns -x.
+func (x Big) Neg() Big { return Big{new(big.Int).Neg(x.int()), x.scale} }
+
+// Abs returns |x|.
+func (x Big) Abs() Big { return Big{new(big.Int).Abs(x.int()), x.scale} }
+
+// alignBig brings x and y to the larger scale.
+func alignBig(x, y Big) (a, b *big.Int, scale int) {
+	scale = max(x.scale, y.scale)
+	return rescale(x.int(), x.scale, scale, rounding.TowardZero), rescale(y.int(), y.scale, scale, rounding.TowardZero), scale
+}
+
+// Add returns x + y exactly at the larger of the two scales.
+func (x Big) Add(y Big) Big {
+	a, b, scale := alignBig(x, y)
+	return Big{a.Add(a, b), scale}
+}
+
+// Sub returns x - y exactly at the larger of the two scales.
+func (x Big) Sub(y Big) Big {
+	a, b, scale := alignBig(x, y)
+	return Big{a.Sub(a, b), scale}
+}
+
+// Mul returns x * y exactly, at scale x.Scale()+y.Scale(). use Round to
+// bring it back to the scale you store.
+func (x Big) Mul(y Big) Big {
+	return Big{new(big.Int).Mul(x.int(), y.int()), x.scale + y.scale}
+}
+
+// Div returns x / y rounded to scale decimal places with mode. a quotient
+// like 1/3 has no exact decimal form, which is why the scale is required.
+func (x Big) Div(y Big, scale int, mode rounding.Mode) (Big, error) {
+	if scale < 0 {
+		return Big{}, fmt.Errorf("decimal: %v / %v: negative scale", x, y)
+	}
+	if y.IsZero() {
+		return Big{}, fmt.Errorf("decimal: %v / %v: %w", x, y, ErrDivisionByZero)
+	}
+	return Big{divide(x.int(), x.scale, y.int(), y.scale, scale, mode), scale}, nil
+}
+
+// Round returns x rounded (or zero-extended) to scale decimal places. it
+// panics if scale is negative.
+func (x Big) Round(scale int, mode rounding.Mode) Big {
+	if scale < 0 {
+		panic("decimal: Round negative scale")
+	}
+	return Big{rescale(x.int(), x.scale, scale, mode), scale}
+}
+
+// Cmp returns -1, 0 or +1 as x is less than, equal to or greater than y.
+func (x Big) Cmp(y Big) int {
+	a, b, _ := alignBig(x, y)
+	return a.Cmp(b)
+}
+
+// Equal reports whether x and y are the same amount; 1.5 equals 1.50.
+func (x Big) Equal(y Big) bool { return x.Cmp(y) == 0 }
+
+// Fixed converts x to a Fixed with the same scale, failing with ErrScale or
+// ErrOverflow when it does not fit. round first to pick the scale.
+func (x Big) Fixed() (Fixed, error) {
+	return fixedFromBig(x.int(), x.scale, "converting "+x.String())
+}
+
+// Float64 returns the float64 nearest to x; ±Inf if it is out of range.
+func (x Big) Float64() float64 {
+	f, _ := strconv.ParseFloat(x.String(), 64)
+	return f
+}
+
+// String returns x with exactly Scale() decimal places.
+func (x Big) String() string { return format(x.int(), x.scale) }
+
+// Format implements fmt.Formatter like Fixed.Format.
+func (x Big) Format(f fmt.State, verb rune) { formatVerb(f, verb, x.int(), x.scale) }
@@ -0,0 +1,246 @@
+// Package decimal stores decimal numbers exactly, for money and anything
+// else where 0.1 + 0.2 must be 0.3.
+//
+// a float64 cannot hold 0.1 (it stores 0.1000000000000000055511151231257827...)
+// so sums of prices drift and rounding through math.Round(x*100)/100 rounds
+// the wrong number. a decimal keeps an integer count of units plus a scale:
+// 19.99 is 1999 units at scale 2. there are two types:
+//
+//	Fixed  int64 units, scale 0..18. fast, no allocations, errors on overflow
+//	Big    math/big units, any scale. slower, never overflows
+//
+// addition, subtraction and comparison are exact. multiplication and
+// division take the scale of the result and a rounding.Mode explicitly, so
+// every rounding in billing code is visible at the call site:
+//
+//	price := decimal.MustParseFixed("19.99")
+//	tax, err := price.Mul(decimal.MustParseFixed("0.0825"), 2, rounding.HalfAwayFromZero) // 1.65
+//
+// the modes are those of the rounding package, which rounds floats the same
+// way: rounding.HalfAwayFromZero is the rounding taught in school and java's
+// HALF_UP, rounding.HalfEven is banker's rounding.
+//
+// both types implement fmt.Formatter, encoding.TextMarshaler, json.Marshaler
+// (as a JSON string, so no float ever touches the value), sql.Scanner and
+// driver.Valuer.
+package decimal
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"float-methods/rounding"
+)
+
+var (
+	// ErrSyntax means the input is not a decimal number.
+	ErrSyntax = errors.New("invalid syntax")
+	// ErrOverflow means a Fixed result does not fit in int64 units.
+	ErrOverflow = errors.New("value out of range")
+	// ErrScale means a Fixed needs more than MaxScale decimal places.
+	ErrScale = errors.New("too many decimal places")
+	// ErrDivisionByZero is returned by Div when the divisor is zero.
+	ErrDivisionByZero = errors.New("division by zero")
+)
+
+// maxExponent bounds the exponent of parsed input like "1e999999999" so a
+// malicious string cannot allocate a gigantic number.
+const maxExponent = 100000
+
+// parse reads [+-]digits[.digits][(e|E)[+-]digits] into unscaled digits and
+// a scale, exactly. a negative scale is normalized away.
+func parse(s string) (*big.Int, int, error) {
+	fail := func() (*big.Int, int, error) {
+		return nil, 0, fmt.Errorf("decimal: parsing %q: %w", s, ErrSyntax)
+	}
+	i := 0
+	neg := false
+	if i < len(s) && (s[i] == '+' || s[i] == '-') {
+		neg = s[i] == '-'
+		i++
+	}
+	digits := make([]byte, 0, len(s))
+	scale, seen := 0, false
+	for ; i < len(s) && isDigit(s[i]); i++ {
+		digits, seen = append(digits, s[i]), true
+	}
+	if i < len(s) && s[i] == '.' {
+		for i++; i < len(s) && isDigit(s[i]); i++ {
+			digits, seen = append(digits, s[i]), true
+			scale++
+		}
+	}
+	if !seen {
+		return fail()
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		expNeg := false
+		if i < len(s) && (s[i] == '+' || s[i] == '-') {
+			expNeg = s[i] == '-'
+			i++
+		}
+		exp, expSeen := 0, false
+		for ; i < len(s) && isDigit(s[i]); i++ {
+			if exp = exp*10 + int(s[i]-'0'); exp > maxExponent {
+				return nil, 0, fmt.Errorf("decimal: parsing %q: exponent too large", s)
+			}
+			expSeen = true
+		}
+		if !expSeen {
+			return fail()
+		}
+		if expNeg {
+			exp = -exp
+		}
+		scale -= exp
+	}
+	if i != len(s) {
+		return fail()
+	}
+
+	u, _ := new(big.Int).SetString(string(digits), 10)
+	if scale < 0 {
+		u.Mul(u, pow10(-scale))
+		scale = 0
+	}
+	if neg {
+		u.Neg(u)
+	}
+	return u, scale, nil
+}
+
+func isDigit(c byte) bool { return '0' <= c && c <= '9' }
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// roundQuo returns n/d rounded to an integer with the given mode.
+func roundQuo(n, d *big.Int, mode rounding.Mode) *big.Int {
+	q, r := new(big.Int).QuoRem(n, d, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+	sign := int64(n.Sign() * d.Sign())
+	// compare the remainder with half the divisor: 2|r| vs |d|
+	half := new(big.Int).Abs(r)
+	half.Lsh(half, 1)
+	cmp := half.Cmp(new(big.Int).Abs(d))
+
+	away := false
+	switch mode {
+	case rounding.TowardZero:
+	case rounding.AwayFromZero:
+		away = true
+	case rounding.Ceiling:
+		away = sign > 0
+	case rounding.Floor:
+		away = sign < 0
+	case rounding.HalfUp:
+		away = cmp > 0 || cmp == 0 && sign > 0
+	case rounding.HalfDown:
+		away = cmp > 0 || cmp == 0 && sign < 0
+	case rounding.HalfAwayFromZero:
+		away = cmp >= 0
+	case rounding.HalfTowardZero:
+		away = cmp > 0
+	default: // HalfEven
+		away = cmp > 0 || cmp == 0 && q.Bit(0) == 1
+	}
+	if away {
+		q.Add(q, big.NewInt(sign))
+	}
+	return q
+}
+
+// rescale changes the scale of u from 'from' to 'to', rounding if digits are
+// dropped.
+func rescale(u *big.Int, from, to int, mode rounding.Mode) *big.Int {
+	switch {
+	case to > from:
+		return new(big.Int).Mul(u, pow10(to-from))
+	case to < from:
+		return roundQuo(u, pow10(from-to), mode)
+	}
+	return new(big.Int).Set(u)
+}
+
+// divide returns (n / 10^ns) / (d / 10^ds) as units at scale, rounded with
+// mode. d must not be zero.
+func divide(n *big.Int, ns int, d *big.Int, ds, scale int, mode rounding.Mode) *big.Int {
+	// n/10^ns / (d/10^ds) * 10^scale = n * 10^(scale+ds-ns) / d
+	if e := scale + ds - ns; e >= 0 {
+		n = new(big.Int).Mul(n, pow10(e))
+	} else {
+		d = new(big.Int).Mul(d, pow10(-e))
+	}
+	return roundQuo(n, d, mode)
+}
+
+// format writes u / 10^scale in plain decimal notation.
+func format(u *big.Int, scale int) string {
+	return formatDigits(u.Sign() < 0, new(big.Int).Abs(u).String(), scale)
+}
+
+// formatDigits places the decimal point scale digits from the right of the
+// magnitude digits, padding with leading zeros as needed.
+func formatDigits(neg bool, digits string, scale int) string {
+	if scale > 0 {
+		if len(digits) <= scale {
+			digits = strings.Repeat("0", scale-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+	}
+	if neg {
+		digits = "-" + digits
+	}
+	return digits
+}
+
+// formatVerb implements fmt.Formatter for both types: %v and %s print the
+// value at its own scale, %.Nf rounds half-even to N places (%f alone keeps
+// the scale) and %q quotes. width and the '-', '+' and '0' flags work as for
+// numbers.
+func formatVerb(f fmt.State, verb rune, u *big.Int, scale int) {
+	switch verb {
+	case 'v', 's', 'f', 'F', 'q':
+	default:
+		fmt.Fprintf(f, "%%!%c(decimal=%s)", verb, format(u, scale))
+		return
+	}
+	if prec, ok := f.Precision(); ok && (verb == 'f' || verb == 'F') {
+		u, scale = rescale(u, scale, prec, rounding.HalfEven), prec
+	}
+	s := format(u, scale)
+	if f.Flag('+') && u.Sign() >= 0 {
+		s = "+" + s
+	}
+	if verb == 'q' {
+		s = strconv.Quote(s)
+	}
+	width, ok := f.Width()
+	if !ok || len(s) >= width {
+		io.WriteString(f, s)
+		return
+	}
+	pad := strings.Repeat(" ", width-len(s))
+	switch {
+	case f.Flag('-'):
+		s += pad
+	case f.Flag('0') && verb != 'q':
+		// zeros go between the sign and the digits
+		sign := ""
+		if s[0] == '-' || s[0] == '+' {
+			sign, s = s[:1], s[1:]
+		}
+		s = sign + strings.Repeat("0", len(pad)) + s
+	default:
+		s = pad + s
+	}
+	io.WriteString(f, s)
+}
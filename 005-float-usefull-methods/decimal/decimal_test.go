@@ -0,0 +1,76 @@
+package decimal
+
+import (
+	"strings"
+	"testing"
+
+	"float-methods/rounding"
+)
+
+// TestRoundLikeRounding checks that decimals round as the rounding package
+// rounds the same digits, in every mode. a decimal has no -0.
+func TestRoundLikeRounding(t *testing.T) {
+	values := []string{
+		"2.5", "-2.5", "3.5", "-3.5", "-1234.5", "2.45", "-2.45", "1.005", "-1.005",
+		"2.675", "0.125", "-0.125", "7", "-7.01", "9.995", "-9.995", "0.0049", "1.2345678901234567890123",
+	}
+	for _, s := range values {
+		for _, m := range rounding.Modes {
+			for n := range 3 {
+				want, err := rounding.Decimal(s, n, m)
+				if err != nil {
+					t.Fatal(err)
+				}
+				want = noNegativeZero(want)
+				if got := MustParseBig(s).Round(n, m).String(); got != want {
+					t.Errorf("Big %s.Round(%d, %v) = %s, want %s", s, n, m, got, want)
+				}
+				x, err := ParseFixed(s)
+				if err != nil {
+					continue // more places than a Fixed holds
+				}
+				if got, err := x.Round(n, m); err != nil || got.String() != want {
+					t.Errorf("Fixed %s.Round(%d, %v) = %s, %v, want %s", s, n, m, got, err, want)
+				}
+			}
+		}
+	}
+}
+
+func noNegativeZero(s string) string {
+	if strings.Trim(s, "-0.") == "" {
+		return strings.TrimPrefix(s, "-")
+	}
+	return s
+}
+
+func TestMulDivModes(t *testing.T) {
+	tests := []struct {
+		x, y string
+		mode rounding.Mode
+		mul  string
+		div  string
+	}{
+		{"19.99", "0.0825", rounding.HalfAwayFromZero, "1.65", "242.30"},
+		{"-2.5", "1", rounding.HalfUp, "-2", "-2"},
+		{"-2.5", "1", rounding.HalfDown, "-3", "-3"},
+		{"-2.5", "1", rounding.HalfTowardZero, "-2", "-2"},
+		{"2", "3", rounding.Floor, "6", "0"},
+		{"-2", "3", rounding.Floor, "-6", "-1"},
+		{"-2", "3", rounding.Ceiling, "-6", "0"},
+		{"1", "3", rounding.AwayFromZero, "3", "1"},
+	}
+	for _, tt := range tests {
+		x, y := MustParseFixed(tt.x), MustParseFixed(tt.y)
+		scale := 0
+		if tt.mode == rounding.HalfAwayFromZero {
+			scale = 2
+		}
+		if got, err := x.Mul(y, scale, tt.mode); err != nil || got.String() != tt.mul {
+			t.Errorf("%s.Mul(%s, %d, %v) = %s, %v, want %s", tt.x, tt.y, scale, tt.mode, got, err, tt.mul)
+		}
+		if got, err := x.Div(y, scale, tt.mode); err != nil || got.String() != tt.div {
+			t.Errorf("%s.Div(%s, %d, %v) = %s, %v, want %s", tt.x, tt.y, scale, tt.mode, got, err, tt.div)
+		}
+	}
+}
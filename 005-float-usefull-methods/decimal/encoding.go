@@ -0,0 +1,125 @@
+package decimal
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+)
+
+// JSON uses a string ("19.99") so the value survives decoders that turn
+// numbers into float64, e.g. javascript's. decoding also accepts a bare
+// number, which is parsed from its text and never goes through a float.
+// null leaves the value unchanged, as encoding/json does for numbers.
+//
+// databases get the decimal string too, which NUMERIC/DECIMAL columns accept.
+// scanning takes a string or []byte (what drivers return for NUMERIC), an
+// int64, or a float64 converted through its shortest representation, so a
+// REAL column holding 0.1 scans as 0.1. NULL is an error: use sql.Null[Fixed]
+// for nullable columns.
+
+// unquoteJSON returns the text of a JSON string or number, and ok = false
+// for null.
+func unquoteJSON(data []byte) (s string, ok bool, err error) {
+	if string(data) == "null" {
+		return "", false, nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		s, err := strconv.Unquote(string(data))
+		if err != nil {
+			return "", false, fmt.Errorf("decimal: invalid JSON string %s", data)
+		}
+		return s, true, nil
+	}
+	return string(data), true, nil
+}
+
+// scanText converts a database value to decimal text.
+func scanText(src any) (string, error) {
+	switch v := src.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case nil:
+		return "", fmt.Errorf("decimal: cannot scan NULL, use sql.Null")
+	}
+	return "", fmt.Errorf("decimal: cannot scan %T", src)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (x Fixed) MarshalText() ([]byte, error) { return []byte(x.String()), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (x *Fixed) UnmarshalText(text []byte) error {
+	v, err := ParseFixed(string(text))
+	if err != nil {
+		return err
+	}
+	*x = v
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, writing a JSON string.
+func (x Fixed) MarshalJSON() ([]byte, error) { return strconv.AppendQuote(nil, x.String()), nil }
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (x *Fixed) UnmarshalJSON(data []byte) error {
+	s, ok, err := unquoteJSON(data)
+	if !ok || err != nil {
+		return err
+	}
+	return x.UnmarshalText([]byte(s))
+}
+
+// Scan implements sql.Scanner.
+func (x *Fixed) Scan(src any) error {
+	s, err := scanText(src)
+	if err != nil {
+		return err
+	}
+	return x.UnmarshalText([]byte(s))
+}
+
+// Value implements driver.Valuer.
+func (x Fixed) Value() (driver.Value, error) { return x.String(), nil }
+
+// MarshalText implements encoding.TextMarshaler.
+func (x Big) MarshalText() ([]byte, error) { return []byte(x.String()), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (x *Big) UnmarshalText(text []byte) error {
+	v, err := ParseBig(string(text))
+	if err != nil {
+		return err
+	}
+	*x = v
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, writing a JSON string.
+func (x Big) MarshalJSON() ([]byte, error) { return strconv.AppendQuote(nil, x.String()), nil }
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (x *Big) UnmarshalJSON(data []byte) error {
+	s, ok, err := unquoteJSON(data)
+	if !ok || err != nil {
+		return err
+	}
+	return x.UnmarshalText([]byte(s))
+}
+
+// Scan implements sql.Scanner.
+func (x *Big) Scan(src any) error {
+	s, err := scanText(src)
+	if err != nil {
+		return err
+	}
+	return x.UnmarshalText([]byte(s))
+}
+
+// Value implements driver.Valuer.
+func (x Big) Value() (driver.Value, error) { return x.String(), nil }
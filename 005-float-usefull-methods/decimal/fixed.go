@@ -0,0 +1,260 @@
+package decimal
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+	"strconv"
+
+	"float-methods/rounding"
+)
+
+// MaxScale is the largest scale a Fixed supports: 10^18 still fits in int64.
+const MaxScale = 18
+
+// Fixed is units / 10^scale with int64 units, e.g. 19.99 is 1999 at scale 2.
+// the zero value is 0. values are compared by amount, so 1.5 and 1.50 are
+// equal, but each keeps the scale it was made with and String prints it.
+type Fixed struct {
+	units int64
+	scale int
+}
+
+// int64 powers of ten, 10^0 through 10^18.
+var pow10s = func() [MaxScale + 1]int64 {
+	var p [MaxScale + 1]int64
+	p[0] = 1
+	for i := 1; i < len(p); i++ {
+		p[i] = p[i-1] * 10
+	}
+	return p
+}()
+
+// NewFixed returns units / 10^scale: NewFixed(1999, 2) is 19.99. it panics if
+// scale is outside 0..MaxScale.
+func NewFixed(units int64, scale int) Fixed {
+	if scale < 0 || scale > MaxScale {
+		panic("decimal: NewFixed scale out of range")
+	}
+	return Fixed{units, scale}
+}
+
+// ParseFixed parses s exactly. the scale is the number of decimal places
+// written, so "1.50" has scale 2; trailing zeros beyond MaxScale are
+// dropped, any other digit beyond it is ErrScale. an exponent is allowed:
+// "1.5e3" is 1500.
+func ParseFixed(s string) (Fixed, error) {
+	u, scale, err := parse(s)
+	if err != nil {
+		return Fixed{}, err
+	}
+	return fixedFromBig(u, scale, "parsing "+strconv.Quote(s))
+}
+
+// MustParseFixed is ParseFixed for constants; it panics on error.
+func MustParseFixed(s string) Fixed {
+	x, err := ParseFixed(s)
+	if err != nil {
+		panic(err)
+	}
+	return x
+}
+
+// fixedFromBig converts exactly, trimming trailing zeros to fit MaxScale.
+func fixedFromBig(u *big.Int, scale int, op string) (Fixed, error) {
+	if scale > MaxScale {
+		q, r := new(big.Int).QuoRem(u, pow10(scale-MaxScale), new(big.Int))
+		if r.Sign() != 0 {
+			return Fixed{}, fmt.Errorf("decimal: %s: %w", op, ErrScale)
+		}
+		u, scale = q, MaxScale
+	}
+	if !u.IsInt64() {
+		return Fixed{}, fmt.Errorf("decimal: %s: %w", op, ErrOverflow)
+	}
+	return Fixed{u.Int64(), scale}, nil
+}
+
+// Units returns the unscaled integer: 1999 for 19.99 at scale 2.
+func (x Fixed) Units() int64 { return x.units }
+
+// Scale returns the number of decimal places.
+func (x Fixed) Scale() int { return x.scale }
+
+// Sign returns -1, 0 or +1.
+func (x Fixed) Sign() int {
+	switch {
+	case x.units < 0:
+		return -1
+	case x.units > 0:
+		return 1
+	}
+	return 0
+}
+
+// IsZero reports whether x is 0 at any scale.
+func (x Fixed) IsZero() bool { return x.units == 0 }
+
+// Neg returns -x. it fails only for the most negative units value.
+func (x Fixed) Neg() (Fixed, error) {
+	if x.units == math.MinInt64 {
+		return Fixed{}, fmt.Errorf("decimal: negating %v: %w", x, ErrOverflow)
+	}
+	return Fixed{-x.units, x.scale}, nil
+}
+
+// Abs returns |x|. it fails only for the most negative units value.
+func (x Fixed) Abs() (Fixed, error) {
+	if x.units < 0 {
+		return x.Neg()
+	}
+	return x, nil
+}
+
+// rescaleUp multiplies the units by 10^n, reporting overflow.
+func (x Fixed) rescaleUp(n int) (int64, bool) {
+	if n == 0 {
+		return x.units, true
+	}
+	p := pow10s[n]
+	r := x.units * p
+	if r/p != x.units {
+		return 0, false
+	}
+	return r, true
+}
+
+// Add returns x + y exactly at the larger of the two scales.
+func (x Fixed) Add(y Fixed) (Fixed, error) {
+	a, b, scale, ok := align(x, y)
+	if ok {
+		if s, overflow := add64(a, b); !overflow {
+			return Fixed{s, scale}, nil
+		}
+	}
+	return Fixed{}, fmt.Errorf("decimal: %v + %v: %w", x, y, ErrOverflow)
+}
+
+// Sub returns x - y exactly at the larger of the two scales.
+func (x Fixed) Sub(y Fixed) (Fixed, error) {
+	a, b, scale, ok := align(x, y)
+	if ok && b != math.MinInt64 {
+		if s, overflow := add64(a, -b); !overflow {
+			return Fixed{s, scale}, nil
+		}
+	}
+	return Fixed{}, fmt.Errorf("decimal: %v - %v: %w", x, y, ErrOverflow)
+}
+
+// align brings x and y to the larger scale.
+func align(x, y Fixed) (a, b int64, scale int, ok bool) {
+	scale = max(x.scale, y.scale)
+	a, okA := x.rescaleUp(scale - x.scale)
+	b, okB := y.rescaleUp(scale - y.scale)
+	return a, b, scale, okA && okB
+}
+
+func add64(a, b int64) (sum int64, overflow bool) {
+	sum = a + b
+	// overflow iff both operands have the same sign and the sum does not
+	return sum, (a >= 0) == (b >= 0) && (sum >= 0) != (a >= 0)
+}
+
+// Mul returns x * y rounded to scale decimal places with mode. the exact
+// product has scale x.Scale()+y.Scale(), so rounding happens once.
+func (x Fixed) Mul(y Fixed, scale int, mode rounding.Mode) (Fixed, error) {
+	if scale < 0 || scale > MaxScale {
+		return Fixed{}, fmt.Errorf("decimal: %v * %v: %w", x, y, ErrScale)
+	}
+	if hi, lo := bits.Mul64(absU(x.units), absU(y.units)); hi == 0 && lo <= math.MaxInt64 && x.scale+y.scale == scale {
+		// fast path: the exact product already fits
+		p := int64(lo)
+		if (x.units < 0) != (y.units < 0) {
+			p = -p
+		}
+		return Fixed{p, scale}, nil
+	}
+	u := new(big.Int).Mul(big.NewInt(x.units), big.NewInt(y.units))
+	u = rescale(u, x.scale+y.scale, scale, mode)
+	if !u.IsInt64() {
+		return Fixed{}, fmt.Errorf("decimal: %v * %v: %w", x, y, ErrOverflow)
+	}
+	return Fixed{u.Int64(), scale}, nil
+}
+
+func absU(v int64) uint64 {
+	if v < 0 {
+		return uint64(-v) // also right for MinInt64
+	}
+	return uint64(v)
+}
+
+// Div returns x / y rounded to scale decimal places with mode.
+func (x Fixed) Div(y Fixed, scale int, mode rounding.Mode) (Fixed, error) {
+	if scale < 0 || scale > MaxScale {
+		return Fixed{}, fmt.Errorf("decimal: %v / %v: %w", x, y, ErrScale)
+	}
+	if y.units == 0 {
+		return Fixed{}, fmt.Errorf("decimal: %v / %v: %w", x, y, ErrDivisionByZero)
+	}
+	u := divide(big.NewInt(x.units), x.scale, big.NewInt(y.units), y.scale, scale, mode)
+	if !u.IsInt64() {
+		return Fixed{}, fmt.Errorf("decimal: %v / %v: %w", x, y, ErrOverflow)
+	}
+	return Fixed{u.Int64(), scale}, nil
+}
+
+// Round returns x rounded (or zero-extended) to scale decimal places.
+func (x Fixed) Round(scale int, mode rounding.Mode) (Fixed, error) {
+	if scale < 0 || scale > MaxScale {
+		return Fixed{}, fmt.Errorf("decimal: rounding %v: %w", x, ErrScale)
+	}
+	if scale >= x.scale {
+		if u, ok := x.rescaleUp(scale - x.scale); ok {
+			return Fixed{u, scale}, nil
+		}
+		return Fixed{}, fmt.Errorf("decimal: rounding %v: %w", x, ErrOverflow)
+	}
+	// rounding away from zero can only overflow at ±MaxInt64 / 10^k, which
+	// still fits, so this cannot fail
+	u := roundQuo(big.NewInt(x.units), big.NewInt(pow10s[x.scale-scale]), mode)
+	return Fixed{u.Int64(), scale}, nil
+}
+
+// Cmp returns -1, 0 or +1 as x is less than, equal to or greater than y.
+func (x Fixed) Cmp(y Fixed) int {
+	if a, b, _, ok := align(x, y); ok {
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+		return 0
+	}
+	return x.Big().Cmp(y.Big())
+}
+
+// Equal reports whether x and y are the same amount; 1.5 equals 1.50.
+func (x Fixed) Equal(y Fixed) bool { return x.Cmp(y) == 0 }
+
+// Big returns x as a Big with the same scale.
+func (x Fixed) Big() Big { return Big{big.NewInt(x.units), x.scale} }
+
+// Float64 returns the float64 nearest to x.
+func (x Fixed) Float64() float64 {
+	f, _ := strconv.ParseFloat(x.String(), 64)
+	return f
+}
+
+// String returns x with exactly Scale() decimal places, e.g. "19.90".
+func (x Fixed) String() string {
+	return formatDigits(x.units < 0, strconv.FormatUint(absU(x.units), 10), x.scale)
+}
+
+// Format implements fmt.Formatter: %v and %s print String, %.2f rounds half
+// even to 2 places, %q quotes, and width and flags pad like a number.
+func (x Fixed) Format(f fmt.State, verb rune) {
+	formatVerb(f, verb, big.NewInt(x.units), x.scale)
+}
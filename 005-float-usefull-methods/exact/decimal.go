@@ -0,0 +1,158 @@
+package exact
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// MaxDigits is the number of digits after the point String writes before it
+// gives up on finding the repetend and ends with "...". the repetend of 1/n
+// can be n-1 digits long.
+const MaxDigits = 1000
+
+// maxExponent bounds the exponent Parse accepts, so "1e999999999" is an
+// error instead of a number with a billion digits.
+const maxExponent = 100000
+
+// String writes r in decimal with the repeating part in parentheses:
+// 1/3 is "0.(3)", 1/6 is "0.1(6)", 22/7 is "3.(142857)" and 1/8 is "0.125".
+// after MaxDigits digits without finding the repetend it writes "...".
+func String(r *big.Rat) string { return Format(r, MaxDigits) }
+
+// Format is String with a limit of maxDigits digits after the point. below
+// 1 it writes no digits, only the integer part and "...": 1/3 is "0...".
+func Format(r *big.Rat, maxDigits int) string {
+	var b strings.Builder
+	num := new(big.Int).Abs(r.Num())
+	den := r.Denom()
+	if r.Sign() < 0 {
+		b.WriteByte('-')
+	}
+	intPart, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	b.WriteString(intPart.String())
+	if rem.Sign() == 0 {
+		return b.String()
+	}
+
+	// long division, remembering at which digit each remainder was seen:
+	// when one comes back, the digits since then repeat forever
+	seen := map[string]int{}
+	var digits []byte
+	ten, d := big.NewInt(10), new(big.Int)
+	for rem.Sign() != 0 {
+		key := rem.String()
+		if at, ok := seen[key]; ok {
+			return b.String() + "." + string(digits[:at]) + "(" + string(digits[at:]) + ")"
+		}
+		if len(digits) >= maxDigits {
+			if len(digits) == 0 {
+				return b.String() + "..."
+			}
+			return b.String() + "." + string(digits) + "..."
+		}
+		seen[key] = len(digits)
+		rem.Mul(rem, ten)
+		d.QuoRem(rem, den, rem)
+		digits = append(digits, byte('0'+d.Int64()))
+	}
+	return b.String() + "." + string(digits)
+}
+
+// Parse reads a decimal number exactly: "0.1" is 1/10, not the float 0.1.
+// it accepts an optional sign, digits with an optional point, a repetend in
+// parentheses after the fraction and an exponent: "-12.5", "0.1(6)",
+// "3.(142857)", "1.5e-3", "0.(9)" (which is 1). a fraction "a/b" is also
+// accepted.
+func Parse(s string) (*big.Rat, error) {
+	if num, den, ok := strings.Cut(s, "/"); ok {
+		n, err1 := parse(num)
+		d, err2 := parse(den)
+		if err1 != nil || err2 != nil || d.Sign() == 0 {
+			return nil, fmt.Errorf("exact: parsing %q: %w", s, ErrSyntax)
+		}
+		return n.Quo(n, d), nil
+	}
+	r, err := parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("exact: parsing %q: %w", s, err)
+	}
+	return r, nil
+}
+
+// MustParse is like Parse but panics on malformed input; for constants in
+// tests and examples.
+func MustParse(s string) *big.Rat {
+	r, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+func parse(s string) (*big.Rat, error) {
+	neg := false
+	if s != "" && (s[0] == '+' || s[0] == '-') {
+		neg, s = s[0] == '-', s[1:]
+	}
+	exp := 0
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.Atoi(s[i+1:])
+		if err != nil {
+			return nil, ErrSyntax
+		}
+		if e > maxExponent || e < -maxExponent {
+			return nil, ErrRange
+		}
+		s, exp = s[:i], e
+	}
+	rep := ""
+	if i := strings.IndexByte(s, '('); i >= 0 {
+		if !strings.HasSuffix(s, ")") || !strings.Contains(s[:i], ".") {
+			return nil, ErrSyntax
+		}
+		s, rep = s[:i], s[i+1:len(s)-1]
+		if rep == "" {
+			return nil, ErrSyntax
+		}
+	}
+	intPart, frac, _ := strings.Cut(s, ".")
+	if intPart == "" && frac == "" && rep == "" || !digits(intPart) || !digits(frac) || !digits(rep) {
+		return nil, ErrSyntax
+	}
+
+	// intPart.frac as an integer over 10^len(frac), plus the repetend:
+	// 0.000(rep) is rep / (10^len(rep) - 1) / 10^len(frac)
+	r, _ := new(big.Rat).SetString("0" + intPart + frac)
+	scale := pow10(len(frac))
+	r.Quo(r, new(big.Rat).SetInt(scale))
+	if rep != "" {
+		x, _ := new(big.Rat).SetString(rep)
+		nines := new(big.Int).Sub(pow10(len(rep)), big.NewInt(1))
+		x.Quo(x, new(big.Rat).SetInt(new(big.Int).Mul(nines, scale)))
+		r.Add(r, x)
+	}
+	if exp > 0 {
+		r.Mul(r, new(big.Rat).SetInt(pow10(exp)))
+	} else if exp < 0 {
+		r.Quo(r, new(big.Rat).SetInt(pow10(-exp)))
+	}
+	if neg {
+		r.Neg(r)
+	}
+	return r, nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+func digits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
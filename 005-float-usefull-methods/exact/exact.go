@@ -0,0 +1,112 @@
+// Package exact turns floats and decimal strings into exact rationals
+// (math/big.Rat) and back, so the float lessons can prove their claims
+// instead of printing 17 digits and hoping:
+//
+//	a, _ := exact.FromFloat64(0.1)
+//	b, _ := exact.FromFloat64(0.2)
+//	sum := new(big.Rat).Add(a, b)         // what 0.1 + 0.2 would be without rounding
+//	f, dir := exact.Float64(sum)          // 0.30000000000000004, rounded Up
+//	exact.String(exact.MustParse("0.3"))  // "0.3": a different number than f
+//
+// every float64 is a fraction m / 2^k, so its decimal expansion always ends:
+// Float prints it in full. a general rational, like 1/6, may repeat forever;
+// String marks the repeating digits, the repetend, in parentheses: 0.1(6).
+// Parse reads that notation back.
+package exact
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+var (
+	// ErrSyntax means the input is not a number Parse understands.
+	ErrSyntax = errors.New("invalid syntax")
+	// ErrRange means an exponent is too large to expand exactly.
+	ErrRange = errors.New("exponent out of range")
+	// ErrNotFinite means a NaN or infinity, which has no rational value.
+	ErrNotFinite = errors.New("not a finite number")
+)
+
+// Rounding says which way a conversion to float moved the value.
+type Rounding int
+
+const (
+	Exact Rounding = iota // the float is the rational
+	Down                  // the float is smaller, closer to -Inf
+	Up                    // the float is larger, closer to +Inf
+)
+
+func (r Rounding) String() string {
+	switch r {
+	case Exact:
+		return "Exact"
+	case Down:
+		return "Down"
+	case Up:
+		return "Up"
+	}
+	return fmt.Sprintf("Rounding(%d)", int(r))
+}
+
+// FromFloat64 returns the exact value of x: 0.1 is
+// 3602879701896397/36028797018963968. -0 becomes 0.
+func FromFloat64(x float64) (*big.Rat, error) {
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		return nil, fmt.Errorf("exact: %v: %w", x, ErrNotFinite)
+	}
+	return new(big.Rat).SetFloat64(x), nil
+}
+
+// FromFloat32 returns the exact value of x: float32(0.1) is
+// 13421773/134217728.
+func FromFloat32(x float32) (*big.Rat, error) {
+	return FromFloat64(float64(x)) // widening is exact
+}
+
+// Float returns every decimal digit of x, without an exponent:
+// Float(0.1) is "0.1000000000000000055511151231257827021181583404541015625".
+// NaN and infinities are written as "NaN", "+Inf" and "-Inf".
+func Float(x float64) string {
+	r, err := FromFloat64(x)
+	if err != nil {
+		return fmt.Sprint(x)
+	}
+	if math.Signbit(x) && r.Sign() == 0 {
+		return "-0"
+	}
+	// the denominator is 2^k, and 1/2^k = 5^k/10^k has exactly k decimals:
+	// 5e-324 is 2^-1074, 1074 of them, more than String would write
+	return Format(r, r.Denom().BitLen()-1)
+}
+
+// Float64 returns the float64 nearest to r, ties to even, and the direction
+// it was rounded in. values beyond the float64 range give ±Inf.
+func Float64(r *big.Rat) (float64, Rounding) {
+	f, _ := r.Float64()
+	return f, direction(f, r)
+}
+
+// Float32 is Float64 for float32.
+func Float32(r *big.Rat) (float32, Rounding) {
+	f, _ := r.Float32()
+	return f, direction(float64(f), r)
+}
+
+func direction(f float64, r *big.Rat) Rounding {
+	switch {
+	case math.IsInf(f, 1):
+		return Up
+	case math.IsInf(f, -1):
+		return Down
+	}
+	switch new(big.Rat).SetFloat64(f).Cmp(r) {
+	case -1:
+		return Down
+	case 1:
+		return Up
+	}
+	return Exact
+}
@@ -0,0 +1,208 @@
+package exact
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestStringParse(t *testing.T) {
+	tests := []struct {
+		r string // as big.Rat.SetString reads it
+		s string
+	}{
+		{"0", "0"},
+		{"1/3", "0.(3)"},
+		{"-1/3", "-0.(3)"},
+		{"1/6", "0.1(6)"},
+		{"22/7", "3.(142857)"},
+		{"1/8", "0.125"},
+		{"-5/2", "-2.5"},
+		{"1/7", "0.(142857)"},
+		{"1/12", "0.08(3)"},
+		{"1/97", "0.(010309278350515463917525773195876288659793814432989690721649484536082474226804123711340206185567)"},
+		{"123456789/1000", "123456.789"},
+		{"1/1024", "0.0009765625"},
+	}
+	for _, tt := range tests {
+		r, _ := new(big.Rat).SetString(tt.r)
+		if got := String(r); got != tt.s {
+			t.Errorf("String(%s) = %q, want %q", tt.r, got, tt.s)
+		}
+		got, err := Parse(tt.s)
+		if err != nil || got.Cmp(r) != 0 {
+			t.Errorf("Parse(%q) = %v, %v, want %s", tt.s, got, err, tt.r)
+		}
+	}
+}
+
+// TestRoundTrip parses back what String writes for random fractions; with
+// denominators below MaxDigits the repetend is always found.
+func TestRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for range 2000 {
+		r := big.NewRat(rng.Int63n(1e12)-5e11, 1+rng.Int63n(MaxDigits-1))
+		s := String(r)
+		got, err := Parse(s)
+		if err != nil || got.Cmp(r) != 0 {
+			t.Fatalf("Parse(String(%v)) = Parse(%q) = %v, %v", r, s, got, err)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct{ s, want string }{
+		{"0.(9)", "1"},
+		{"1.5e-3", "3/2000"},
+		{"1.5E3", "1500"},
+		{"+.5", "1/2"},
+		{"7.", "7"},
+		{"-0.1(6)e1", "-5/3"},
+		{"3/6", "1/2"},
+		{"0.5/0.(3)", "3/2"},
+		{"0001.2500", "5/4"},
+	}
+	for _, tt := range tests {
+		want, _ := new(big.Rat).SetString(tt.want)
+		if got, err := Parse(tt.s); err != nil || got.Cmp(want) != 0 {
+			t.Errorf("Parse(%q) = %v, %v, want %v", tt.s, got, err, want)
+		}
+	}
+	errs := []struct {
+		s   string
+		err error
+	}{
+		{"", ErrSyntax},
+		{".", ErrSyntax},
+		{"-", ErrSyntax},
+		{"1.2.3", ErrSyntax},
+		{"0x10", ErrSyntax},
+		{"1(3)", ErrSyntax},
+		{"0.()", ErrSyntax},
+		{"0.(3", ErrSyntax},
+		{"1e", ErrSyntax},
+		{"1/0", ErrSyntax},
+		{"1/", ErrSyntax},
+		{"1e999999", ErrRange},
+		{"1e-999999", ErrRange},
+	}
+	for _, tt := range errs {
+		if _, err := Parse(tt.s); !errors.Is(err, tt.err) {
+			t.Errorf("Parse(%q): error %v, want %v", tt.s, err, tt.err)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		r         string
+		maxDigits int
+		want      string
+	}{
+		{"1/3", 0, "0..."},
+		{"-1/3", -1, "-0..."},
+		{"10/3", 0, "3..."},
+		{"1/3", 1, "0.(3)"}, // the repetend is found before the limit
+		{"1/7", 1, "0.1..."},
+		{"1/6", 2, "0.1(6)"},
+		{"1/7", 3, "0.142..."},
+		{"1/8", 3, "0.125"},
+		{"1/16", 3, "0.062..."},
+		{"3", 0, "3"},
+	}
+	for _, tt := range tests {
+		r, _ := new(big.Rat).SetString(tt.r)
+		if got := Format(r, tt.maxDigits); got != tt.want {
+			t.Errorf("Format(%s, %d) = %q, want %q", tt.r, tt.maxDigits, got, tt.want)
+		}
+	}
+}
+
+func TestFloat(t *testing.T) {
+	tests := []struct {
+		x    float64
+		want string
+	}{
+		{0.1, "0.1000000000000000055511151231257827021181583404541015625"},
+		{0.5, "0.5"},
+		{-2, "-2"},
+		{1e21, "1000000000000000000000"},
+		{math.Copysign(0, -1), "-0"},
+		{math.Inf(1), "+Inf"},
+		{math.NaN(), "NaN"},
+	}
+	for _, tt := range tests {
+		if got := Float(tt.x); got != tt.want {
+			t.Errorf("Float(%v) = %q, want %q", tt.x, got, tt.want)
+		}
+	}
+	// 2^-1074 has 1074 decimals, more than MaxDigits, and they all show
+	if got := Float(5e-324); len(got) != len("0.")+1074 || got[len(got)-1] != '5' {
+		t.Errorf("Float(5e-324) = %.20q..., %d bytes", got, len(got))
+	}
+}
+
+func TestFloatDirection(t *testing.T) {
+	tests := []struct {
+		r     string
+		f64   float64
+		dir64 Rounding
+		f32   float32
+		dir32 Rounding
+	}{
+		{"1/10", 0.1, Up, 0.1, Up},
+		{"3/10", 0.3, Down, 0.3, Up},
+		{"1/2", 0.5, Exact, 0.5, Exact},
+		{"-1/10", -0.1, Down, -0.1, Down},
+		{"1/3", 1.0 / 3, Down, 1.0 / 3, Up},
+		{"16777217", 16777217, Exact, 16777216, Down},
+		{"1e400", math.Inf(1), Up, float32(math.Inf(1)), Up},
+		{"-1e400", math.Inf(-1), Down, float32(math.Inf(-1)), Down},
+		{"1e-400", 0, Down, 0, Down},
+		{"-1e-400", math.Copysign(0, -1), Up, float32(math.Copysign(0, -1)), Up},
+	}
+	for _, tt := range tests {
+		r := MustParse(tt.r)
+		if f, dir := Float64(r); f != tt.f64 || dir != tt.dir64 {
+			t.Errorf("Float64(%s) = %v, %v, want %v, %v", tt.r, f, dir, tt.f64, tt.dir64)
+		}
+		if f, dir := Float32(r); f != tt.f32 || dir != tt.dir32 {
+			t.Errorf("Float32(%s) = %v, %v, want %v, %v", tt.r, f, dir, tt.f32, tt.dir32)
+		}
+	}
+}
+
+// TestFloat64Nearest checks on random fractions that Float64 returns the
+// nearest float and the side it is on: the float and its neighbour on the
+// other side of r enclose r, and the neighbour is not closer.
+func TestFloat64Nearest(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for range 5000 {
+		r := big.NewRat(rng.Int63()-rng.Int63(), 1+rng.Int63n(1<<rng.Intn(62)))
+		f, dir := Float64(r)
+		fr := new(big.Rat).SetFloat64(f)
+		var other float64
+		switch dir {
+		case Exact:
+			if fr.Cmp(r) != 0 {
+				t.Fatalf("Float64(%v) = %v, Exact, but they differ", r, f)
+			}
+			continue
+		case Down:
+			other = math.Nextafter(f, math.Inf(1))
+		case Up:
+			other = math.Nextafter(f, math.Inf(-1))
+		}
+		or := new(big.Rat).SetFloat64(other)
+		if (dir == Down) != (fr.Cmp(r) < 0) || (dir == Down) != (or.Cmp(r) > 0) {
+			t.Fatalf("Float64(%v) = %v, %v: %v and %v do not enclose it", r, f, dir, f, other)
+		}
+		near := new(big.Rat).Abs(new(big.Rat).Sub(fr, r))
+		far := new(big.Rat).Abs(new(big.Rat).Sub(or, r))
+		if near.Cmp(far) > 0 {
+			t.Fatalf("Float64(%v) = %v, but %v is closer", r, f, other)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Package floatcmp compares floats the ways the lesson's floatEquals cannot.
+//
+// an absolute epsilon like 1e-9 is far too loose for values around 1e-12 and
+// far too strict for values around 1e12 (where neighbouring float64 values are
+// already 1e-4 apart). this package offers four comparisons, each for float32
+// and float64:
+//
+//	AbsEqual(a, b, 1e-9)         |a-b| <= tol, for values near a known scale
+//	RelEqual(a, b, 1e-9)         |a-b| <= tol * max(|a|, |b|), scale free
+//	Equal(a, b, abs, rel)        either one, the usual choice: rel for normal
+//	                             values, abs as a floor near zero
+//	ULPEqual(a, b, 4)            at most 4 representable floats apart
+//
+// special values follow one rule set everywhere:
+//   - NaN is never equal to anything, not even NaN (Tolerance.NaNEqual changes
+//     that for tests)
+//   - +Inf equals only +Inf and -Inf only -Inf, tolerances never apply
+//   - +0 and -0 are equal and 0 ULPs apart
+//   - subnormals are ordinary values: ULP distance counts them one by one and
+//     relative comparison treats them like any other magnitude, which is why
+//     Equal needs the absolute floor near zero
+package floatcmp
+
+import (
+	"math"
+	"unsafe"
+)
+
+// Float is float32 or float64.
+type Float interface {
+	~float32 | ~float64
+}
+
+// special handles NaN and infinities. done reports whether the result is
+// decided; otherwise both values are finite.
+func special[F Float](a, b F, nanEqual bool) (equal, done bool) {
+	an, bn := math.IsNaN(float64(a)), math.IsNaN(float64(b))
+	if an || bn {
+		return an && bn && nanEqual, true
+	}
+	if math.IsInf(float64(a), 0) || math.IsInf(float64(b), 0) {
+		return a == b, true
+	}
+	if a == b {
+		// also catches +0 == -0
+		return true, true
+	}
+	return false, false
+}
+
+// AbsEqual reports whether |a-b| <= tol.
+func AbsEqual[F Float](a, b F, tol float64) bool {
+	if eq, done := special(a, b, false); done {
+		return eq
+	}
+	return math.Abs(float64(a)-float64(b)) <= tol
+}
+
+// RelEqual reports whether |a-b| <= tol * max(|a|, |b|). it is scale free but
+// useless around zero: nothing except 0 itself is relatively close to 0.
+func RelEqual[F Float](a, b F, tol float64) bool {
+	if eq, done := special(a, b, false); done {
+		return eq
+	}
+	return relClose(float64(a), float64(b), tol)
+}
+
+func relClose(a, b, tol float64) bool {
+	return math.Abs(a-b) <= tol*math.Max(math.Abs(a), math.Abs(b))
+}
+
+// Equal reports whether a and b pass the absolute or the relative test.
+func Equal[F Float](a, b F, abs, rel float64) bool {
+	if eq, done := special(a, b, false); done {
+		return eq
+	}
+	x, y := float64(a), float64(b)
+	return math.Abs(x-y) <= abs || relClose(x, y, rel)
+}
+
+// ULPEqual reports whether a and b are at most maxULPs representable values
+// of F apart.
+func ULPEqual[F Float](a, b F, maxULPs uint64) bool {
+	if eq, done := special(a, b, false); done {
+		return eq
+	}
+	return ULPDistance(a, b) <= maxULPs
+}
+
+// ULPDistance returns how many representable values of F lie between a and b,
+// counting b (so neighbours are 1 apart). the count crosses zero correctly:
+// the smallest positive and smallest negative subnormal are 2 apart. it is
+// math.MaxUint64 if either value is NaN.
+func ULPDistance[F Float](a, b F) uint64 {
+	if math.IsNaN(float64(a)) || math.IsNaN(float64(b)) {
+		return math.MaxUint64
+	}
+	x, y := ordered(a), ordered(b)
+	if x < y {
+		x, y = y, x
+	}
+	return uint64(x) - uint64(y)
+}
+
+// ordered maps a float to an integer that sorts the same way and steps by
+// one between neighbouring floats. the bit pattern already does that for
+// positives, negatives are sign-magnitude so they are mirrored below zero.
+func ordered[F Float](x F) int64 {
+	if unsafe.Sizeof(x) == 4 {
+		i := int64(int32(math.Float32bits(float32(x))))
+		if i < 0 {
+			i = math.MinInt32 - i
+		}
+		return i
+	}
+	i := int64(math.Float64bits(float64(x)))
+	if i < 0 {
+		i = math.MinInt64 - i
+	}
+	return i
+}
+
+// ULP returns the distance from |x| to the next larger representable value
+// of F: the resolution of the type at x. it is +Inf for infinities and NaN
+// for NaN.
+func ULP[F Float](x F) F {
+	if math.IsInf(float64(x), 0) {
+		return F(math.Inf(1))
+	}
+	if unsafe.Sizeof(x) == 4 {
+		a := float32(math.Abs(float64(x)))
+		return F(math.Nextafter32(a, float32(math.Inf(1))) - a)
+	}
+	a := math.Abs(float64(x))
+	return F(math.Nextafter(a, math.Inf(1)) - a)
+}
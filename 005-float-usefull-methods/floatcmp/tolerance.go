@@ -0,0 +1,96 @@
+package floatcmp
+
+import (
+	"fmt"
+	"math"
+)
+
+// Tolerance combines the comparisons for use in tests: two values are close
+// when any enabled check passes. a zero field disables its check, so the zero
+// Tolerance means exact equality (with +0 == -0).
+type Tolerance struct {
+	Abs      float64 // |a-b| <= Abs
+	Rel      float64 // |a-b| <= Rel * max(|a|, |b|)
+	ULPs     uint64  // at most ULPs representable values apart
+	NaNEqual bool    // treat NaN as equal to NaN, e.g. for expected outputs
+}
+
+var (
+	// Default64 suits float64 results of a few chained operations.
+	Default64 = Tolerance{Abs: 1e-12, Rel: 1e-9}
+	// Default32 suits float32 results of a few chained operations.
+	Default32 = Tolerance{Abs: 1e-6, Rel: 1e-5}
+)
+
+// Close reports whether a and b are within the tolerance.
+func Close[F Float](a, b F, tol Tolerance) bool {
+	if eq, done := special(a, b, tol.NaNEqual); done {
+		return eq
+	}
+	x, y := float64(a), float64(b)
+	switch {
+	case tol.Abs > 0 && math.Abs(x-y) <= tol.Abs:
+		return true
+	case tol.Rel > 0 && relClose(x, y, tol.Rel):
+		return true
+	case tol.ULPs > 0 && ULPDistance(a, b) <= tol.ULPs:
+		return true
+	}
+	return false
+}
+
+// Mismatch describes the first pair of values that are not close.
+type Mismatch struct {
+	Index  []int   // position of the pair: [i] for slices, [i, j] for matrices
+	A, B   float64 // the two values, widened to float64
+	ULPs   uint64  // their ULP distance in the original type
+	Reason string  // set instead of the values for shape mismatches
+}
+
+func (m *Mismatch) String() string {
+	if m.Reason != "" {
+		return m.Reason
+	}
+	return fmt.Sprintf("at %v: %v != %v (diff %g, %d ULPs)", m.Index, m.A, m.B, m.A-m.B, m.ULPs)
+}
+
+// CompareSlices returns nil when a and b have the same length and every pair
+// is Close, otherwise the first mismatch. in a test:
+//
+//	if m := floatcmp.CompareSlices(got, want, floatcmp.Default64); m != nil {
+//		t.Errorf("result differs %s", m)
+//	}
+func CompareSlices[F Float](a, b []F, tol Tolerance) *Mismatch {
+	if len(a) != len(b) {
+		return &Mismatch{Reason: fmt.Sprintf("length %d != %d", len(a), len(b))}
+	}
+	for i := range a {
+		if !Close(a[i], b[i], tol) {
+			return mismatch([]int{i}, a[i], b[i])
+		}
+	}
+	return nil
+}
+
+// CompareMatrices is CompareSlices for row-major matrices. ragged matrices
+// are compared row by row, so rows only need to match their counterpart.
+func CompareMatrices[F Float](a, b [][]F, tol Tolerance) *Mismatch {
+	if len(a) != len(b) {
+		return &Mismatch{Reason: fmt.Sprintf("row count %d != %d", len(a), len(b))}
+	}
+	for i := range a {
+		if len(a[i]) != len(b[i]) {
+			return &Mismatch{Reason: fmt.Sprintf("row %d: length %d != %d", i, len(a[i]), len(b[i]))}
+		}
+		for j := range a[i] {
+			if !Close(a[i][j], b[i][j], tol) {
+				return mismatch([]int{i, j}, a[i][j], b[i][j])
+			}
+		}
+	}
+	return nil
+}
+
+func mismatch[F Float](index []int, a, b F) *Mismatch {
+	return &Mismatch{Index: index, A: float64(a), B: float64(b), ULPs: ULPDistance(a, b)}
+}
@@ -0,0 +1,289 @@
+// Package floatfmt formats floats in the notations strconv.FormatFloat does
+// not have, and parses them back.
+//
+// FormatFloat knows 'f' (1234.5), 'e' (1.2345e+03) and 'g'. a Format adds:
+//
+//	Engineering   12.345e+03   the exponent is a multiple of 3
+//	SI            12.345k      the exponent becomes a prefix: 4.7k, 12.3µ
+//	SigFigs       3 significant digits instead of digits after the point
+//	Group         1,234,567.5  thousands grouping with any separator
+//	Sign, Width   +1.5, "  1.5", 0001.5
+//
+// Locale fills in the decimal point and grouping of a language from the CLDR
+// data in golang.org/x/text, e.g. "1.234.567,5" for German and
+// "12,34,567.5" for Hindi. digits are always ASCII.
+//
+// Parse reads everything Format writes, with the same options.
+package floatfmt
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Notation selects how the exponent is written.
+type Notation int
+
+const (
+	Fixed       Notation = iota // 1234.5, no exponent
+	Scientific                  // 1.2345e+03, one digit before the point
+	Engineering                 // 1.2345e+03, 12.345e+03, 123.45e+03
+	SI                          // 1.2345k; engineering with a prefix, e.g. the k in 4.7k
+)
+
+// Sign selects how the sign of a non-negative number is written.
+type Sign int
+
+const (
+	SignNegative Sign = iota // only "-", the default
+	SignAlways               // "+" or "-"
+	SignSpace                // " " or "-", so columns line up
+)
+
+// Format describes how to write a float. the zero value writes Fixed with 0
+// digits after the point, like FormatFloat(x, 'f', 0, 64).
+type Format struct {
+	Notation Notation
+
+	// Precision is the number of digits after the point, or -1 for the
+	// fewest digits that read back to the same float64. it is ignored when
+	// SigFigs > 0, which sets the total number of significant digits.
+	Precision int
+	SigFigs   int
+
+	Decimal   string // the decimal point; "" means "."
+	Group     string // the grouping separator; "" turns grouping off
+	GroupSize int    // digits in the rightmost group; 0 means 3
+	// GroupSize2 is the size of the other groups when it differs, like the
+	// 2 of Indian grouping (12,34,567); 0 means GroupSize.
+	GroupSize2 int
+	// GroupMin is the least number of digits before the first separator: 2
+	// writes 1234 but 12,345, as Spanish does. 0 means 1.
+	GroupMin int
+
+	Sign  Sign
+	Width int  // pad to at least Width characters
+	Left  bool // pad on the right instead of the left
+	Zero  bool // pad with zeros after the sign instead of spaces
+
+	Unit  string // written after the number (and SI prefix), e.g. "Ω"
+	Space bool   // a space before the SI prefix or unit: 4.7 kΩ
+	ASCII bool   // write the micro prefix as "u" instead of "µ"
+}
+
+var (
+	// Default writes the shortest round-trip digits, like strconv 'f', -1.
+	Default = Format{Precision: -1}
+	// Eng writes engineering notation with shortest digits.
+	Eng = Format{Notation: Engineering, Precision: -1}
+	// Prefix writes SI prefixes with 3 significant digits: 4.7k, 12.3µ.
+	Prefix = Format{Notation: SI, SigFigs: 3}
+)
+
+// siPrefixes are the SI prefixes from quecto (10^-30) to quetta (10^30).
+var siPrefixes = [...]string{
+	"q", "r", "y", "z", "a", "f", "p", "n", "µ", "m", "",
+	"k", "M", "G", "T", "P", "E", "Z", "Y", "R", "Q",
+}
+
+const siMinExp = -30
+
+// siPrefix returns the prefix for a multiple of 3 exponent.
+func siPrefix(exp int) (string, bool) {
+	i := (exp - siMinExp) / 3
+	if exp < siMinExp || i >= len(siPrefixes) {
+		return "", false
+	}
+	return siPrefixes[i], true
+}
+
+// Format returns x written as f describes. NaN and infinities are written as
+// "NaN", "+Inf" and "-Inf" and padded with spaces only.
+func (f Format) Format(x float64) string {
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		s := strconv.FormatFloat(x, 'f', -1, 64)
+		return f.pad("", s, false)
+	}
+	sign := ""
+	switch {
+	case math.Signbit(x):
+		sign = "-"
+	case f.Sign == SignAlways:
+		sign = "+"
+	case f.Sign == SignSpace:
+		sign = " "
+	}
+	mant, exp, hasExp := f.digits(math.Abs(x))
+
+	suffix := ""
+	if hasExp {
+		if p, ok := siPrefix(exp); ok && f.Notation == SI {
+			if p == "µ" && f.ASCII {
+				p = "u"
+			}
+			suffix = p + f.Unit
+		} else {
+			// Engineering, or SI beyond quecto..quetta
+			suffix = "e" + expString(exp)
+			if f.Unit != "" {
+				suffix += " " + f.Unit
+			}
+		}
+	} else if f.Unit != "" {
+		suffix = f.Unit
+	}
+	if f.Space && suffix != "" && suffix[0] != 'e' {
+		suffix = " " + suffix
+	}
+	return f.pad(sign, f.localize(mant)+suffix, true)
+}
+
+// expString writes an exponent like strconv: sign and at least two digits.
+func expString(exp int) string {
+	sign := "+"
+	if exp < 0 {
+		sign, exp = "-", -exp
+	}
+	s := strconv.Itoa(exp)
+	if len(s) < 2 {
+		s = "0" + s
+	}
+	return sign + s
+}
+
+// digits returns the mantissa of a non-negative x in plain decimal with a
+// '.' point and, except in Fixed notation, its power of ten.
+func (f Format) digits(x float64) (mant string, exp int, hasExp bool) {
+	step := 1
+	switch f.Notation {
+	case Fixed:
+		if f.SigFigs > 0 {
+			d, e := sigDigits(x, f.SigFigs)
+			return place(d, e+1), 0, false
+		}
+		return strconv.FormatFloat(x, 'f', f.Precision, 64), 0, false
+	case Engineering, SI:
+		step = 3
+	}
+
+	// the exponent depends on the rounded digits (999.96 to 3 figures is
+	// 1.00e+03), and with Precision the digit count depends on the
+	// exponent, so round once with an estimate and redo it if it moved
+	_, e := sigDigits(x, -1)
+	for range 2 {
+		group := floorDiv(e, step) * step
+		n := f.SigFigs
+		if n <= 0 && f.Precision >= 0 {
+			n = e - group + 1 + f.Precision
+		}
+		var d string
+		d, e = sigDigits(x, n)
+		if x == 0 {
+			e = 0
+		}
+		if floorDiv(e, step)*step == group {
+			return place(d, e-group+1), group, true
+		}
+	}
+	panic("unreachable")
+}
+
+// sigDigits rounds x to n significant digits (n <= 0: shortest) and returns
+// the digits without a point and the decimal exponent of the first one.
+func sigDigits(x float64, n int) (string, int) {
+	s := strconv.FormatFloat(x, 'e', n-1, 64)
+	i := strings.IndexByte(s, 'e')
+	exp, _ := strconv.Atoi(s[i+1:])
+	return strings.Replace(s[:i], ".", "", 1), exp
+}
+
+// place puts the decimal point after intLen digits of d, adding zeros on
+// either side as needed.
+func place(d string, intLen int) string {
+	switch {
+	case intLen <= 0:
+		return "0." + strings.Repeat("0", -intLen) + d
+	case intLen >= len(d):
+		return d + strings.Repeat("0", intLen-len(d))
+	}
+	return d[:intLen] + "." + d[intLen:]
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && a < 0 {
+		q--
+	}
+	return q
+}
+
+// localize groups the integer part and replaces the decimal point.
+func (f Format) localize(m string) string {
+	intPart, frac := m, ""
+	if i := strings.IndexByte(m, '.'); i >= 0 {
+		intPart, frac = m[:i], m[i+1:]
+	}
+	if f.Group != "" {
+		intPart = f.group(intPart)
+	}
+	if frac == "" {
+		return intPart
+	}
+	point := f.Decimal
+	if point == "" {
+		point = "."
+	}
+	return intPart + point + frac
+}
+
+func (f Format) sizes() (size, size2, first int) {
+	size, size2, first = f.GroupSize, f.GroupSize2, f.GroupMin
+	if size <= 0 {
+		size = 3
+	}
+	if size2 <= 0 {
+		size2 = size
+	}
+	return size, size2, max(first, 1)
+}
+
+// group inserts separators into a string of digits.
+func (f Format) group(digits string) string {
+	size, size2, first := f.sizes()
+	if len(digits) < size+first {
+		return digits
+	}
+	var parts []string
+	parts = append(parts, digits[len(digits)-size:])
+	digits = digits[:len(digits)-size]
+	for len(digits) > size2 {
+		parts = append(parts, digits[len(digits)-size2:])
+		digits = digits[:len(digits)-size2]
+	}
+	parts = append(parts, digits)
+	var b strings.Builder
+	for i := len(parts) - 1; i >= 0; i-- {
+		b.WriteString(parts[i])
+		if i > 0 {
+			b.WriteString(f.Group)
+		}
+	}
+	return b.String()
+}
+
+// pad applies Width. zero padding goes between the sign and the digits and
+// is only used for finite numbers.
+func (f Format) pad(sign, s string, finite bool) string {
+	n := f.Width - utf8.RuneCountInString(sign) - utf8.RuneCountInString(s)
+	switch {
+	case n <= 0:
+		return sign + s
+	case f.Left:
+		return sign + s + strings.Repeat(" ", n)
+	case f.Zero && finite:
+		return sign + strings.Repeat("0", n) + s
+	}
+	return strings.Repeat(" ", n) + sign + s
+}
@@ -0,0 +1,64 @@
+package floatfmt
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// Locale returns f with the decimal point and grouping of the language tag,
+// as CLDR defines them: "1,234,567.5" for en, "1.234.567,5" for de,
+// "1 234 567,5" (no-break space) for fr and "12,34,567.5" for hi. the rest
+// of f is kept, and the digits stay ASCII even where the language has its
+// own.
+//
+// x/text has no API for the symbols themselves, so they are read back from
+// how its printer writes a sample number.
+func (f Format) Locale(tag language.Tag) Format {
+	p := message.NewPrinter(tag)
+	runs, seps := split(p.Sprintf("%.1f", 1234567.5))
+	if len(runs) < 2 || len(seps) != len(runs)-1 {
+		return f // not a number we understand; keep f as it is
+	}
+	f.Decimal = seps[len(seps)-1]
+	f.Group, f.GroupSize, f.GroupSize2, f.GroupMin = "", 0, 0, 0
+	if n := len(runs) - 1; n >= 3 { // 1234567 has at least two separators
+		f.Group = seps[0]
+		f.GroupSize = runs[n-1]
+		if size2 := runs[n-2]; size2 != f.GroupSize {
+			f.GroupSize2 = size2
+		}
+		if runs, _ := split(p.Sprintf("%d", 1234)); len(runs) == 1 {
+			f.GroupMin = 2
+		}
+	}
+	return f
+}
+
+// split cuts s into the lengths of its digit runs and the strings between
+// them, ignoring anything before the first digit.
+func split(s string) (runs []int, seps []string) {
+	var sep strings.Builder
+	inDigits := false
+	for _, r := range s {
+		if unicode.IsDigit(r) {
+			if !inDigits {
+				if len(runs) > 0 {
+					seps = append(seps, sep.String())
+				}
+				runs = append(runs, 0)
+				sep.Reset()
+			}
+			runs[len(runs)-1]++
+			inDigits = true
+			continue
+		}
+		if len(runs) > 0 {
+			sep.WriteRune(r)
+		}
+		inDigits = false
+	}
+	return runs, seps
+}
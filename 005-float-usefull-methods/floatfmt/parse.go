@@ -0,0 +1,143 @@
+package floatfmt
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// prefixExp maps every SI prefix Parse accepts to its power of ten,
+// including "u" and the Greek letter mu for micro.
+var prefixExp = func() map[string]int {
+	m := map[string]int{"u": -6, "μ": -6}
+	for i, p := range siPrefixes {
+		if p != "" {
+			m[p] = siMinExp + 3*i
+		}
+	}
+	return m
+}()
+
+// Parse reads a number written in any notation with f's decimal point and
+// grouping: "1,234.5", "12.3e+03", "4.7k", "4.7 kΩ" with Unit "Ω", "NaN",
+// "-Inf". grouping is optional but must be well formed when present, and
+// padding spaces and zeros are ignored. the value is rounded once, from the
+// full decimal, like strconv.ParseFloat; on overflow it returns ±Inf and an
+// error wrapping strconv.ErrRange.
+func (f Format) Parse(s string) (float64, error) {
+	v, err := f.parse(s)
+	if err != nil {
+		return v, fmt.Errorf("floatfmt: parsing %q: %w", s, err)
+	}
+	return v, nil
+}
+
+// Parse is Default.Parse: no grouping, "." as the decimal point.
+func Parse(s string) (float64, error) { return Default.Parse(s) }
+
+func (f Format) parse(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	neg := false
+	if r, n := utf8.DecodeRuneInString(s); r == '+' || r == '-' || r == '−' {
+		neg, s = r != '+', strings.TrimLeftFunc(s[n:], unicode.IsSpace)
+	}
+	switch strings.ToLower(s) {
+	case "inf", "infinity":
+		if neg {
+			return math.Inf(-1), nil
+		}
+		return math.Inf(1), nil
+	case "nan":
+		return math.NaN(), nil
+	}
+
+	if f.Unit != "" {
+		s = strings.TrimRightFunc(strings.TrimSuffix(s, f.Unit), unicode.IsSpace)
+	}
+	exp, hasPrefix := 0, false
+	if r, n := utf8.DecodeLastRuneInString(s); !isDigit(r) {
+		if e, ok := prefixExp[string(r)]; ok {
+			exp, hasPrefix = e, true
+			s = strings.TrimRightFunc(s[:len(s)-n], unicode.IsSpace)
+		}
+	}
+
+	mant := s
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		if hasPrefix {
+			return 0, strconv.ErrSyntax // both 1e3 and k
+		}
+		e, err := strconv.Atoi(s[i+1:])
+		if err != nil || s[i+1] == '_' {
+			return 0, strconv.ErrSyntax
+		}
+		mant, exp = s[:i], e
+	}
+
+	point := f.Decimal
+	if point == "" {
+		point = "."
+	}
+	intPart, frac, _ := strings.Cut(mant, point)
+	intPart, err := f.ungroup(intPart)
+	if err != nil {
+		return 0, err
+	}
+	if intPart == "" && frac == "" || !allDigits(frac) {
+		return 0, strconv.ErrSyntax
+	}
+
+	num := intPart + "." + frac + "e" + strconv.Itoa(exp)
+	if neg {
+		num = "-" + num
+	}
+	v, err := strconv.ParseFloat(num, 64)
+	if errors.Is(err, strconv.ErrRange) {
+		return v, strconv.ErrRange
+	}
+	return v, err
+}
+
+// ungroup removes the separators from the integer part, checking the group
+// sizes: 1,234,567 and 12,34,567 (with GroupSize2 2) are fine, 1,23,4 not.
+func (f Format) ungroup(s string) (string, error) {
+	if f.Group == "" || !strings.Contains(s, f.Group) {
+		if !allDigits(s) {
+			return "", strconv.ErrSyntax
+		}
+		return s, nil
+	}
+	size, size2, _ := f.sizes()
+	parts := strings.Split(s, f.Group)
+	for i, p := range parts {
+		want := size2
+		if i == len(parts)-1 {
+			want = size
+		}
+		ok := allDigits(p) && len(p) == want
+		if i == 0 {
+			// zero padding can make the first group longer: 0001,234
+			n := len(strings.TrimLeft(p, "0"))
+			ok = allDigits(p) && p != "" && n <= want
+		}
+		if !ok {
+			return "", fmt.Errorf("bad digit grouping: %w", strconv.ErrSyntax)
+		}
+	}
+	return strings.Join(parts, ""), nil
+}
+
+func isDigit(r rune) bool { return '0' <= r && r <= '9' }
+
+func allDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isDigit(rune(s[i])) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,5 @@
+module float-methods
+
+go 1.24.0
+
+require golang.org/x/text v0.32.0
@@ -0,0 +1,170 @@
+// Package interval computes with ranges [lo, hi] that are guaranteed to
+// contain the exact real result, instead of a single float64 that is only
+// close to it.
+//
+// every float64 operation rounds to the nearest float, and after a few steps
+// nobody knows how far the result is off; the lesson's floatEquals guesses
+// with an epsilon. interval arithmetic rounds the lower bound down and the
+// upper bound up, so the true value can never escape:
+//
+//	x := interval.MustParse("0.1")          // [0.09999999999999999, 0.1]
+//	sum := interval.Point(0)
+//	for range 10 {
+//		sum = sum.Add(x)
+//	}
+//	fmt.Println(sum, sum.Contains(1))       // an interval a few ulps wide, true
+//
+// the width of the result is the error bound. the bounds are tight: an
+// operation that is exact in float64 does not widen the interval (the error
+// is detected with error-free transformations, see roundDown), otherwise it
+// moves one float outward with math.Nextafter.
+//
+// the empty interval stands for "no real number", the result of Sqrt of a
+// negative interval or of a NaN input; Entire is the whole real line, the
+// result of dividing by an interval that contains zero. bounds may be
+// infinite, so [1, +Inf] is every number >= 1.
+package interval
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// Interval is the closed set of reals x with Lo <= x <= Hi. an Interval with
+// Lo > Hi, or a NaN bound, is empty; use New, Point or Parse to build valid
+// ones.
+type Interval struct {
+	Lo, Hi float64
+}
+
+var (
+	// Empty contains no number.
+	Empty = Interval{math.Inf(1), math.Inf(-1)}
+	// Entire contains every number.
+	Entire = Interval{math.Inf(-1), math.Inf(1)}
+)
+
+// New returns [lo, hi]. it is Empty if lo > hi or either bound is NaN, and
+// also for [+Inf, +Inf] and [-Inf, -Inf], which contain no real number.
+func New(lo, hi float64) Interval {
+	if !(lo <= hi) || math.IsInf(lo, 1) || math.IsInf(hi, -1) {
+		return Empty
+	}
+	return Interval{lo, hi}
+}
+
+// Point returns [x, x]: x is exact, like an integer or a measured float64
+// taken at face value. for a decimal constant that float64 cannot hold, like
+// 0.1, use Parse, which encloses the decimal itself.
+func Point(x float64) Interval { return New(x, x) }
+
+// Parse returns the smallest interval that contains the decimal (or hex)
+// number in s: a point if s is a float64, otherwise the two floats around it.
+func Parse(s string) (Interval, error) {
+	want, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Empty, fmt.Errorf("interval: parsing %q: %w", s, strconv.ErrSyntax)
+	}
+	f, _ := want.Float64() // nearest, ±MaxFloat64 or ±Inf beyond the range
+	switch new(big.Rat).SetFloat64(clampFinite(f)).Cmp(want) {
+	case -1:
+		return New(clampFinite(f), roundUp(f)), nil
+	case 1:
+		return New(roundDown(f), clampFinite(f)), nil
+	}
+	return Point(f), nil
+}
+
+// MustParse is like Parse but panics on malformed input.
+func MustParse(s string) Interval {
+	x, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return x
+}
+
+// clampFinite turns ±Inf into ±MaxFloat64, for comparing with a big.Rat.
+func clampFinite(f float64) float64 {
+	return math.Max(-math.MaxFloat64, math.Min(f, math.MaxFloat64))
+}
+
+// IsEmpty reports whether x contains no number.
+func (x Interval) IsEmpty() bool { return !(x.Lo <= x.Hi) }
+
+// IsEntire reports whether x is the whole real line.
+func (x Interval) IsEntire() bool { return math.IsInf(x.Lo, -1) && math.IsInf(x.Hi, 1) }
+
+// Contains reports whether v is in x. NaN is in no interval.
+func (x Interval) Contains(v float64) bool { return x.Lo <= v && v <= x.Hi }
+
+// ContainsZero reports whether 0 is in x.
+func (x Interval) ContainsZero() bool { return x.Contains(0) }
+
+// Subset reports whether every number in x is also in y.
+func (x Interval) Subset(y Interval) bool {
+	return x.IsEmpty() || y.Lo <= x.Lo && x.Hi <= y.Hi
+}
+
+// Intersect returns the numbers in both x and y.
+func (x Interval) Intersect(y Interval) Interval {
+	return New(math.Max(x.Lo, y.Lo), math.Min(x.Hi, y.Hi))
+}
+
+// Hull returns the smallest interval that contains both x and y.
+func (x Interval) Hull(y Interval) Interval {
+	switch {
+	case x.IsEmpty():
+		return y
+	case y.IsEmpty():
+		return x
+	}
+	return Interval{math.Min(x.Lo, y.Lo), math.Max(x.Hi, y.Hi)}
+}
+
+// Mid returns the midpoint of x, a float inside x; NaN for Empty, 0 for
+// Entire and ±MaxFloat64 for half-infinite intervals.
+func (x Interval) Mid() float64 {
+	switch {
+	case x.IsEmpty():
+		return math.NaN()
+	case x.IsEntire():
+		return 0
+	case math.IsInf(x.Lo, -1):
+		return -math.MaxFloat64
+	case math.IsInf(x.Hi, 1):
+		return math.MaxFloat64
+	}
+	m := x.Lo/2 + x.Hi/2 // no overflow, unlike (Lo+Hi)/2
+	return math.Max(x.Lo, math.Min(m, x.Hi))
+}
+
+// Width returns Hi - Lo rounded up, the error bound of any point in x; NaN
+// for Empty.
+func (x Interval) Width() float64 {
+	if x.IsEmpty() {
+		return math.NaN()
+	}
+	return subUp(x.Hi, x.Lo)
+}
+
+// Equal reports whether x and y have the same bounds; all empty intervals
+// are equal.
+func (x Interval) Equal(y Interval) bool {
+	if x.IsEmpty() || y.IsEmpty() {
+		return x.IsEmpty() && y.IsEmpty()
+	}
+	return x.Lo == y.Lo && x.Hi == y.Hi
+}
+
+// String writes [lo, hi] with the shortest decimals that read back as the
+// bounds, or "[empty]".
+func (x Interval) String() string {
+	if x.IsEmpty() {
+		return "[empty]"
+	}
+	return "[" + strconv.FormatFloat(x.Lo, 'g', -1, 64) + ", " +
+		strconv.FormatFloat(x.Hi, 'g', -1, 64) + "]"
+}
@@ -0,0 +1,429 @@
+package interval
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// prec is the precision of the reference values for Sqrt, Exp, Log and Pow,
+// far beyond float64, so a bound that is on the wrong side of one is wrong.
+const prec = 200
+
+func bigFloat(x float64) *big.Float { return new(big.Float).SetPrec(prec).SetFloat64(x) }
+
+// ln2 is log(2) to prec bits: 2 atanh(1/3).
+var ln2 = func() *big.Float {
+	third := new(big.Float).SetPrec(prec).Quo(bigFloat(1), bigFloat(3))
+	return atanh(third).Mul(atanh(third), bigFloat(2))
+}()
+
+// atanh returns z + z³/3 + z⁵/5 + ..., for |z| <= 1/3.
+func atanh(z *big.Float) *big.Float {
+	sum := new(big.Float).SetPrec(prec).Set(z)
+	z2 := new(big.Float).SetPrec(prec).Mul(z, z)
+	term := new(big.Float).SetPrec(prec).Set(z)
+	for k := 3; ; k += 2 {
+		term.Mul(term, z2)
+		t := new(big.Float).SetPrec(prec).Quo(term, bigFloat(float64(k)))
+		if t.Sign() == 0 || t.MantExp(nil)-sum.MantExp(nil) < -prec {
+			return sum
+		}
+		sum.Add(sum, t)
+	}
+}
+
+// bigExp returns e^x to about prec bits: x = k log 2 + r, and e^r from its
+// Taylor series after halving r until it is small.
+func bigExp(x *big.Float) *big.Float {
+	kf := new(big.Float).SetPrec(prec).Quo(x, ln2)
+	k, _ := kf.Int64()
+	r := new(big.Float).SetPrec(prec).Sub(x, new(big.Float).SetPrec(prec).Mul(ln2, bigFloat(float64(k))))
+	const halvings = 20
+	r.SetMantExp(r, -halvings)
+	sum, term := bigFloat(1), bigFloat(1)
+	for i := 1; i < 60; i++ {
+		term.Mul(term, r)
+		term.Quo(term, bigFloat(float64(i)))
+		sum.Add(sum, term)
+	}
+	for range halvings {
+		sum.Mul(sum, sum)
+	}
+	return sum.SetMantExp(sum, int(k))
+}
+
+// bigLog returns log(x) for x > 0: x = m 2^e with m in [0.5, 1), and
+// log m = 2 atanh((m-1)/(m+1)).
+func bigLog(x *big.Float) *big.Float {
+	m := new(big.Float).SetPrec(prec)
+	e := x.MantExp(m)
+	z := new(big.Float).SetPrec(prec).Quo(
+		new(big.Float).SetPrec(prec).Sub(m, bigFloat(1)),
+		new(big.Float).SetPrec(prec).Add(m, bigFloat(1)))
+	l := atanh(z)
+	l.Mul(l, bigFloat(2))
+	return l.Add(l, new(big.Float).SetPrec(prec).Mul(ln2, bigFloat(float64(e))))
+}
+
+func TestReference(t *testing.T) {
+	for _, x := range []float64{-745, -1, -1e-10, 0, 0.5, 1, 2, 10, 709, 1000} {
+		got, _ := bigExp(bigFloat(x)).Float64()
+		if want := math.Exp(x); math.Abs(got-want) > 2e-16*want {
+			t.Errorf("bigExp(%v) = %v, want %v", x, got, want)
+		}
+	}
+	for _, x := range []float64{5e-324, 1e-10, 0.5, 1, 2, math.E, 10, 1e300, math.MaxFloat64} {
+		got, _ := bigLog(bigFloat(x)).Float64()
+		if want := log(x); math.Abs(got-want) > 2e-16*math.Abs(want) {
+			t.Errorf("bigLog(%v) = %v, want %v", x, got, want)
+		}
+	}
+}
+
+// cases is how many random cases each enclosure test checks.
+func cases(n int) int {
+	if testing.Short() {
+		return n / 10
+	}
+	return n
+}
+
+// encloses reports whether the exact value v is in x. a nil v, a result
+// that does not exist, is in every interval.
+func encloses(x Interval, v *big.Float) bool {
+	if v == nil {
+		return true
+	}
+	if x.IsEmpty() {
+		return false
+	}
+	return (math.IsInf(x.Lo, -1) || bigFloat(x.Lo).Cmp(v) <= 0) &&
+		(math.IsInf(x.Hi, 1) || bigFloat(x.Hi).Cmp(v) >= 0)
+}
+
+// randFloat returns a float64 of any magnitude, with the values where
+// rounding goes wrong most often (zero, one, powers of two, the ends of
+// the range) more likely than they would be by chance.
+func randFloat(r *rand.Rand) float64 {
+	var f float64
+	switch r.Intn(8) {
+	case 0:
+		f = []float64{0, 1, 2, 0.5, 0.1, 3, 10, math.MaxFloat64, math.SmallestNonzeroFloat64, 0x1p-1022}[r.Intn(10)]
+	case 1:
+		f = float64(r.Intn(100))
+	case 2:
+		f = math.Ldexp(r.Float64(), r.Intn(2098)-1074)
+	default:
+		f = math.Ldexp(r.Float64(), r.Intn(80)-40)
+	}
+	if r.Intn(2) == 0 {
+		f = -f
+	}
+	return f
+}
+
+// randInterval returns an interval with random bounds, sometimes a point and
+// sometimes unbounded.
+func randInterval(r *rand.Rand) Interval {
+	a, b := randFloat(r), randFloat(r)
+	switch r.Intn(10) {
+	case 0:
+		return Point(a)
+	case 1:
+		a = math.Inf(-1)
+	case 2:
+		b = math.Inf(1)
+	}
+	return New(min(a, b), max(a, b))
+}
+
+// points returns finite numbers in x: its finite bounds and one between.
+func points(r *rand.Rand, x Interval) []float64 {
+	if x.IsEmpty() {
+		return nil
+	}
+	var ps []float64
+	lo, hi := x.Lo, x.Hi
+	if finite(lo) {
+		ps = append(ps, lo)
+	} else {
+		lo = -math.MaxFloat64
+	}
+	if finite(hi) {
+		ps = append(ps, hi)
+	} else {
+		hi = math.MaxFloat64
+	}
+	f := r.Float64()
+	return append(ps, max(lo, min(hi, lo*(1-f)+hi*f)))
+}
+
+func TestEnclosureBinary(t *testing.T) {
+	ops := []struct {
+		name  string
+		f     func(x, y Interval) Interval
+		exact func(x, y *big.Rat) *big.Rat // nil if undefined
+	}{
+		{"Add", Interval.Add, func(x, y *big.Rat) *big.Rat { return new(big.Rat).Add(x, y) }},
+		{"Sub", Interval.Sub, func(x, y *big.Rat) *big.Rat { return new(big.Rat).Sub(x, y) }},
+		{"Mul", Interval.Mul, func(x, y *big.Rat) *big.Rat { return new(big.Rat).Mul(x, y) }},
+		{"Div", Interval.Div, func(x, y *big.Rat) *big.Rat {
+			if y.Sign() == 0 {
+				return nil
+			}
+			return new(big.Rat).Quo(x, y)
+		}},
+	}
+	r := rand.New(rand.NewSource(1))
+	for range cases(20000) {
+		x, y := randInterval(r), randInterval(r)
+		for _, op := range ops {
+			got := op.f(x, y)
+			for _, a := range points(r, x) {
+				for _, b := range points(r, y) {
+					v := op.exact(new(big.Rat).SetFloat64(a), new(big.Rat).SetFloat64(b))
+					if v != nil && !encloses(got, new(big.Float).SetPrec(0).SetRat(v)) {
+						t.Fatalf("%v.%s(%v) = %v, does not contain %v %s %v", x, op.name, y, got, a, op.name, b)
+					}
+				}
+			}
+		}
+		p, q := x.DivParts(y)
+		for _, a := range points(r, x) {
+			for _, b := range points(r, y) {
+				if b == 0 {
+					continue
+				}
+				v := new(big.Float).SetPrec(0).SetRat(new(big.Rat).Quo(new(big.Rat).SetFloat64(a), new(big.Rat).SetFloat64(b)))
+				if !encloses(p, v) && !encloses(q, v) {
+					t.Fatalf("%v.DivParts(%v) = %v, %v, neither contains %v / %v", x, y, p, q, a, b)
+				}
+			}
+		}
+	}
+}
+
+func TestEnclosureUnary(t *testing.T) {
+	ops := []struct {
+		name  string
+		f     func(x Interval) Interval
+		exact func(x float64) *big.Float // nil if undefined
+	}{
+		{"Sqrt", Interval.Sqrt, func(x float64) *big.Float {
+			if x < 0 {
+				return nil
+			}
+			return new(big.Float).SetPrec(prec).Sqrt(bigFloat(x))
+		}},
+		{"Exp", Interval.Exp, func(x float64) *big.Float {
+			if x > 1e6 || x < -1e6 {
+				return nil // beyond what bigExp handles; Exp is ±Inf-bound there anyway
+			}
+			return bigExp(bigFloat(x))
+		}},
+		{"Log", Interval.Log, func(x float64) *big.Float {
+			if x <= 0 {
+				return nil
+			}
+			return bigLog(bigFloat(x))
+		}},
+		{"Sqr", Interval.Sqr, func(x float64) *big.Float { return new(big.Float).Mul(bigFloat(x), bigFloat(x)) }},
+	}
+	r := rand.New(rand.NewSource(2))
+	for range cases(20000) {
+		x := randInterval(r)
+		if r.Intn(2) == 0 {
+			// the range where Exp neither overflows nor underflows, and its
+			// edges
+			x = New(math.Ldexp(r.Float64(), 10)-800, math.Ldexp(r.Float64(), 10)-200)
+		}
+		for _, op := range ops {
+			got := op.f(x)
+			for _, a := range points(r, x) {
+				if v := op.exact(a); !encloses(got, v) {
+					t.Fatalf("%v.%s() = %v, does not contain %s(%v)", x, op.name, got, op.name, a)
+				}
+			}
+		}
+	}
+}
+
+func TestEnclosurePow(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for range cases(5000) {
+		x := New(0, math.Ldexp(r.Float64(), r.Intn(40)-20))
+		x.Lo = x.Hi * r.Float64()
+		y := New(-20*r.Float64(), 20*r.Float64())
+		got := x.Pow(y)
+		for _, a := range points(r, x) {
+			for _, b := range points(r, y) {
+				if a == 0 {
+					continue
+				}
+				v := bigExp(new(big.Float).SetPrec(prec).Mul(bigFloat(b), bigLog(bigFloat(a))))
+				if !encloses(got, v) {
+					t.Fatalf("%v.Pow(%v) = %v, does not contain %v^%v", x, y, got, a, b)
+				}
+			}
+		}
+	}
+}
+
+func TestEnclosurePowInt(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	for range cases(20000) {
+		x := randInterval(r)
+		n := r.Intn(25) - 12
+		got := x.PowInt(n)
+		for _, a := range points(r, x) {
+			if a == 0 && n < 0 || math.Abs(a) > 1e100 || a != 0 && math.Abs(a) < 1e-100 {
+				continue // no value, or one too large for a quick big.Rat
+			}
+			v := new(big.Rat).SetInt64(1)
+			for range max(n, -n) {
+				v.Mul(v, new(big.Rat).SetFloat64(a))
+			}
+			if n < 0 {
+				v.Inv(v)
+			}
+			if !encloses(got, new(big.Float).SetPrec(0).SetRat(v)) {
+				t.Fatalf("%v.PowInt(%d) = %v, does not contain %v^%d", x, n, got, a, n)
+			}
+		}
+	}
+}
+
+func TestPowIntLarge(t *testing.T) {
+	tests := []struct {
+		x    Interval
+		n    int
+		want Interval
+	}{
+		{Point(1), 1 << 40, Point(1)},
+		{Point(-1), math.MaxInt, Point(-1)},
+		{Point(-1), math.MinInt, Point(1)},
+		{Point(0), 1 << 62, Point(0)},
+		{Point(2), 1 << 40, New(math.MaxFloat64, math.Inf(1))},
+		{Point(0.5), 1 << 40, New(0, 2*math.SmallestNonzeroFloat64)},
+		{Point(2), math.MinInt, New(0, 2*math.SmallestNonzeroFloat64)},
+		{New(-2, 3), 3, New(-8, 27)},
+		{New(-2, 3), 4, New(0, 81)},
+		{New(-3, -2), 2, New(4, 9)},
+		{New(-2, 3), 0, Point(1)},
+	}
+	for _, tt := range tests {
+		if got := tt.x.PowInt(tt.n); !got.Equal(tt.want) {
+			t.Errorf("%v.PowInt(%d) = %v, want %v", tt.x, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestEmptyAndEntire(t *testing.T) {
+	x := New(1, 2)
+	for name, got := range map[string]Interval{
+		"Empty.Add":        Empty.Add(x),
+		"Sub Empty":        x.Sub(Empty),
+		"Empty.Mul":        Empty.Mul(x),
+		"Div Empty":        x.Div(Empty),
+		"Div [0, 0]":       x.Div(Point(0)),
+		"Empty.Sqrt":       Empty.Sqrt(),
+		"[-2, -1].Sqrt":    New(-2, -1).Sqrt(),
+		"[-2, 0].Log":      New(-2, 0).Log(),
+		"Empty.Exp":        Empty.Exp(),
+		"Empty.PowInt(0)":  Empty.PowInt(0),
+		"Pow Empty":        x.Pow(Empty),
+		"New(2, 1)":        New(2, 1),
+		"New(NaN, 1)":      New(math.NaN(), 1),
+		"New(+Inf, +Inf)":  New(math.Inf(1), math.Inf(1)),
+		"Point(-Inf)":      Point(math.Inf(-1)),
+		"Intersect":        New(1, 2).Intersect(New(3, 4)),
+		"Empty.Neg":        Empty.Neg(),
+		"[0, 0].Pow([-1])": Point(0).Pow(Point(-1)),
+	} {
+		if !got.IsEmpty() {
+			t.Errorf("%s = %v, want empty", name, got)
+		}
+	}
+	if !Empty.Equal(New(3, 1)) || Empty.Equal(x) || Empty.Contains(0) || Empty.String() != "[empty]" {
+		t.Error("Empty does not behave as the empty set")
+	}
+	if !Entire.IsEntire() || !Entire.Contains(math.MaxFloat64) || Entire.Contains(math.NaN()) {
+		t.Error("Entire does not behave as the real line")
+	}
+	for name, got := range map[string]Interval{
+		"Entire.Add":         Entire.Add(x),
+		"Entire.Mul":         Entire.Mul(x),
+		"[1, 2] / [-1, 1]":   x.Div(New(-1, 1)),
+		"[-1, 1] / [-1, 1]":  New(-1, 1).Div(New(-1, 1)),
+		"Entire.Neg":         Entire.Neg(),
+		"[1, 2] / [-Inf, 1]": x.Div(New(math.Inf(-1), 1)),
+	} {
+		if !got.IsEntire() {
+			t.Errorf("%s = %v, want Entire", name, got)
+		}
+	}
+	if got := Point(0).Mul(Entire); !got.Equal(Point(0)) {
+		t.Errorf("0 * Entire = %v, want [0, 0]", got)
+	}
+}
+
+func TestDivParts(t *testing.T) {
+	inf := math.Inf(1)
+	tests := []struct {
+		x, y   Interval
+		p1, p2 Interval
+	}{
+		{New(1, 2), New(-1, 1), New(-inf, -1), New(1, inf)},
+		{New(-2, -1), New(-1, 1), New(-inf, -1), New(1, inf)},
+		{New(1, 2), New(0, 1), New(1, inf), Empty},
+		{New(1, 2), New(-1, 0), New(-inf, -1), Empty},
+		{New(-2, -1), New(0, 1), New(-inf, -1), Empty},
+		{New(-2, -1), New(-4, 0), New(0.25, inf), Empty},
+		{New(1, 2), New(2, 4), New(0.25, 1), Empty},
+		{Point(0), New(-1, 1), Point(0), Empty},
+		{New(-1, 1), New(-1, 1), Entire, Empty},
+		{New(1, 2), Point(0), Empty, Empty},
+	}
+	for _, tt := range tests {
+		p1, p2 := tt.x.DivParts(tt.y)
+		if !p1.Equal(tt.p1) || !p2.Equal(tt.p2) {
+			t.Errorf("%v.DivParts(%v) = %v, %v, want %v, %v", tt.x, tt.y, p1, p2, tt.p1, tt.p2)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		s    string
+		want Interval
+	}{
+		{"0.5", Point(0.5)},
+		{"-3", Point(-3)},
+		{"0.1", New(0.09999999999999999, 0.1)},
+		{"-0.1", New(-0.1, -0.09999999999999999)},
+		{"1/3", New(0.3333333333333333, 0.33333333333333337)},
+		{"0x1p-3", Point(0.125)},
+		{"1e400", New(math.MaxFloat64, math.Inf(1))},
+		{"-1e400", New(math.Inf(-1), -math.MaxFloat64)},
+		{"1e-400", New(0, math.SmallestNonzeroFloat64)},
+		{"-1e-400", New(-math.SmallestNonzeroFloat64, 0)},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.s)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("Parse(%q) = %v, %v, want %v", tt.s, got, err, tt.want)
+		}
+		// the bounds enclose the number
+		v, _ := new(big.Rat).SetString(tt.s)
+		if !encloses(got, new(big.Float).SetPrec(0).SetRat(v)) {
+			t.Errorf("Parse(%q) = %v does not contain it", tt.s, got)
+		}
+	}
+	for _, s := range []string{"", "x", "1.2.3", "NaN", "Inf"} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("Parse(%q): no error", s)
+		}
+	}
+}
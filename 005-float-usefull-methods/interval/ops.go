@@ -0,0 +1,239 @@
+package interval
+
+import "math"
+
+// Neg returns [-Hi, -Lo]; it is exact.
+func (x Interval) Neg() Interval {
+	if x.IsEmpty() {
+		return Empty
+	}
+	return Interval{-x.Hi, -x.Lo}
+}
+
+// Add returns x + y.
+func (x Interval) Add(y Interval) Interval {
+	if x.IsEmpty() || y.IsEmpty() {
+		return Empty
+	}
+	return Interval{addDown(x.Lo, y.Lo), addUp(x.Hi, y.Hi)}
+}
+
+// Sub returns x - y. note that x.Sub(x) is not [0, 0] unless x is a point:
+// interval arithmetic does not know both operands are the same number.
+func (x Interval) Sub(y Interval) Interval {
+	if x.IsEmpty() || y.IsEmpty() {
+		return Empty
+	}
+	return Interval{subDown(x.Lo, y.Hi), subUp(x.Hi, y.Lo)}
+}
+
+// Mul returns x * y. 0 times an unbounded interval is 0.
+func (x Interval) Mul(y Interval) Interval {
+	if x.IsEmpty() || y.IsEmpty() {
+		return Empty
+	}
+	return Interval{
+		min4(mulDown(x.Lo, y.Lo), mulDown(x.Lo, y.Hi), mulDown(x.Hi, y.Lo), mulDown(x.Hi, y.Hi)),
+		max4(mulUp(x.Lo, y.Lo), mulUp(x.Lo, y.Hi), mulUp(x.Hi, y.Lo), mulUp(x.Hi, y.Hi)),
+	}
+}
+
+// Div returns x / y. when y contains zero the exact result is a union of
+// up to two rays, and Div returns the smallest interval around them: often
+// Entire. Div by [0, 0] is Empty. use DivParts to keep the rays apart.
+func (x Interval) Div(y Interval) Interval {
+	a, b := x.DivParts(y)
+	return a.Hull(b)
+}
+
+// DivParts returns x / y as two intervals whose union is the exact result,
+// for y that contains zero in its interior: [1, 2] / [-1, 1] is
+// [-Inf, -1] and [1, +Inf]. the second interval is Empty when one is enough.
+func (x Interval) DivParts(y Interval) (Interval, Interval) {
+	switch {
+	case x.IsEmpty() || y.IsEmpty() || y.Lo == 0 && y.Hi == 0:
+		return Empty, Empty
+	case !y.ContainsZero():
+		return Interval{
+			min4(divDown(x.Lo, y.Lo), divDown(x.Lo, y.Hi), divDown(x.Hi, y.Lo), divDown(x.Hi, y.Hi)),
+			max4(divUp(x.Lo, y.Lo), divUp(x.Lo, y.Hi), divUp(x.Hi, y.Lo), divUp(x.Hi, y.Hi)),
+		}, Empty
+	case x.Lo == 0 && x.Hi == 0:
+		return Point(0), Empty
+	case x.ContainsZero():
+		return Entire, Empty // 0/0 can be anything
+	}
+
+	// y contains zero, x does not: 1/y is one or two rays going to ±Inf
+	var lo, hi Interval
+	if x.Hi < 0 {
+		lo = Interval{math.Inf(-1), divUp(x.Hi, y.Hi)}  // x / (small positive y)
+		hi = Interval{divDown(x.Hi, y.Lo), math.Inf(1)} // x / (small negative y)
+	} else {
+		lo = Interval{math.Inf(-1), divUp(x.Lo, y.Lo)}
+		hi = Interval{divDown(x.Lo, y.Hi), math.Inf(1)}
+	}
+	switch {
+	case y.Lo == 0: // only the positive side of y
+		if x.Hi < 0 {
+			return lo, Empty
+		}
+		return hi, Empty
+	case y.Hi == 0: // only the negative side
+		if x.Hi < 0 {
+			return hi, Empty
+		}
+		return lo, Empty
+	}
+	return lo, hi
+}
+
+// Sqr returns x², tighter than x.Mul(x) for an x that contains zero:
+// [-1, 2] squared is [0, 4], not [-2, 4].
+func (x Interval) Sqr() Interval { return x.PowInt(2) }
+
+// PowInt returns x to the integer power n. for even n the result is never
+// negative, and x^0 is [1, 1], even for an x that contains zero.
+func (x Interval) PowInt(n int) Interval {
+	switch {
+	case x.IsEmpty():
+		return Empty
+	case n == 0:
+		return Point(1)
+	case n == math.MinInt:
+		return x.PowInt(n / 2).Sqr() // -n overflows; n is even
+	case n < 0:
+		return Point(1).Div(x.PowInt(-n))
+	}
+	if n%2 == 1 || x.Lo >= 0 {
+		// odd powers and powers of a positive x keep the order
+		return Interval{powDir(x.Lo, n, false), powDir(x.Hi, n, true)}
+	}
+	if x.Hi <= 0 {
+		return Interval{powDir(-x.Hi, n, false), powDir(-x.Lo, n, true)}
+	}
+	return Interval{0, powDir(math.Max(-x.Lo, x.Hi), n, true)}
+}
+
+// powDir computes a^n by repeated squaring, with every multiplication
+// rounded the same way: the factors are never negative, so each rounded
+// product stays on the same side of the exact one. for an odd n and a
+// negative a, the magnitude is rounded the other way.
+func powDir(a float64, n int, up bool) float64 {
+	if a < 0 {
+		return -powDir(-a, n, !up)
+	}
+	// mul rounds a product that underflows down to 0, not below it
+	mul := func(x, y float64) float64 { return math.Max(0, mulDir(x, y, up)) }
+	r := 1.0
+	for p := a; n > 0; n >>= 1 {
+		if n&1 == 1 {
+			r = mul(r, p)
+		}
+		if p == 0 || p == 1 || math.IsInf(p, 1) {
+			// further squares do not change p; only whether it is a
+			// factor once more matters
+			if n > 1 {
+				r = mul(r, p)
+			}
+			break
+		}
+		p = mul(p, p)
+	}
+	return r
+}
+
+// Sqrt returns the square root of the non-negative part of x, or Empty if
+// x has none.
+func (x Interval) Sqrt() Interval {
+	x = x.Intersect(Interval{0, math.Inf(1)})
+	if x.IsEmpty() {
+		return Empty
+	}
+	return Interval{sqrtDir(x.Lo, false), sqrtDir(x.Hi, true)}
+}
+
+func sqrtDir(a float64, up bool) float64 {
+	r := math.Sqrt(a)
+	if a == 0 || math.IsInf(a, 1) {
+		return r
+	}
+	if a < tiny {
+		return math.Max(0, moveOut(r, up))
+	}
+	// r*r - a exactly: positive means r is too large
+	return adjust(r, -sign(math.FMA(r, r, -a)), up, true)
+}
+
+// Exp returns e^x.
+func (x Interval) Exp() Interval {
+	if x.IsEmpty() {
+		return Empty
+	}
+	return Interval{math.Max(0, libDir(math.Exp, x.Lo, 0, false)), libDir(math.Exp, x.Hi, 0, true)}
+}
+
+// Log returns the natural logarithm of the positive part of x, or Empty if
+// x has none. a lower bound of 0 gives -Inf.
+func (x Interval) Log() Interval {
+	x = x.Intersect(Interval{0, math.Inf(1)})
+	if x.IsEmpty() || x.Hi == 0 {
+		return Empty
+	}
+	return Interval{libDir(log, x.Lo, 1, false), libDir(log, x.Hi, 1, true)}
+}
+
+// log is math.Log, with subnormal arguments scaled into the normal range
+// first: the amd64 math.Log gives -709.09 for every one of them, where the
+// true values go down to -744.44. the scaling is exact, and 54 log 2 and
+// the subtraction add less than an ulp.
+func log(a float64) float64 {
+	if a > 0 && a < 0x1p-1022 {
+		return math.Log(a*0x1p54) - 54*math.Ln2
+	}
+	return math.Log(a)
+}
+
+// libDir calls a library function and moves the result two floats outward.
+// the portable math.Exp and math.Log are within one ulp of the true value;
+// the second step covers the assembly versions some platforms use, which
+// document no bound. the argument exact, where the function is exact
+// (Exp(0) = 1, Log(1) = 0), and infinite results are not moved, except that
+// a lower bound that overflows from a finite argument becomes MaxFloat64, as
+// in adjust: Exp(710) is finite, just too large for a float64.
+func libDir(f func(float64) float64, a, exact float64, up bool) float64 {
+	r := f(a)
+	if math.IsInf(r, 1) && !up && !math.IsInf(a, 0) {
+		return math.MaxFloat64
+	}
+	if a == exact || math.IsInf(r, 0) {
+		return r
+	}
+	return moveOut(moveOut(r, up), up)
+}
+
+// Pow returns x^y as e^(y log x) over the positive part of x: a negative
+// base has no real power for most y, so like Sqrt and Log, Pow ignores the
+// negative part. 0^y is 0 for positive y and Empty otherwise. use PowInt
+// for integer powers of negative numbers.
+func (x Interval) Pow(y Interval) Interval {
+	if x.IsEmpty() || y.IsEmpty() {
+		return Empty
+	}
+	if x.Hi == 0 && y.Lo > 0 {
+		return Point(0) // Log would make this Empty
+	}
+	return y.Mul(x.Log()).Exp()
+}
+
+// min4 and max4 skip NaN, which only comes from Inf/Inf corners that are
+// never the extreme values of a quotient.
+func min4(a, b, c, d float64) float64 { return minNaN(minNaN(a, b), minNaN(c, d)) }
+func max4(a, b, c, d float64) float64 { return -min4(-a, -b, -c, -d) }
+
+func minNaN(a, b float64) float64 {
+	if math.IsNaN(a) || b < a {
+		return b
+	}
+	return a
+}
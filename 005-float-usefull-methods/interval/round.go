@@ -0,0 +1,116 @@
+package interval
+
+import "math"
+
+// Directed rounding. Go has no way to switch the FPU rounding mode, so each
+// operation is done in round-to-nearest and then checked: an error-free
+// transformation (TwoSum for addition, FMA for the rest) gives the exact
+// rounding error, and only when the result was rounded the wrong way does
+// it move one float with math.Nextafter. results near the subnormal range,
+// where those errors are no longer exact, are always moved.
+
+func roundDown(x float64) float64 { return math.Nextafter(x, math.Inf(-1)) }
+func roundUp(x float64) float64   { return math.Nextafter(x, math.Inf(1)) }
+
+// tiny is the magnitude below which FMA errors may themselves be rounded.
+const tiny = 0x1p-969 // 2^-1022 * 2^53
+
+func finite(x float64) bool { return !math.IsInf(x, 0) && !math.IsNaN(x) }
+
+// adjust returns r rounded toward -Inf (up false) or +Inf (up true), given
+// the sign of the exact result minus r: errSign < 0 means r is too large.
+// an overflow to ±Inf of finite operands is pulled back to ±MaxFloat64 on
+// the side where the true value is finite.
+func adjust(r float64, errSign int, up, finiteArgs bool) float64 {
+	switch {
+	case math.IsInf(r, 0):
+		if finiteArgs && math.IsInf(r, 1) && !up {
+			return math.MaxFloat64
+		}
+		if finiteArgs && math.IsInf(r, -1) && up {
+			return -math.MaxFloat64
+		}
+		return r
+	case errSign < 0 && !up:
+		return roundDown(r)
+	case errSign > 0 && up:
+		return roundUp(r)
+	}
+	return r
+}
+
+// sign returns -1, 0 or 1.
+func sign(x float64) int {
+	switch {
+	case x < 0:
+		return -1
+	case x > 0:
+		return 1
+	}
+	return 0
+}
+
+func addDir(a, b float64, up bool) float64 {
+	s := a + b
+	if !finite(s) {
+		return adjust(s, 0, up, finite(a) && finite(b))
+	}
+	// TwoSum: s + e == a + b exactly
+	var e float64
+	if math.Abs(a) >= math.Abs(b) {
+		e = (a - s) + b
+	} else {
+		e = (b - s) + a
+	}
+	return adjust(s, sign(e), up, true)
+}
+
+func addDown(a, b float64) float64 { return addDir(a, b, false) }
+func addUp(a, b float64) float64   { return addDir(a, b, true) }
+func subDown(a, b float64) float64 { return addDir(a, -b, false) }
+func subUp(a, b float64) float64   { return addDir(a, -b, true) }
+
+func mulDir(a, b float64, up bool) float64 {
+	if a == 0 || b == 0 {
+		return 0 // also 0 * Inf: a bound of Inf means "unbounded", not Inf
+	}
+	p := a * b
+	if !finite(p) {
+		return adjust(p, 0, up, finite(a) && finite(b))
+	}
+	if math.Abs(p) < tiny {
+		return moveOut(p, up)
+	}
+	return adjust(p, sign(math.FMA(a, b, -p)), up, true)
+}
+
+func mulDown(a, b float64) float64 { return mulDir(a, b, false) }
+func mulUp(a, b float64) float64   { return mulDir(a, b, true) }
+
+func divDir(a, b float64, up bool) float64 {
+	if a == 0 || math.IsInf(b, 0) && finite(a) {
+		return 0
+	}
+	q := a / b
+	if !finite(q) || !finite(a) {
+		return adjust(q, 0, up, finite(a) && finite(b))
+	}
+	if math.Abs(q) < tiny || math.Abs(a) < tiny {
+		return moveOut(q, up)
+	}
+	// a - q*b exactly; the true quotient is q + r/b
+	r := math.FMA(-q, b, a)
+	return adjust(q, sign(r)*sign(b), up, true)
+}
+
+func divDown(a, b float64) float64 { return divDir(a, b, false) }
+func divUp(a, b float64) float64   { return divDir(a, b, true) }
+
+// moveOut always moves one float in the direction of the rounding, for
+// results whose error cannot be measured.
+func moveOut(x float64, up bool) float64 {
+	if up {
+		return roundUp(x)
+	}
+	return roundDown(x)
+}
@@ -0,0 +1,22 @@
+package lossy_test
+
+import (
+	"fmt"
+
+	"float-methods/lossy"
+)
+
+func Example() {
+	f, loss := lossy.Float32(1.23456789)
+	fmt.Println(f, loss)
+
+	_, loss = lossy.Float32(0.5)
+	fmt.Println(loss)
+
+	_, loss, _ = lossy.ParseFloat("1e-400", 64)
+	fmt.Println(loss.Exact, loss.Abs > 0)
+	// Output:
+	// 1.2345679 abs 9.4e-09, rel 7.6e-09
+	// exact
+	// false true
+}
@@ -0,0 +1,149 @@
+// Package lossy converts between numeric types and reports what the
+// conversion lost, the runtime side of SECTION 5 of the lesson.
+//
+// Go converts silently: float32(1.23456789) is 1.2345679, int(3.7) is 3,
+// float64(int64(1<<53 + 1)) is 9007199254740992 and "0.1" parses to
+// 0.1000000000000000055511151231257827. each function here returns the
+// converted value and a Loss saying whether it is exact and, if not, by how
+// much it is off:
+//
+//	f, loss := lossy.Float32(1.23456789)
+//	fmt.Println(f, loss) // 1.2345679 abs 9.4e-09, rel 7.6e-09
+//
+// to find such conversions of constants in source code without running it,
+// use go run ./cmd/lossycheck.
+package lossy
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ErrRange means a float is NaN, infinite or outside the range of int64.
+var ErrRange = errors.New("value out of range")
+
+// Loss is the difference between a value and its conversion.
+type Loss struct {
+	Exact bool
+	Abs   float64 // |converted - original|, rounded to float64 but not to 0
+	Rel   float64 // Abs / |original|
+}
+
+func (l Loss) String() string {
+	if l.Exact {
+		return "exact"
+	}
+	return fmt.Sprintf("abs %.2g, rel %.2g", l.Abs, l.Rel)
+}
+
+// loss builds a Loss from the original value and the absolute error.
+func loss(orig, abs float64) Loss {
+	if abs == 0 {
+		return Loss{Exact: true}
+	}
+	return Loss{Abs: abs, Rel: abs / math.Abs(orig)}
+}
+
+// Float32 converts x to float32, rounding to the nearest float32 like
+// float32(x). a finite x that overflows to ±Inf has an infinite loss. NaN and
+// ±Inf convert exactly (a NaN payload may lose its low bits).
+func Float32(x float64) (float32, Loss) {
+	f := float32(x)
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		return f, Loss{Exact: true}
+	}
+	// exact: both are float64 values within a factor of two (Sterbenz), or
+	// f is Inf and so is the difference
+	return f, loss(x, math.Abs(float64(f)-x))
+}
+
+// Float64 converts i to float64 like float64(i). every int64 up to 2^53 in
+// magnitude converts exactly; above that only every 2nd, 4th, ... integer
+// exists as a float64.
+func Float64(i int64) (float64, Loss) {
+	f := float64(i)
+	var diff uint64
+	switch {
+	case f >= 1<<63: // i rounded up to 2^63, which int64 cannot hold
+		diff = 1<<63 - uint64(i)
+	case int64(f) >= i:
+		diff = uint64(int64(f) - i)
+	default:
+		diff = uint64(i - int64(f))
+	}
+	return f, loss(float64(i), float64(diff))
+}
+
+// Int64 converts x to int64 by truncating toward zero, like int64(x). the
+// loss is the dropped fraction. NaN, ±Inf and values outside the int64 range
+// fail with ErrRange, where int64(x) would return an implementation-defined
+// value.
+func Int64(x float64) (int64, Loss, error) {
+	if math.IsNaN(x) || x >= 1<<63 || x < -1<<63 {
+		return 0, Loss{}, fmt.Errorf("lossy: converting %v to int64: %w", x, ErrRange)
+	}
+	return int64(x), loss(x, math.Abs(x-math.Trunc(x))), nil
+}
+
+// ParseFloat parses s like strconv.ParseFloat and reports how far the
+// result is from the decimal (or hexadecimal) number written in s. errors
+// are strconv's; on ErrRange the loss is infinite.
+func ParseFloat(s string, bitSize int) (float64, Loss, error) {
+	f, err := strconv.ParseFloat(s, bitSize)
+	if err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			return f, Loss{Abs: math.Inf(1), Rel: math.Inf(1)}, err
+		}
+		return f, Loss{}, err
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return f, Loss{Exact: true}, nil // "NaN", "Inf" and "Infinity"
+	}
+	if f == 0 {
+		// either s is a zero or it underflowed. the loss is all of s, which
+		// may be too small for a float64 too: 1e-400 is. big.Float keeps its
+		// exponent without expanding it, as big.Rat would for something like
+		// 1e-1000000000, and a loss too small for a float64 is reported as
+		// the smallest one, so that it is never 0
+		if !hasNonzeroDigit(s) {
+			return f, Loss{Exact: true}, nil
+		}
+		want, _, err := big.ParseFloat(s, 0, 64, big.ToNearestEven)
+		if err != nil {
+			return f, Loss{}, fmt.Errorf("lossy: parsing %q: %w", s, strconv.ErrSyntax)
+		}
+		abs, _ := want.Abs(want).Float64()
+		return f, Loss{Abs: math.Max(abs, math.SmallestNonzeroFloat64), Rel: 1}, nil
+	}
+	want, ok := new(big.Rat).SetString(strings.ReplaceAll(s, "_", ""))
+	if !ok {
+		return f, Loss{}, fmt.Errorf("lossy: parsing %q: %w", s, strconv.ErrSyntax)
+	}
+	diff := new(big.Rat).Sub(new(big.Rat).SetFloat64(f), want)
+	if diff.Sign() == 0 {
+		return f, Loss{Exact: true}, nil
+	}
+	abs, _ := diff.Abs(diff).Float64()
+	rel, _ := diff.Quo(diff, want.Abs(want)).Float64()
+	return f, Loss{Abs: abs, Rel: rel}, nil
+}
+
+// hasNonzeroDigit reports whether the mantissa of a number that parsed has a
+// digit other than 0.
+func hasNonzeroDigit(s string) bool {
+	s = strings.TrimLeft(s, "+-")
+	exp := "eE"
+	if len(s) > 1 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
+		s, exp = s[2:], "pP"
+	}
+	if i := strings.IndexAny(s, exp); i >= 0 {
+		s = s[:i]
+	}
+	return strings.ContainsFunc(s, func(r rune) bool {
+		return r != '0' && r != '.' && r != '_'
+	})
+}
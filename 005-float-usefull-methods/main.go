@@ -119,6 +119,13 @@ func main() {
 
 }
 
+// NOTE: an absolute epsilon only works when you know the scale of the values:
+// 1e-9 is huge next to 1e-12 and tiny next to 1e12 (neighbouring float64 values
+// there are ~1e-4 apart). for relative, combined and ULP comparison with proper
+// NaN/Inf/±0 handling see the ./floatcmp package. when a result needs a
+// guaranteed error bound rather than a guessed epsilon, compute it with the
+// ./interval package: every operation rounds outward, so the exact value is
+// always inside [Lo, Hi]
 func floatEquals(a float64, b float64) bool {
 	return math.Abs(a-b) < epsilon
 }
@@ -219,6 +226,14 @@ Answer: false
 Explanation: Floating-point precision issue!
 0.1 + 0.2 = 0.30000000000000004 (not exactly 0.3)
 This is due to binary representation of decimals
+
+the ./exact package proves it with rationals (for float64 variables; the
+untyped constants 0.1 + 0.2 are added exactly by the compiler):
+    exact.Float(0.1) = 0.1000000000000000055511151231257827021181583404541015625
+    exact.Float(0.2) = 0.200000000000000011102230246251565404236316680908203125
+    their exact sum rounds Up to 0.30000000000000004, while
+    exact.Float(0.3) = 0.299999999999999988897769753748434595763683319091796875
+and exact.String prints repeating decimals like 1/3 as 0.(3)
 */
 
 // Q7. How should you compare floating-point numbers?
@@ -360,6 +375,11 @@ Answer:
 'x': Hexadecimal (rarely used)
 
 Most common: 'f' for fixed decimal, 'e'/'E' for scientific notation
+
+None of them does engineering notation (12.3e+03), SI prefixes (4.7k),
+significant figures or thousands grouping; the ./floatfmt package adds
+those, takes the decimal point and grouping of a language from
+golang.org/x/text, and parses all of it back.
 */
 
 // Q16. What is the output?
@@ -412,6 +432,9 @@ Explanation:
 // SECTION 3: ADVANCED LEVEL - NaN BEHAVIOR
 // ============================================================================
 
+// to see the bits behind this section and the next (quiet/signaling NaN,
+// payloads, ±Inf, ±0) run: go run ./cmd/float 'math.NaN()'
+
 // Q19. What is the output?
 /*
 func main() {
@@ -607,6 +630,16 @@ Any other value (like 2 or -2) returns false
 // SECTION 5: TYPE CONVERSION AND PRECISION
 // ============================================================================
 
+// go run ./cmd/float 'float32(1.23456789)' prints the exact value stored,
+// its neighbours and the gap between them (the ULP), which is where the
+// "~7 digits" and "~16 digits" below come from
+//
+// Q31-Q34 lose digits without a word. at run time the ./lossy package
+// reports whether a conversion was exact and by how much it was off
+// (lossy.Float32, lossy.Float64 for int64, lossy.Int64, lossy.ParseFloat);
+// go run ./cmd/lossycheck ./... finds constants like the 1.23456789 of Q31
+// that the compiler rounds silently
+
 // Q31. What is the output?
 /*
 func main() {
@@ -804,6 +837,15 @@ Explanation:
 1. Multiply by 10^decimals to shift decimal point
 2. Round to nearest integer
 3. Divide by 10^decimals to shift back
+
+Caveat: x*shift is itself a rounded float, so ties go the wrong way:
+roundToDecimal(1.005, 2) is 1 because 1.005 is stored as 1.00499999999999989...
+For floats use the ./rounding package, which rounds the shortest decimal
+(what Println shows) in any mode, to places or significant figures:
+rounding.Places(1.005, 2, rounding.HalfAwayFromZero) // 1.01
+go run ./cmd/roundtable 1.005 2.675 shows where this function goes wrong.
+For money use the ./decimal package, which keeps exact decimal digits:
+decimal.MustParseFixed("1.005").Round(2, rounding.HalfAwayFromZero) // 1.01
 */
 
 // Q44. How do you check if two floats are approximately equal?
@@ -845,6 +887,10 @@ func safeDivide(a, b float64) (float64, error) {
 
 Note: In Go, float division by zero doesn't panic, it returns Inf
 But you might want to handle it as an error in your application
+
+The ./checked package does this for every operation (Sqrt, Log, Pow,
+conversions...), classifies the error (domain, invalid, overflow,
+underflow...) and checked.Trace finds the first operation that made a NaN
 */
 
 // ============================================================================
@@ -946,6 +992,10 @@ Answer: false
 Explanation: Accumulated floating-point errors
 1.0 + (0.1 * 10) ≠ 2.0 exactly due to binary representation
 x will be approximately 2.0000000000000004
+
+the error grows with every addition: += of 0.1 ten million times is off by
+about 1.6e-4. stable.Neumaier returns the correctly rounded 1e6; run
+go run ./cmd/sumcheck to compare the summation methods.
 */
 
 // Q52. What is the output?
@@ -1306,7 +1356,8 @@ Answer:
 
 2. Accumulation errors in loops
    Bad: sum := 0.0; for i := 0; i < 1000; i++ { sum += 0.1 }
-   Good: Use integer arithmetic or decimal library
+   Good: Use integer arithmetic or decimal library, or a compensated sum
+         (./stable: Kahan, Neumaier, Pairwise; Stats for mean and variance)
 
 3. Comparing NaN
    Bad: if x == math.NaN() { ... }
@@ -0,0 +1,226 @@
+package rounding
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// digits is a decimal number 0.d[0]d[1]... * 10^dp, the same layout
+// strconv uses internally. d holds ASCII digits without leading zeros; an
+// empty d is zero.
+type digits struct {
+	neg bool
+	d   []byte
+	dp  int
+}
+
+// fromFloat returns the shortest decimal that parses back to x, and
+// ok = false for NaN and infinities.
+func fromFloat[F Float](x F) (digits, bool) {
+	f := float64(x)
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return digits{}, false
+	}
+	// "d.dddde±xx": exactly the digits fmt.Println shows
+	s := strconv.FormatFloat(math.Abs(f), 'e', -1, bitSize(x))
+	e := strings.IndexByte(s, 'e')
+	exp, _ := strconv.Atoi(s[e+1:])
+	d := digits{neg: math.Signbit(f), dp: exp + 1}
+	for i := range e {
+		if s[i] != '.' {
+			d.d = append(d.d, s[i])
+		}
+	}
+	d.trim()
+	return d, true
+}
+
+// trim drops trailing zeros and turns 0 into the empty digit string.
+func (d *digits) trim() {
+	d.d = []byte(strings.TrimRight(string(d.d), "0"))
+	if len(d.d) == 0 {
+		d.dp = 0
+	}
+}
+
+// round keeps the first n digits, rounding away the rest with mode. n may
+// be negative or zero: then every digit lies below the last kept place.
+func (d *digits) round(n int, mode Mode) {
+	if n >= len(d.d) || len(d.d) == 0 {
+		return // already exact
+	}
+	// how the dropped digits compare to half a unit of the last kept place.
+	// with n < 0 the dropped part starts with at least one implicit zero,
+	// so it is below half
+	var cmp int
+	switch {
+	case n < 0:
+		cmp = -1
+	case d.d[n] < '5':
+		cmp = -1
+	case d.d[n] > '5' || strings.Trim(string(d.d[n+1:]), "0") != "":
+		cmp = 1
+	}
+	kept := d.d[:max(n, 0)]
+	odd := len(kept) > 0 && (kept[len(kept)-1]-'0')%2 == 1
+
+	var up bool // away from zero, in magnitude
+	switch mode {
+	case HalfEven:
+		up = cmp > 0 || cmp == 0 && odd
+	case HalfUp:
+		up = cmp > 0 || cmp == 0 && !d.neg
+	case HalfDown:
+		up = cmp > 0 || cmp == 0 && d.neg
+	case HalfAwayFromZero:
+		up = cmp >= 0
+	case HalfTowardZero:
+		up = cmp > 0
+	case Ceiling:
+		up = !d.neg
+	case Floor:
+		up = d.neg
+	case TowardZero:
+		up = false
+	case AwayFromZero:
+		up = true
+	}
+
+	if !up {
+		d.d = kept
+		d.trim()
+		return
+	}
+	if n < 0 {
+		// everything was dropped: the result is one unit of the place
+		d.d, d.dp = []byte{'1'}, d.dp-n+1
+		return
+	}
+	// add one to the last kept digit, carrying through nines
+	i := n - 1
+	for i >= 0 && kept[i] == '9' {
+		i--
+	}
+	if i < 0 {
+		d.d, d.dp = []byte{'1'}, d.dp+1
+		return
+	}
+	kept[i]++
+	d.d = kept[:i+1]
+}
+
+// roundPlaces rounds to n digits after the point.
+func (d *digits) roundPlaces(n int, mode Mode) {
+	if n >= len(d.d)-d.dp {
+		return // also keeps d.dp+n from overflowing
+	}
+	d.round(d.dp+max(n, -maxExponent), mode)
+}
+
+// toFloat parses the digits back into the type of like. zero keeps the sign
+// and a result beyond the largest finite value becomes ±Inf.
+func toFloat[F Float](d digits, like F) F {
+	if len(d.d) == 0 {
+		if d.neg {
+			return F(math.Copysign(0, -1))
+		}
+		return 0
+	}
+	s := "0." + string(d.d) + "e" + strconv.Itoa(d.dp)
+	if d.neg {
+		s = "-" + s
+	}
+	// the only possible error is ErrRange, and then f is already ±Inf
+	f, _ := strconv.ParseFloat(s, bitSize(like))
+	return F(f)
+}
+
+// fixed formats d with exactly n digits after the point, none if n <= 0.
+// like strconv, a negative value that rounded to zero keeps its "-".
+func (d digits) fixed(n int) string {
+	var b strings.Builder
+	if d.neg {
+		b.WriteByte('-')
+	}
+	digit := func(i int) byte {
+		if i >= 0 && i < len(d.d) {
+			return d.d[i]
+		}
+		return '0'
+	}
+	if d.dp <= 0 {
+		b.WriteByte('0')
+	}
+	for i := range d.dp {
+		b.WriteByte(digit(i))
+	}
+	if n > 0 {
+		b.WriteByte('.')
+		for i := range n {
+			b.WriteByte(digit(d.dp + i))
+		}
+	}
+	return b.String()
+}
+
+// maxExponent bounds exponents in Decimal input and places arguments, far
+// beyond anything a float64 can need.
+const maxExponent = 100000
+
+// parseDecimal reads [+-]digits[.digits][(e|E)[+-]digits].
+func parseDecimal(s string) (digits, error) {
+	var d digits
+	i := 0
+	if i < len(s) && (s[i] == '+' || s[i] == '-') {
+		d.neg = s[i] == '-'
+		i++
+	}
+	seen := false
+	for ; i < len(s) && isDigit(s[i]); i++ {
+		d.d, seen = append(d.d, s[i]), true
+		d.dp++
+	}
+	if i < len(s) && s[i] == '.' {
+		for i++; i < len(s) && isDigit(s[i]); i++ {
+			d.d, seen = append(d.d, s[i]), true
+		}
+	}
+	if !seen {
+		return digits{}, syntaxError(s)
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		neg := i < len(s) && s[i] == '-'
+		if i < len(s) && (s[i] == '+' || s[i] == '-') {
+			i++
+		}
+		exp, start := 0, i
+		for ; i < len(s) && isDigit(s[i]) && exp <= maxExponent; i++ {
+			exp = exp*10 + int(s[i]-'0')
+		}
+		if i == start || exp > maxExponent {
+			return digits{}, syntaxError(s)
+		}
+		if neg {
+			exp = -exp
+		}
+		d.dp += exp
+	}
+	if i != len(s) {
+		return digits{}, syntaxError(s)
+	}
+	for len(d.d) > 0 && d.d[0] == '0' {
+		d.d = d.d[1:]
+		d.dp--
+	}
+	d.trim()
+	return d, nil
+}
+
+func syntaxError(s string) error {
+	return fmt.Errorf("rounding: parsing %q: %w", s, strconv.ErrSyntax)
+}
+
+func isDigit(c byte) bool { return '0' <= c && c <= '9' }
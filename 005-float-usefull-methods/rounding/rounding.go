@@ -0,0 +1,119 @@
+// Package rounding rounds floats to decimal places or significant figures
+// with every common rounding mode, correctly.
+//
+// the lesson's roundToDecimal does math.Round(x*100)/100, and x*100 is a new
+// rounded float: 1.005*100 is 100.49999999999999, so 1.005 rounds to 1.
+// this package never multiplies. it takes the shortest decimal that
+// identifies the float (strconv's 'e', -1 formatting: the digits fmt.Println
+// shows, "1.005"), rounds those digits as text and parses the result back,
+// so the answer is what a person rounding the printed number would write.
+//
+//	rounding.Places(1.005, 2, rounding.HalfAwayFromZero)  // 1.01
+//	rounding.Places(2.5, 0, rounding.HalfEven)            // 2
+//	rounding.Significant(123456, 2, rounding.Floor)       // 120000
+//	rounding.FormatPlaces(0.1, 3, rounding.HalfEven)      // "0.100"
+//	rounding.Decimal("-2.345", 2, rounding.HalfUp)        // "-2.34", nil
+//
+// NaN and ±Inf are returned unchanged, the sign of zero is kept (like
+// math.Round(-0.4) is -0), and a result beyond the largest float is ±Inf.
+package rounding
+
+import (
+	"fmt"
+	"strconv"
+	"unsafe"
+)
+
+// Float is float32 or float64.
+type Float interface {
+	~float32 | ~float64
+}
+
+// Mode says where a value between two candidates goes. the Half modes only
+// differ on exact ties (2.5, -0.125 to 2 places); the others move every
+// inexact value the same way.
+//
+// HalfUp means ties toward +Inf, as in "round half up" on a number line.
+// java's HALF_UP means ties away from zero, which is HalfAwayFromZero here.
+// the decimal package rounds with these same modes.
+type Mode int
+
+const (
+	HalfEven         Mode = iota // ties to the even digit (math.RoundToEven); the zero value
+	HalfUp                       // ties toward +Inf: 2.5 -> 3, -2.5 -> -2
+	HalfDown                     // ties toward -Inf: 2.5 -> 2, -2.5 -> -3
+	HalfAwayFromZero             // ties away from zero (math.Round): 2.5 -> 3, -2.5 -> -3
+	HalfTowardZero               // ties toward zero: 2.5 -> 2, -2.5 -> -2
+	Ceiling                      // toward +Inf (math.Ceil)
+	Floor                        // toward -Inf (math.Floor)
+	TowardZero                   // truncate (math.Trunc)
+	AwayFromZero                 // up in magnitude: 2.1 -> 3, -2.1 -> -3
+)
+
+var modeNames = [...]string{
+	"HalfEven", "HalfUp", "HalfDown", "HalfAwayFromZero", "HalfTowardZero",
+	"Ceiling", "Floor", "TowardZero", "AwayFromZero",
+}
+
+// Modes lists every mode, in declaration order.
+var Modes = []Mode{HalfEven, HalfUp, HalfDown, HalfAwayFromZero, HalfTowardZero, Ceiling, Floor, TowardZero, AwayFromZero}
+
+func (m Mode) String() string {
+	if m >= 0 && int(m) < len(modeNames) {
+		return modeNames[m]
+	}
+	return fmt.Sprintf("Mode(%d)", int(m))
+}
+
+// Places rounds x to n digits after the decimal point. n may be negative to
+// round to tens (-1), hundreds (-2) and so on.
+func Places[F Float](x F, n int, mode Mode) F {
+	d, ok := fromFloat(x)
+	if !ok {
+		return x
+	}
+	d.roundPlaces(n, mode)
+	return toFloat(d, x)
+}
+
+// Significant rounds x to n significant digits. n < 1 is treated as 1.
+func Significant[F Float](x F, n int, mode Mode) F {
+	d, ok := fromFloat(x)
+	if !ok {
+		return x
+	}
+	d.round(max(n, 1), mode)
+	return toFloat(d, x)
+}
+
+// FormatPlaces rounds x like Places and formats it with exactly n digits
+// after the point (none if n <= 0), like %.nf but with the chosen mode.
+// NaN and infinities format as strconv does.
+func FormatPlaces[F Float](x F, n int, mode Mode) string {
+	d, ok := fromFloat(x)
+	if !ok {
+		return strconv.FormatFloat(float64(x), 'f', -1, bitSize(x))
+	}
+	d.roundPlaces(n, mode)
+	return d.fixed(n)
+}
+
+// Decimal rounds the decimal number in s ([+-]digits[.digits], with an
+// optional exponent) to n places and formats it like FormatPlaces. it
+// rounds the text itself, with no float involved, so any number of digits
+// is exact.
+func Decimal(s string, n int, mode Mode) (string, error) {
+	d, err := parseDecimal(s)
+	if err != nil {
+		return "", err
+	}
+	d.roundPlaces(n, mode)
+	return d.fixed(n), nil
+}
+
+func bitSize[F Float](x F) int {
+	if unsafe.Sizeof(x) == 4 {
+		return 32
+	}
+	return 64
+}
@@ -0,0 +1,183 @@
+package rounding
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+// placesCase rounds x to n places; want is FormatPlaces output per mode, in
+// the order of Modes:
+//
+//	HalfEven HalfUp HalfDown HalfAwayFromZero HalfTowardZero
+//	Ceiling Floor TowardZero AwayFromZero
+type placesCase struct {
+	x    float64
+	n    int
+	want [9]string
+	note string
+}
+
+var placesCases = []placesCase{
+	// values whose float is just below the written tie: x*10^n rounds the
+	// wrong way, the shortest decimal does not
+	{1.005, 2, [9]string{"1.00", "1.01", "1.00", "1.01", "1.00", "1.01", "1.00", "1.00", "1.01"}, "stored as 1.00499999999999989..."},
+	{-1.005, 2, [9]string{"-1.00", "-1.00", "-1.01", "-1.01", "-1.00", "-1.00", "-1.01", "-1.00", "-1.01"}, ""},
+	{2.675, 2, [9]string{"2.68", "2.68", "2.67", "2.68", "2.67", "2.68", "2.67", "2.67", "2.68"}, "stored as 2.67499999999999982..."},
+	{1.015, 2, [9]string{"1.02", "1.02", "1.01", "1.02", "1.01", "1.02", "1.01", "1.01", "1.02"}, "stored as 1.01499999999999990..."},
+	{0.045, 2, [9]string{"0.04", "0.05", "0.04", "0.05", "0.04", "0.05", "0.04", "0.04", "0.05"}, "stored as 0.04499999999999999..."},
+	{4.35, 1, [9]string{"4.4", "4.4", "4.3", "4.4", "4.3", "4.4", "4.3", "4.3", "4.4"}, "stored as 4.34999999999999964..."},
+	{1.45, 1, [9]string{"1.4", "1.5", "1.4", "1.5", "1.4", "1.5", "1.4", "1.4", "1.5"}, "stored as 1.44999999999999995..."},
+	{8.345, 2, [9]string{"8.34", "8.35", "8.34", "8.35", "8.34", "8.35", "8.34", "8.34", "8.35"}, ""},
+	{39.425, 2, [9]string{"39.42", "39.43", "39.42", "39.43", "39.42", "39.43", "39.42", "39.42", "39.43"}, ""},
+	{0.285, 2, [9]string{"0.28", "0.29", "0.28", "0.29", "0.28", "0.29", "0.28", "0.28", "0.29"}, ""},
+	{0.615, 2, [9]string{"0.62", "0.62", "0.61", "0.62", "0.61", "0.62", "0.61", "0.61", "0.62"}, ""},
+	{10.075, 2, [9]string{"10.08", "10.08", "10.07", "10.08", "10.07", "10.08", "10.07", "10.07", "10.08"}, ""},
+	{2.345e-5, 7, [9]string{"0.0000234", "0.0000235", "0.0000234", "0.0000235", "0.0000234", "0.0000235", "0.0000234", "0.0000234", "0.0000235"}, ""},
+
+	// exact binary ties: every mode differs somewhere
+	{0.125, 2, [9]string{"0.12", "0.13", "0.12", "0.13", "0.12", "0.13", "0.12", "0.12", "0.13"}, ""},
+	{0.5, 0, [9]string{"0", "1", "0", "1", "0", "1", "0", "0", "1"}, ""},
+	{-0.5, 0, [9]string{"-0", "-0", "-1", "-1", "-0", "-0", "-1", "-0", "-1"}, "the sign of zero is kept, like math.Round"},
+	{1.5, 0, [9]string{"2", "2", "1", "2", "1", "2", "1", "1", "2"}, ""},
+	{2.5, 0, [9]string{"2", "3", "2", "3", "2", "3", "2", "2", "3"}, ""},
+	{-2.5, 0, [9]string{"-2", "-2", "-3", "-3", "-2", "-2", "-3", "-2", "-3"}, ""},
+	{-1.55, 1, [9]string{"-1.6", "-1.5", "-1.6", "-1.6", "-1.5", "-1.5", "-1.6", "-1.5", "-1.6"}, ""},
+	{4503599627370495.5, 0, [9]string{"4503599627370496", "4503599627370496", "4503599627370495", "4503599627370496", "4503599627370495", "4503599627370496", "4503599627370495", "4503599627370495", "4503599627370496"}, "2^52 - 0.5, the last float with a fraction"},
+
+	// carries through nines
+	{9.995, 2, [9]string{"10.00", "10.00", "9.99", "10.00", "9.99", "10.00", "9.99", "9.99", "10.00"}, ""},
+	{0.95, 1, [9]string{"1.0", "1.0", "0.9", "1.0", "0.9", "1.0", "0.9", "0.9", "1.0"}, ""},
+	{99.5, 0, [9]string{"100", "100", "99", "100", "99", "100", "99", "99", "100"}, ""},
+
+	// one ULP either side of a tie
+	{2.5000000000000004, 0, [9]string{"3", "3", "3", "3", "3", "3", "2", "2", "3"}, ""},
+	{2.4999999999999996, 0, [9]string{"2", "2", "2", "2", "2", "3", "2", "2", "3"}, ""},
+	{0.49999999999999994, 0, [9]string{"0", "0", "0", "0", "0", "1", "0", "0", "1"}, "x+0.5 rounds up to 1 in float64"},
+	{0.30000000000000004, 2, [9]string{"0.30", "0.30", "0.30", "0.30", "0.30", "0.31", "0.30", "0.30", "0.31"}, "0.1 + 0.2"},
+
+	// negative places, tiny values and exact values
+	{1234.5, -2, [9]string{"1200", "1200", "1200", "1200", "1200", "1300", "1200", "1200", "1300"}, ""},
+	{950, -2, [9]string{"1000", "1000", "900", "1000", "900", "1000", "900", "900", "1000"}, ""},
+	{0.0001, 2, [9]string{"0.00", "0.00", "0.00", "0.00", "0.00", "0.01", "0.00", "0.00", "0.01"}, ""},
+	{-0.0001, 2, [9]string{"-0.00", "-0.00", "-0.00", "-0.00", "-0.00", "-0.00", "-0.01", "-0.00", "-0.01"}, ""},
+	{5e-324, 3, [9]string{"0.000", "0.000", "0.000", "0.000", "0.000", "0.001", "0.000", "0.000", "0.001"}, "smallest subnormal"},
+	{1, 2, [9]string{"1.00", "1.00", "1.00", "1.00", "1.00", "1.00", "1.00", "1.00", "1.00"}, ""},
+	{123.456, 5, [9]string{"123.45600", "123.45600", "123.45600", "123.45600", "123.45600", "123.45600", "123.45600", "123.45600", "123.45600"}, ""},
+	{1e22, -21, [9]string{"10000000000000000000000", "10000000000000000000000", "10000000000000000000000", "10000000000000000000000", "10000000000000000000000", "10000000000000000000000", "10000000000000000000000", "10000000000000000000000", "10000000000000000000000"}, ""},
+}
+
+// floatCase checks a float result of Places or Significant, including the
+// sign of zero and overflow to infinity.
+type floatCase struct {
+	fn   string // "Places" or "Significant"
+	x    float64
+	n    int
+	mode Mode
+	want float64
+	f32  bool // round float32(x) instead of x
+}
+
+var negZero = math.Copysign(0, -1)
+
+var floatCases = []floatCase{
+	{"Places", 1.005, 2, HalfAwayFromZero, 1.01, false},
+	{"Places", -0.4, 0, HalfEven, negZero, false},
+	{"Places", math.MaxFloat64, -308, HalfEven, math.Inf(1), false},
+	{"Places", -math.MaxFloat64, -308, Ceiling, -1e308, false},
+	{"Places", math.NaN(), 2, HalfEven, math.NaN(), false},
+	{"Places", math.Inf(-1), 2, Floor, math.Inf(-1), false},
+	{"Places", 5e-324, 400, HalfEven, 5e-324, false},
+	{"Places", 0.0, -3, AwayFromZero, 0, false},
+	{"Places", 0.145, 2, HalfEven, float64(float32(0.14)), true},
+	{"Places", 0.145, 2, HalfUp, float64(float32(0.15)), true},
+	{"Places", 8388607.5, 0, HalfEven, 8388608, true},
+	{"Significant", 123456, 2, HalfEven, 120000, false},
+	{"Significant", 123456, 2, Ceiling, 130000, false},
+	{"Significant", 0.000123456, 3, HalfEven, 0.000123, false},
+	{"Significant", 0.000123456, 3, Ceiling, 0.000124, false},
+	{"Significant", 9.995, 3, HalfUp, 10, false},
+	{"Significant", 1234.5, 4, HalfEven, 1234, false},
+	{"Significant", 1234.5, 4, HalfAwayFromZero, 1235, false},
+	{"Significant", -1234.5, 4, HalfUp, -1234, false},
+	{"Significant", 299792458, 3, HalfEven, 3e8, false},
+	{"Significant", 0.30000000000000004, 15, HalfEven, 0.3, false},
+	{"Significant", math.MaxFloat64, 1, HalfEven, math.Inf(1), false},
+	{"Significant", math.MaxFloat64, 2, Floor, 1.7e308, false},
+	{"Significant", 2.2250738585072014e-308, 3, HalfEven, 2.23e-308, false},
+	{"Significant", 5e-324, 1, AwayFromZero, 5e-324, false},
+	{"Significant", negZero, 3, AwayFromZero, negZero, false},
+	{"Significant", 7.5, 0, HalfEven, 8, false},
+}
+
+// decimalCase checks Decimal, which rounds text with no float in between.
+type decimalCase struct {
+	s    string
+	n    int
+	mode Mode
+	want string // "error" for invalid input
+}
+
+var decimalCases = []decimalCase{
+	{"2.5000000000000000000001", 0, HalfEven, "3"},
+	{"2.50000000000000000000", 0, HalfEven, "2"},
+	{"-0.0050", 2, HalfUp, "-0.00"},
+	{"-0.0050", 2, HalfDown, "-0.01"},
+	{"123456789012345678901234567890.5", 0, HalfEven, "123456789012345678901234567890"},
+	{"123456789012345678901234567891.5", 0, HalfEven, "123456789012345678901234567892"},
+	{"1e-5", 2, Ceiling, "0.01"},
+	{"9.9999", 3, HalfAwayFromZero, "10.000"},
+	{"+000.00450", 3, HalfEven, "0.004"},
+	{"1.005", 2, HalfAwayFromZero, "1.01"},
+	{"5E2", -3, HalfEven, "0"},
+	{"5E2", -3, HalfAwayFromZero, "1000"},
+	{".5", 0, HalfUp, "1"},
+	{"abc", 2, HalfEven, "error"},
+	{"1.2.3", 2, HalfEven, "error"},
+	{"1e", 2, HalfEven, "error"},
+	{"", 2, HalfEven, "error"},
+}
+
+func TestFormatPlaces(t *testing.T) {
+	for _, c := range placesCases {
+		for i, m := range Modes {
+			if got := FormatPlaces(c.x, c.n, m); got != c.want[i] {
+				t.Errorf("FormatPlaces(%v, %d, %v) = %s, want %s %s", c.x, c.n, m, got, c.want[i], c.note)
+			}
+		}
+	}
+}
+
+func TestPlacesAndSignificant(t *testing.T) {
+	for _, c := range floatCases {
+		var got float64
+		x := fmt.Sprint(c.x)
+		switch {
+		case c.fn == "Places" && c.f32:
+			got, x = float64(Places(float32(c.x), c.n, c.mode)), "float32("+x+")"
+		case c.fn == "Places":
+			got = Places(c.x, c.n, c.mode)
+		case c.f32:
+			got, x = float64(Significant(float32(c.x), c.n, c.mode)), "float32("+x+")"
+		default:
+			got = Significant(c.x, c.n, c.mode)
+		}
+		same := got == c.want && math.Signbit(got) == math.Signbit(c.want) ||
+			math.IsNaN(got) && math.IsNaN(c.want)
+		if !same {
+			t.Errorf("%s(%s, %d, %v) = %v, want %v", c.fn, x, c.n, c.mode, got, c.want)
+		}
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	for _, c := range decimalCases {
+		got, err := Decimal(c.s, c.n, c.mode)
+		if err != nil {
+			got = "error"
+		}
+		if got != c.want {
+			t.Errorf("Decimal(%q, %d, %v) = %s, want %s", c.s, c.n, c.mode, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package stable
+
+import "math"
+
+// Stats is a running count, mean, variance, minimum and maximum, updated one
+// value at a time with Welford's method. the zero value is empty and ready
+// to use:
+//
+//	var s stable.Stats
+//	for _, x := range xs {
+//		s.Add(x)
+//	}
+//	fmt.Println(s.Mean(), s.StdDev())
+//
+// NaN values make every result NaN, like they would in a plain loop, unless
+// SkipNaN is set; either way NaNs counts them. to reduce in parallel, give
+// each goroutine its own Stats and Merge them at the end.
+type Stats struct {
+	// SkipNaN leaves NaN values out of every result except NaNs.
+	SkipNaN bool
+
+	n        int64
+	mean, m2 float64 // m2 is the sum of squared differences from the mean
+	min, max float64
+	nans     int64
+}
+
+// Add adds x to the statistics.
+func (s *Stats) Add(x float64) {
+	if math.IsNaN(x) {
+		s.nans++
+		return
+	}
+	s.n++
+	if s.n == 1 {
+		s.mean, s.m2, s.min, s.max = x, 0, x, x
+		return
+	}
+	// the new mean moves by delta/n; m2 grows by the product of the
+	// distances to the old and the new mean, which never cancels
+	delta := x - s.mean
+	s.mean += delta / float64(s.n)
+	s.m2 += delta * (x - s.mean)
+	s.min, s.max = minimum(s.min, x), maximum(s.max, x)
+}
+
+// Merge adds all values seen by t to s, as if s had seen them itself (Chan,
+// Golub and LeVeque's pairwise update). s keeps its own SkipNaN.
+func (s *Stats) Merge(t Stats) {
+	s.nans += t.nans
+	switch {
+	case t.n == 0:
+		return
+	case s.n == 0:
+		s.n, s.mean, s.m2, s.min, s.max = t.n, t.mean, t.m2, t.min, t.max
+		return
+	}
+	n := s.n + t.n
+	delta := t.mean - s.mean
+	fs, ft := float64(s.n), float64(t.n)
+	s.mean += delta * ft / float64(n)
+	s.m2 += t.m2 + delta*delta*fs*ft/float64(n)
+	s.n = n
+	s.min, s.max = minimum(s.min, t.min), maximum(s.max, t.max)
+}
+
+// Reset empties s, keeping SkipNaN.
+func (s *Stats) Reset() { *s = Stats{SkipNaN: s.SkipNaN} }
+
+// N returns the number of values added, without the skipped NaNs.
+func (s *Stats) N() int64 {
+	if s.SkipNaN {
+		return s.n
+	}
+	return s.n + s.nans
+}
+
+// NaNs returns the number of NaN values added.
+func (s *Stats) NaNs() int64 { return s.nans }
+
+// poisoned reports whether a NaN makes every result NaN.
+func (s *Stats) poisoned() bool { return s.nans > 0 && !s.SkipNaN }
+
+// Mean returns the arithmetic mean, or NaN for no values.
+func (s *Stats) Mean() float64 {
+	if s.n == 0 || s.poisoned() {
+		return math.NaN()
+	}
+	return s.mean
+}
+
+// Variance returns the population variance, the mean squared distance from
+// the mean, or NaN for no values.
+func (s *Stats) Variance() float64 {
+	if s.n == 0 || s.poisoned() {
+		return math.NaN()
+	}
+	return s.m2 / float64(s.n)
+}
+
+// SampleVariance returns the variance with Bessel's correction (divided by
+// n-1), the estimate for the population a sample was drawn from. it is NaN
+// for fewer than 2 values.
+func (s *Stats) SampleVariance() float64 {
+	if s.n < 2 || s.poisoned() {
+		return math.NaN()
+	}
+	return s.m2 / float64(s.n-1)
+}
+
+// StdDev returns the population standard deviation.
+func (s *Stats) StdDev() float64 { return math.Sqrt(s.Variance()) }
+
+// SampleStdDev returns the sample standard deviation.
+func (s *Stats) SampleStdDev() float64 { return math.Sqrt(s.SampleVariance()) }
+
+// Min returns the smallest value, or NaN for no values. -0 is smaller than
+// +0, like math.Min.
+func (s *Stats) Min() float64 {
+	if s.n == 0 || s.poisoned() {
+		return math.NaN()
+	}
+	return s.min
+}
+
+// Max returns the largest value, or NaN for no values. +0 is larger than
+// -0, like math.Max.
+func (s *Stats) Max() float64 {
+	if s.n == 0 || s.poisoned() {
+		return math.NaN()
+	}
+	return s.max
+}
+
+// minimum and maximum order -0 before +0. NaN never reaches them: a plain
+// x < min would silently skip it, which is why Add counts it instead.
+func minimum(a, b float64) float64 {
+	if b < a || b == a && math.Signbit(b) {
+		return b
+	}
+	return a
+}
+
+func maximum(a, b float64) float64 {
+	if b > a || b == a && !math.Signbit(b) {
+		return b
+	}
+	return a
+}
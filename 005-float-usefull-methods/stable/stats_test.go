@@ -0,0 +1,80 @@
+package stable
+
+import (
+	"math"
+	"testing"
+)
+
+// values 4, 7, 13, 16 have mean 10 and population variance 22.5; an offset
+// of 1e9 makes the textbook sum(x²)/n - mean² lose every digit.
+func TestStatsLargeMean(t *testing.T) {
+	for _, offset := range []float64{0, 1e8, 1e9} {
+		var all, a, b Stats
+		for i := range 100_000 {
+			x := offset + []float64{4, 7, 13, 16}[i%4]
+			all.Add(x)
+			if i < 30_000 {
+				a.Add(x)
+			} else {
+				b.Add(x)
+			}
+		}
+		a.Merge(b)
+		for name, s := range map[string]*Stats{"Add": &all, "Merge": &a} {
+			if s.N() != 100_000 {
+				t.Errorf("%s, offset %g: N = %d", name, offset, s.N())
+			}
+			if e := math.Abs(s.Mean()-(offset+10)) / (offset + 10); e > 1e-12 {
+				t.Errorf("%s, offset %g: Mean = %v", name, offset, s.Mean())
+			}
+			if e := math.Abs(s.Variance()-22.5) / 22.5; e > 1e-9 {
+				t.Errorf("%s, offset %g: Variance = %v, want 22.5", name, offset, s.Variance())
+			}
+			if want := 22.5 * 100_000 / 99_999; math.Abs(s.SampleVariance()-want)/want > 1e-9 {
+				t.Errorf("%s, offset %g: SampleVariance = %v, want %v", name, offset, s.SampleVariance(), want)
+			}
+		}
+	}
+}
+
+func TestStatsNaN(t *testing.T) {
+	xs := []float64{3, math.NaN(), -1, 2}
+	var s, skip Stats
+	skip.SkipNaN = true
+	for _, x := range xs {
+		s.Add(x)
+		skip.Add(x)
+	}
+	if !math.IsNaN(s.Mean()) || !math.IsNaN(s.Min()) || !math.IsNaN(s.Max()) {
+		t.Errorf("with a NaN: Mean %v, Min %v, Max %v, want NaN", s.Mean(), s.Min(), s.Max())
+	}
+	if skip.Min() != -1 || skip.Max() != 3 || skip.Mean() != 4.0/3 || skip.N() != 3 || skip.NaNs() != 1 {
+		t.Errorf("SkipNaN: Min %v, Max %v, Mean %v, N %d, NaNs %d", skip.Min(), skip.Max(), skip.Mean(), skip.N(), skip.NaNs())
+	}
+}
+
+func TestStatsSignedZero(t *testing.T) {
+	var s Stats
+	s.Add(0)
+	s.Add(math.Copysign(0, -1))
+	if !math.Signbit(s.Min()) || math.Signbit(s.Max()) {
+		t.Errorf("Min %v, Max %v of 0 and -0, want -0 and 0", s.Min(), s.Max())
+	}
+}
+
+func TestStatsEmpty(t *testing.T) {
+	var s Stats
+	if !math.IsNaN(s.Mean()) || !math.IsNaN(s.Min()) {
+		t.Errorf("empty: Mean %v, Min %v, want NaN", s.Mean(), s.Min())
+	}
+}
+
+func BenchmarkStatsAdd(b *testing.B) {
+	for range b.N {
+		var s Stats
+		for i := range 10_000 {
+			s.Add(float64(i))
+		}
+		sink += s.Mean()
+	}
+}
@@ -0,0 +1,170 @@
+// Package stable adds up floats without the drift of a plain += loop.
+//
+// every float64 addition rounds, and in a loop the rounding errors add up:
+// summing 0.1 ten million times with += is off in the 10th digit, and
+// [1, 1e100, 1, -1e100] sums to 0 instead of 2. the functions here keep the
+// error small:
+//
+//	Kahan      carries the lost low bits of each addition into the next one
+//	Neumaier   Kahan that also survives terms larger than the running sum
+//	Pairwise   adds in a tree, error grows with log n instead of n; no extra
+//	           work per element, what numpy's sum does
+//	Dot        compensated dot product, as if computed in twice the precision
+//	Exact      the correctly rounded sum via math/big, slow, for checking
+//
+// and Stats computes mean, variance and standard deviation in one pass with
+// Welford's method, which does not lose everything to cancellation like the
+// textbook sum(x²)/n - mean². Stats values computed in parallel can be
+// merged.
+//
+// with an infinite or NaN term the sums return what += would.
+package stable
+
+import (
+	"math"
+	"math/big"
+)
+
+// Naive is the plain loop, sum += x, for comparison.
+func Naive(xs []float64) float64 {
+	sum := 0.0
+	for _, x := range xs {
+		sum += x
+	}
+	return sum
+}
+
+// Kahan returns the sum of xs with Kahan's compensated summation. the error
+// does not grow with len(xs) as long as no term is much larger than the
+// running sum; use Neumaier when that can happen.
+func Kahan(xs []float64) float64 {
+	sum, c := 0.0, 0.0
+	for _, x := range xs {
+		y := x - c
+		t := sum + y
+		c = (t - sum) - y // the part of y that did not make it into t
+		sum = t
+	}
+	if !finite(sum) {
+		return Naive(xs) // c turned Inf into NaN
+	}
+	return sum
+}
+
+// Neumaier returns the sum of xs with Neumaier's improvement of Kahan's
+// method, which picks the smaller of sum and x as the one losing bits. the
+// result is within about one rounding of the exact sum unless the terms
+// cancel almost completely.
+func Neumaier(xs []float64) float64 {
+	sum, c := 0.0, 0.0
+	for _, x := range xs {
+		var e float64
+		sum, e = twoSum(sum, x)
+		c += e
+	}
+	if !finite(sum) {
+		return sum
+	}
+	return sum + c
+}
+
+// pairwiseBlock is the length below which Pairwise adds in a plain loop;
+// the tree only needs to be coarse to keep the error down.
+const pairwiseBlock = 128
+
+// Pairwise returns the sum of xs by splitting it in halves recursively. the
+// error bound grows with log2(len(xs)) instead of len(xs), at the speed of
+// the naive loop.
+func Pairwise(xs []float64) float64 {
+	if len(xs) <= pairwiseBlock {
+		return Naive(xs)
+	}
+	h := len(xs) / 2
+	return Pairwise(xs[:h]) + Pairwise(xs[h:])
+}
+
+// Dot returns the sum of x[i]*y[i], computed as if in twice the float64
+// precision and rounded once more at the end (Ogita, Rump and Oishi's
+// Dot2). it panics if the lengths differ.
+func Dot(x, y []float64) float64 {
+	if len(x) != len(y) {
+		panic("stable: Dot of vectors with different lengths")
+	}
+	sum, c := 0.0, 0.0
+	for i := range x {
+		p := x[i] * y[i]
+		pe := math.FMA(x[i], y[i], -p) // the exact rounding error of p
+		var se float64
+		sum, se = twoSum(sum, p)
+		c += pe + se
+	}
+	if !finite(sum) {
+		return NaiveDot(x, y)
+	}
+	return sum + c
+}
+
+// NaiveDot is the plain dot product loop, for comparison.
+func NaiveDot(x, y []float64) float64 {
+	if len(x) != len(y) {
+		panic("stable: Dot of vectors with different lengths")
+	}
+	sum := 0.0
+	for i := range x {
+		sum += x[i] * y[i]
+	}
+	return sum
+}
+
+// twoSum returns a+b rounded and the exact error of that rounding, so that
+// s + e == a + b exactly (Neumaier's branch form of Knuth's TwoSum).
+func twoSum(a, b float64) (s, e float64) {
+	s = a + b
+	if math.Abs(a) >= math.Abs(b) {
+		return s, (a - s) + b
+	}
+	return s, (b - s) + a
+}
+
+func finite(x float64) bool { return !math.IsInf(x, 0) && !math.IsNaN(x) }
+
+// exactPrec holds any sum of float64 values, or of products of two, without
+// rounding: products span 2^-2148 to 2^2048, plus bits for carries.
+const exactPrec = 2048 + 2148 + 64
+
+// Exact returns the sum of xs rounded once to the nearest float64. it is
+// slow and allocates; it is meant for measuring the other functions.
+func Exact(xs []float64) float64 {
+	for _, x := range xs {
+		if !finite(x) {
+			return Naive(xs)
+		}
+	}
+	sum := new(big.Float).SetPrec(exactPrec)
+	for _, x := range xs {
+		sum.Add(sum, big.NewFloat(x))
+	}
+	f, _ := sum.Float64()
+	return f
+}
+
+// ExactDot returns the dot product of x and y rounded once to the nearest
+// float64, like Exact.
+func ExactDot(x, y []float64) float64 {
+	if len(x) != len(y) {
+		panic("stable: Dot of vectors with different lengths")
+	}
+	for i := range x {
+		if !finite(x[i]) || !finite(y[i]) {
+			return NaiveDot(x, y)
+		}
+	}
+	sum := new(big.Float).SetPrec(exactPrec)
+	p := new(big.Float).SetPrec(2 * 53)
+	for i := range x {
+		p.Mul(big.NewFloat(x[i]), big.NewFloat(y[i]))
+		sum.Add(sum, p)
+	}
+	f, _ := sum.Float64()
+	return f
+}
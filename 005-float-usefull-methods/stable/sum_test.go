@@ -0,0 +1,194 @@
+package stable
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// u is the unit roundoff of float64: one rounding moves a value by at most
+// u times its magnitude.
+const u = 0x1p-53
+
+type sumCase struct {
+	name string
+	xs   []float64
+}
+
+func sumCases() []sumCase {
+	r := rand.New(rand.NewSource(1))
+	tenths := make([]float64, 1_000_000)
+	for i := range tenths {
+		tenths[i] = 0.1
+	}
+	harmonic := make([]float64, 100_000)
+	for i := range harmonic {
+		harmonic[i] = 1 / float64(i+1)
+	}
+	uniform := make([]float64, 100_000)
+	for i := range uniform {
+		uniform[i] = r.Float64()
+	}
+	mixed := make([]float64, 100_000)
+	for i := range mixed {
+		mixed[i] = r.NormFloat64() * math.Pow(10, float64(r.Intn(21)-10))
+	}
+	return []sumCase{
+		{"0.1 a million times", tenths},
+		{"1/i", harmonic},
+		{"uniform [0,1)", uniform},
+		{"mixed signs and magnitudes", mixed},
+		{"[1, 1e100, 1, -1e100]", []float64{1, 1e100, 1, -1e100}},
+		{"empty", nil},
+	}
+}
+
+func sumAbs(xs []float64) float64 {
+	s := 0.0
+	for _, x := range xs {
+		s += math.Abs(x)
+	}
+	return s * (1 + 0x1p-40) // room for the rounding of this loop
+}
+
+func allPositive(xs []float64) bool {
+	for _, x := range xs {
+		if x < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// the bounds are those of Higham, Accuracy and Stability of Numerical
+// Algorithms, chapter 4, for n terms with absolute sum A and exact sum S.
+func TestSumErrorBounds(t *testing.T) {
+	for _, c := range sumCases() {
+		exact := Exact(c.xs)
+		n, abs := float64(len(c.xs)), sumAbs(c.xs)
+		check := func(name string, got, bound float64) {
+			t.Helper()
+			if e := math.Abs(got - exact); e > bound {
+				t.Errorf("%s(%s) = %v, exact %v: error %.3g, bound %.3g", name, c.name, got, exact, e, bound)
+			}
+		}
+		// naive: (n-1)u A
+		check("Naive", Naive(c.xs), n*u*abs)
+		// Neumaier: u|S| + 2n u² A
+		check("Neumaier", Neumaier(c.xs), u*math.Abs(exact)+2*n*u*u*abs)
+		// pairwise: the naive bound of a block plus one rounding per level
+		levels := math.Ceil(math.Log2(max(n/pairwiseBlock, 1)))
+		check("Pairwise", Pairwise(c.xs), (pairwiseBlock+levels)*u*abs)
+		// Kahan: 2u|S| + O(n u²) A, when no term outgrows the running sum,
+		// which holds for positive terms
+		if allPositive(c.xs) {
+			check("Kahan", Kahan(c.xs), 2*u*math.Abs(exact)+2*n*u*u*abs)
+		}
+	}
+}
+
+func TestSumBeatsNaive(t *testing.T) {
+	xs := sumCases()[0].xs // 0.1 a million times
+	exact := Exact(xs)
+	naive := math.Abs(Naive(xs) - exact)
+	if naive == 0 {
+		t.Fatal("the naive sum of 0.1 a million times is exact; the test data is wrong")
+	}
+	for name, f := range map[string]func([]float64) float64{"Kahan": Kahan, "Neumaier": Neumaier, "Pairwise": Pairwise} {
+		if e := math.Abs(f(xs) - exact); e >= naive {
+			t.Errorf("%s is off by %.3g, no better than += (%.3g)", name, e, naive)
+		}
+	}
+}
+
+func TestNeumaierLargeTerms(t *testing.T) {
+	xs := []float64{1, 1e100, 1, -1e100}
+	if got := Naive(xs); got != 0 {
+		t.Errorf("Naive(%v) = %v, want 0, the loss the package is about", xs, got)
+	}
+	if got := Neumaier(xs); got != 2 {
+		t.Errorf("Neumaier(%v) = %v, want 2", xs, got)
+	}
+}
+
+func TestSumNonFinite(t *testing.T) {
+	for _, xs := range [][]float64{
+		{1, math.Inf(1), 2},
+		{math.Inf(1), math.Inf(-1)},
+		{1, math.NaN()},
+		{math.MaxFloat64, math.MaxFloat64},
+	} {
+		want := Naive(xs)
+		for name, f := range map[string]func([]float64) float64{"Kahan": Kahan, "Neumaier": Neumaier, "Pairwise": Pairwise, "Exact": Exact} {
+			if got := f(xs); got != want && !(math.IsNaN(got) && math.IsNaN(want)) {
+				t.Errorf("%s(%v) = %v, want %v like +=", name, xs, got, want)
+			}
+		}
+	}
+}
+
+func TestDot(t *testing.T) {
+	// products up to 1e20 that cancel to a small result
+	r := rand.New(rand.NewSource(2))
+	n := 1000
+	x, y := make([]float64, 2*n), make([]float64, 2*n)
+	for i := range n {
+		x[i] = r.NormFloat64() * 1e10
+		y[i] = r.NormFloat64() * 1e10
+		x[n+i], y[n+i] = -x[i], y[i]*(1+r.NormFloat64()*1e-15)
+	}
+	cases := []struct {
+		name string
+		x, y []float64
+	}{
+		{"[1e16, 1, -1e16]·[1, 1, 1]", []float64{1e16, 1, -1e16}, []float64{1, 1, 1}},
+		{"[1+2⁻³⁰, -1]·[1-2⁻³⁰, 1]", []float64{1 + 0x1p-30, -1}, []float64{1 - 0x1p-30, 1}},
+		{"2000 cancelling products", x, y},
+	}
+	for _, c := range cases {
+		exact := ExactDot(c.x, c.y)
+		abs := 0.0
+		for i := range c.x {
+			abs += math.Abs(c.x[i] * c.y[i])
+		}
+		// one rounding of the result plus the error of twice the precision
+		g := float64(len(c.x)) * u
+		bound := 2*u*math.Abs(exact) + g*g*abs*1.01
+		if got := Dot(c.x, c.y); math.Abs(got-exact) > bound {
+			t.Errorf("Dot(%s) = %v, exact %v, bound %.3g", c.name, got, exact, bound)
+		}
+	}
+}
+
+var sink float64
+
+func benchmarkSum(b *testing.B, sum func([]float64) float64) {
+	r := rand.New(rand.NewSource(3))
+	xs := make([]float64, 10_000)
+	for i := range xs {
+		xs[i] = r.NormFloat64()
+	}
+	b.SetBytes(int64(8 * len(xs)))
+	b.ResetTimer()
+	for range b.N {
+		sink += sum(xs)
+	}
+}
+
+func BenchmarkNaive(b *testing.B)    { benchmarkSum(b, Naive) }
+func BenchmarkKahan(b *testing.B)    { benchmarkSum(b, Kahan) }
+func BenchmarkNeumaier(b *testing.B) { benchmarkSum(b, Neumaier) }
+func BenchmarkPairwise(b *testing.B) { benchmarkSum(b, Pairwise) }
+func BenchmarkExact(b *testing.B)    { benchmarkSum(b, Exact) }
+
+func BenchmarkDot(b *testing.B) {
+	r := rand.New(rand.NewSource(4))
+	x, y := make([]float64, 10_000), make([]float64, 10_000)
+	for i := range x {
+		x[i], y[i] = r.NormFloat64(), r.NormFloat64()
+	}
+	b.SetBytes(int64(16 * len(x)))
+	for range b.N {
+		sink += Dot(x, y)
+	}
+}
@@ -0,0 +1,355 @@
+// Package ahocorasick finds many patterns in a text in one pass.
+//
+// strings.Index looks for one pattern; looking for k patterns with it reads
+// the text k times. an Aho-Corasick automaton is a trie of all the patterns
+// with extra "failure" links: on a mismatch it falls back to the longest
+// suffix of what it has read that is still the start of some pattern, so
+// every byte of the text is read once, however many patterns there are:
+//
+//	m := ahocorasick.Compile([]string{"password", "token", "secret"}, ahocorasick.Options{ASCIIFold: true})
+//	m.FindAll("Token=abc password=xyz")  // [{1 0 5} {0 10 18}]
+//	m.ReplaceAll(s, []string{"[pw]", "[tok]", "[sec]"})
+//
+// when matches overlap, Kind picks which one wins, like the choice between
+// a|ab in a regular expression; FindOverlapping reports all of them. the
+// automaton works on bytes, so ASCIIFold ignores the case of ASCII letters
+// only; normalize.Key both sides first for full Unicode folding.
+package ahocorasick
+
+import "io"
+
+// MatchKind decides between matches that overlap. both kinds take the match
+// that starts first; they differ for matches that start at the same place.
+type MatchKind int
+
+const (
+	// LeftmostFirst prefers the pattern that comes first in the list, as
+	// strings.NewReplacer does: for [a ab] in "ab" it matches "a".
+	LeftmostFirst MatchKind = iota
+	// LeftmostLongest prefers the longest pattern: for [a ab] it matches "ab".
+	LeftmostLongest
+)
+
+// Options configures Compile. the zero value is LeftmostFirst, case
+// sensitive.
+type Options struct {
+	Kind      MatchKind
+	ASCIIFold bool // A-Z match a-z
+}
+
+// Match is one occurrence of patterns[Pattern] at text[Start:End], in bytes.
+type Match struct {
+	Pattern    int
+	Start, End int
+}
+
+// Matcher is a compiled set of patterns. it is safe for concurrent use.
+type Matcher struct {
+	kind     MatchKind
+	patterns int
+	maxLen   int // of the longest pattern
+
+	// the automaton is a DFA over byte classes: bytes that no pattern uses
+	// all behave the same and share class 0, which keeps the table small
+	class  [256]byte
+	stride int     // number of classes
+	delta  []int32 // delta[s*stride+c] is the state after s reads class c
+	depth  []int32 // length of the pattern prefix state s stands for
+	out    []int32 // pattern that ends at s, or -1
+	dict   []int32 // nearest state on the failure chain of s with out >= 0, or 0
+}
+
+// Compile builds the automaton for patterns. empty patterns never match.
+// if a pattern appears more than once, matches report the first index.
+func Compile(patterns []string, opts Options) *Matcher {
+	m := &Matcher{kind: opts.Kind, patterns: len(patterns)}
+	fold := func(b byte) byte {
+		if opts.ASCIIFold && 'A' <= b && b <= 'Z' {
+			return b + 'a' - 'A'
+		}
+		return b
+	}
+
+	// byte classes
+	var used [256]bool
+	for _, p := range patterns {
+		for i := range len(p) {
+			used[fold(p[i])] = true
+		}
+	}
+	for b := range 256 {
+		if used[b] {
+			m.stride++
+			m.class[b] = byte(m.stride)
+		}
+	}
+	m.stride++ // class 0
+	for b := range 256 {
+		m.class[b] = m.class[fold(byte(b))]
+	}
+
+	// the trie; -1 marks a missing edge
+	newState := func(depth int) int32 {
+		for range m.stride {
+			m.delta = append(m.delta, -1)
+		}
+		m.depth = append(m.depth, int32(depth))
+		m.out = append(m.out, -1)
+		return int32(len(m.depth) - 1)
+	}
+	newState(0)
+	for i, p := range patterns {
+		if p == "" {
+			continue
+		}
+		m.maxLen = max(m.maxLen, len(p))
+		s := int32(0)
+		for j := range len(p) {
+			e := int(s)*m.stride + int(m.class[p[j]])
+			if m.delta[e] < 0 {
+				next := newState(j + 1)
+				m.delta[e] = next
+			}
+			s = m.delta[e]
+		}
+		if m.out[s] < 0 {
+			m.out[s] = int32(i)
+		}
+	}
+
+	// failure links in breadth-first order, so the failure state of s, which
+	// is shallower, is complete when s is reached. missing edges become the
+	// edge of the failure state, turning the trie into a DFA.
+	fail := make([]int32, len(m.depth))
+	m.dict = make([]int32, len(m.depth))
+	queue := []int32{0}
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+		for c := range m.stride {
+			e := int(s)*m.stride + c
+			child := m.delta[e]
+			if child < 0 {
+				if s == 0 {
+					m.delta[e] = 0
+				} else {
+					m.delta[e] = m.delta[int(fail[s])*m.stride+c]
+				}
+				continue
+			}
+			if s != 0 {
+				f := m.delta[int(fail[s])*m.stride+c]
+				fail[child] = f
+				if m.out[f] >= 0 {
+					m.dict[child] = f
+				} else {
+					m.dict[child] = m.dict[f]
+				}
+			}
+			queue = append(queue, child)
+		}
+	}
+	return m
+}
+
+// scanner runs the automaton over a text that may arrive in pieces, and
+// decides between overlapping matches as they are found.
+//
+// a match can only be reported when no better one can still appear. the
+// state after reading up to pos stands for the longest suffix of the text
+// that is a prefix of some pattern, depth bytes long, so every match still
+// to come starts at pos-depth or later: once that is past the start of the
+// best match so far, that match is final. matches that start after it ends
+// may have gone by while it was pending, so the scanner then reads the text
+// from its end again; that is never more than the longest pattern.
+type scanner struct {
+	m       *Matcher
+	state   int32
+	pos     int   // bytes read
+	cand    Match // best match that is not final yet; Pattern -1 if none
+	overlap bool  // report every match, no choices
+	emit    func(Match) bool
+	stopped bool
+
+	// the text read again: a string scanned whole, or the last bytes
+	// written, from offset histStart on
+	str       string
+	hist      []byte
+	histStart int
+}
+
+func (m *Matcher) newScanner(overlap bool, emit func(Match) bool) *scanner {
+	return &scanner{m: m, cand: Match{Pattern: -1}, overlap: overlap, emit: emit}
+}
+
+// write feeds the next bytes of the text. it returns false once emit has
+// asked to stop.
+func (sc *scanner) write(p []byte) bool {
+	if !sc.overlap {
+		sc.hist = append(sc.hist, p...)
+	}
+	for _, b := range p {
+		if sc.stopped {
+			return false
+		}
+		sc.step(b)
+	}
+	// the candidate, and any match still to come, starts within the longest
+	// pattern of the end, so that is all that can be read again
+	if keep := sc.pos - sc.m.maxLen; keep > sc.histStart {
+		n := copy(sc.hist, sc.hist[keep-sc.histStart:])
+		sc.hist, sc.histStart = sc.hist[:n], keep
+	}
+	return !sc.stopped
+}
+
+// writeString feeds the whole text as a string, without copying it.
+func (sc *scanner) writeString(s string) bool {
+	sc.str = s
+	for i := range len(s) {
+		if sc.stopped {
+			return false
+		}
+		sc.step(s[i])
+	}
+	return !sc.stopped
+}
+
+func (sc *scanner) byteAt(off int) byte {
+	if sc.str != "" {
+		return sc.str[off]
+	}
+	return sc.hist[off-sc.histStart]
+}
+
+func (sc *scanner) step(b byte) {
+	m := sc.m
+	sc.state = m.delta[int(sc.state)*m.stride+int(m.class[b])]
+	sc.pos++
+	x := sc.state
+	if m.out[x] < 0 {
+		x = m.dict[x]
+	}
+	if sc.overlap {
+		for ; x != 0; x = m.dict[x] {
+			sc.report(Match{int(m.out[x]), sc.pos - int(m.depth[x]), sc.pos})
+		}
+		return
+	}
+
+	// finalize the candidate if nothing can start at or before it any more,
+	// and read what came after it again from the start state, so that the
+	// next match cannot start inside it
+	if sc.cand.Pattern >= 0 && sc.pos-int(m.depth[sc.state]) > sc.cand.Start {
+		sc.finalize()
+		return
+	}
+
+	// the longest match ending here starts first; one that starts after
+	// the candidate waits until the candidate is final
+	if x == 0 {
+		return
+	}
+	mt := Match{int(m.out[x]), sc.pos - int(m.depth[x]), sc.pos}
+	switch c := sc.cand; {
+	case c.Pattern < 0 || mt.Start < c.Start:
+		sc.cand = mt
+	case mt.Start == c.Start && (m.kind == LeftmostLongest || mt.Pattern < c.Pattern):
+		sc.cand = mt // same start, ends later: longer, or a pattern listed earlier
+	}
+}
+
+// finalize reports the candidate and reads the text after it again.
+func (sc *scanner) finalize() {
+	sc.report(sc.cand)
+	end := sc.pos
+	sc.state, sc.pos = 0, sc.cand.End
+	sc.cand = Match{Pattern: -1}
+	for sc.pos < end && !sc.stopped {
+		sc.step(sc.byteAt(sc.pos))
+	}
+}
+
+// close ends the text and reports the candidates left.
+func (sc *scanner) close() {
+	for sc.cand.Pattern >= 0 && !sc.stopped {
+		sc.finalize()
+	}
+}
+
+func (sc *scanner) report(mt Match) {
+	if !sc.stopped && !sc.emit(mt) {
+		sc.stopped = true
+	}
+}
+
+// FindAll returns the matches in s that do not overlap, in order.
+func (m *Matcher) FindAll(s string) []Match {
+	var ms []Match
+	sc := m.newScanner(false, func(mt Match) bool {
+		ms = append(ms, mt)
+		return true
+	})
+	sc.writeString(s)
+	sc.close()
+	return ms
+}
+
+// Find returns the first match in s, as FindAll would report it.
+func (m *Matcher) Find(s string) (Match, bool) {
+	var first Match
+	found := false
+	sc := m.newScanner(false, func(mt Match) bool {
+		first, found = mt, true
+		return false
+	})
+	if sc.writeString(s) {
+		sc.close()
+	}
+	return first, found
+}
+
+// Contains reports whether any pattern occurs in s.
+func (m *Matcher) Contains(s string) bool {
+	_, ok := m.Find(s)
+	return ok
+}
+
+// FindOverlapping returns every occurrence of every pattern in s, ordered
+// by where they end, and for the same end, longest first.
+func (m *Matcher) FindOverlapping(s string) []Match {
+	var ms []Match
+	sc := m.newScanner(true, func(mt Match) bool {
+		ms = append(ms, mt)
+		return true
+	})
+	sc.writeString(s)
+	return ms
+}
+
+// FindReader reads r to the end and calls fn for each match, as FindAll
+// would return them, with offsets counted from the start of r. it holds no
+// more of the text than one read and the longest pattern. it stops at the first error from r or
+// fn and returns it; io.EOF is not an error.
+func (m *Matcher) FindReader(r io.Reader, fn func(Match) error) error {
+	var err error
+	sc := m.newScanner(false, func(mt Match) bool {
+		err = fn(mt)
+		return err == nil
+	})
+	buf := make([]byte, 32*1024)
+	for {
+		n, rerr := r.Read(buf)
+		if !sc.write(buf[:n]) {
+			return err
+		}
+		if rerr == io.EOF {
+			break
+		}
+		if rerr != nil {
+			return rerr
+		}
+	}
+	sc.close()
+	return err
+}
@@ -0,0 +1,290 @@
+package ahocorasick
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// naive is the reference the automaton is checked against: it tries every
+// pattern at every offset.
+type naive struct {
+	patterns []string
+	opts     Options
+}
+
+func (n naive) at(s string, i, p int) bool {
+	pat := n.patterns[p]
+	if pat == "" || len(s)-i < len(pat) {
+		return false
+	}
+	if n.opts.ASCIIFold {
+		return asciiEqualFold(s[i:i+len(pat)], pat)
+	}
+	return s[i:i+len(pat)] == pat
+}
+
+func asciiEqualFold(a, b string) bool {
+	for i := range len(a) {
+		x, y := a[i], b[i]
+		if 'A' <= x && x <= 'Z' {
+			x += 'a' - 'A'
+		}
+		if 'A' <= y && y <= 'Z' {
+			y += 'a' - 'A'
+		}
+		if x != y {
+			return false
+		}
+	}
+	return true
+}
+
+func (n naive) findAll(s string) []Match {
+	var ms []Match
+	for i := 0; i < len(s); {
+		best := Match{Pattern: -1}
+		for p, pat := range n.patterns {
+			if !n.at(s, i, p) {
+				continue
+			}
+			if best.Pattern < 0 || n.opts.Kind == LeftmostLongest && len(pat) > best.End-i {
+				best = Match{p, i, i + len(pat)}
+			}
+		}
+		if best.Pattern < 0 {
+			i++
+			continue
+		}
+		ms = append(ms, best)
+		i = best.End
+	}
+	return ms
+}
+
+func (n naive) findOverlapping(s string) []Match {
+	var ms []Match
+	for end := 1; end <= len(s); end++ {
+		var here []Match
+		for p, pat := range n.patterns {
+			start := end - len(pat)
+			if start < 0 || !n.at(s, start, p) {
+				continue
+			}
+			if slices.ContainsFunc(here, func(mt Match) bool { return mt.Start == start }) {
+				continue // the same text as an earlier pattern
+			}
+			here = append(here, Match{p, start, end})
+		}
+		slices.SortStableFunc(here, func(a, b Match) int { return a.Start - b.Start })
+		ms = append(ms, here...)
+	}
+	return ms
+}
+
+func TestMatchKind(t *testing.T) {
+	tests := []struct {
+		patterns []string
+		kind     MatchKind
+		text     string
+		want     []Match
+	}{
+		{[]string{"a", "ab"}, LeftmostFirst, "ab", []Match{{0, 0, 1}}},
+		{[]string{"a", "ab"}, LeftmostLongest, "ab", []Match{{1, 0, 2}}},
+		{[]string{"ab", "a"}, LeftmostFirst, "ab", []Match{{0, 0, 2}}},
+		{[]string{"Samwise", "Sam"}, LeftmostFirst, "Samwise", []Match{{0, 0, 7}}},
+		{[]string{"Sam", "Samwise"}, LeftmostFirst, "Samwise", []Match{{0, 0, 3}}},
+		{[]string{"Sam", "Samwise"}, LeftmostLongest, "Samwise", []Match{{1, 0, 7}}},
+		// the leftmost match wins even if a longer or earlier one ends first
+		{[]string{"bcd", "abcdef"}, LeftmostFirst, "abcdef", []Match{{1, 0, 6}}},
+		{[]string{"abcd", "bc"}, LeftmostLongest, "xabcx", []Match{{1, 2, 4}}},
+		{[]string{"abcd", "b", "c"}, LeftmostFirst, "abcx", []Match{{1, 1, 2}, {2, 2, 3}}},
+		{[]string{"x", "x"}, LeftmostLongest, "xx", []Match{{0, 0, 1}, {0, 1, 2}}},
+		{[]string{"", "a"}, LeftmostFirst, "aa", []Match{{1, 0, 1}, {1, 1, 2}}},
+		{[]string{"aa"}, LeftmostFirst, "aaa", []Match{{0, 0, 2}}},
+	}
+	for _, tt := range tests {
+		m := Compile(tt.patterns, Options{Kind: tt.kind})
+		if got := m.FindAll(tt.text); !slices.Equal(got, tt.want) {
+			t.Errorf("%q kind %d FindAll(%q) = %v, want %v", tt.patterns, tt.kind, tt.text, got, tt.want)
+		}
+		first, ok := m.Find(tt.text)
+		if want := len(tt.want) > 0; ok != want || ok && first != tt.want[0] {
+			t.Errorf("%q kind %d Find(%q) = %v, %v", tt.patterns, tt.kind, tt.text, first, ok)
+		}
+	}
+}
+
+func TestASCIIFold(t *testing.T) {
+	m := Compile([]string{"password", "TOKEN", "Straße"}, Options{ASCIIFold: true})
+	got := m.FindAll("PassWord=1 token=2 STRAßE strasse straSSE")
+	want := []Match{{0, 0, 8}, {1, 11, 16}, {2, 19, 26}}
+	if !slices.Equal(got, want) {
+		t.Errorf("FindAll = %v, want %v", got, want)
+	}
+	// only ASCII letters fold
+	if m := Compile([]string{"é"}, Options{ASCIIFold: true}); m.Contains("É") {
+		t.Error(`ASCIIFold matches "é" in "É"`)
+	}
+	if m := Compile([]string{"token"}, Options{}); m.Contains("TOKEN") {
+		t.Error(`without ASCIIFold "token" matches "TOKEN"`)
+	}
+}
+
+func TestFindOverlapping(t *testing.T) {
+	m := Compile([]string{"he", "she", "his", "hers"}, Options{})
+	got := m.FindOverlapping("ushers")
+	want := []Match{{1, 1, 4}, {0, 2, 4}, {3, 2, 6}}
+	if !slices.Equal(got, want) {
+		t.Errorf("FindOverlapping = %v, want %v", got, want)
+	}
+	m = Compile([]string{"aa", "a"}, Options{})
+	got = m.FindOverlapping("aaa")
+	want = []Match{{1, 0, 1}, {0, 0, 2}, {1, 1, 2}, {0, 1, 3}, {1, 2, 3}}
+	if !slices.Equal(got, want) {
+		t.Errorf("FindOverlapping = %v, want %v", got, want)
+	}
+}
+
+// randomCase returns patterns and a text over a small alphabet, so that
+// matches overlap and share prefixes often.
+func randomCase(r *rand.Rand) ([]string, string) {
+	word := func(n int) string {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = "abcAB"[r.Intn(5)]
+		}
+		return string(b)
+	}
+	patterns := make([]string, 1+r.Intn(6))
+	for i := range patterns {
+		patterns[i] = word(r.Intn(5))
+	}
+	return patterns, word(r.Intn(40))
+}
+
+func TestAgainstNaive(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for range 5000 {
+		patterns, text := randomCase(r)
+		opts := Options{Kind: MatchKind(r.Intn(2)), ASCIIFold: r.Intn(2) == 0}
+		m, ref := Compile(patterns, opts), naive{patterns, opts}
+		if got, want := m.FindAll(text), ref.findAll(text); !slices.Equal(got, want) {
+			t.Fatalf("%q %+v FindAll(%q) = %v, want %v", patterns, opts, text, got, want)
+		}
+		if got, want := m.FindOverlapping(text), ref.findOverlapping(text); !slices.Equal(got, want) {
+			t.Fatalf("%q %+v FindOverlapping(%q) = %v, want %v", patterns, opts, text, got, want)
+		}
+	}
+}
+
+func TestReplaceAllLikeReplacer(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for range 5000 {
+		patterns, text := randomCase(r)
+		repl := make([]string, len(patterns))
+		var oldnew []string
+		for i, p := range patterns {
+			if p == "" {
+				p = "c" // strings.Replacer matches empty strings everywhere
+				patterns[i] = p
+			}
+			repl[i] = "<" + strings.Repeat("*", i) + ">"
+			oldnew = append(oldnew, p, repl[i])
+		}
+		m := Compile(patterns, Options{})
+		if got, want := m.ReplaceAll(text, repl), strings.NewReplacer(oldnew...).Replace(text); got != want {
+			t.Fatalf("%q ReplaceAll(%q) = %q, strings.Replacer %q", patterns, text, got, want)
+		}
+	}
+}
+
+// readers return the text in pieces of different sizes.
+var readers = map[string]func(io.Reader) io.Reader{
+	"whole":   func(r io.Reader) io.Reader { return r },
+	"onebyte": iotest.OneByteReader,
+	"half":    iotest.HalfReader,
+	"dataerr": iotest.DataErrReader,
+}
+
+func TestFindReader(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for range 1000 {
+		patterns, text := randomCase(r)
+		opts := Options{Kind: MatchKind(r.Intn(2)), ASCIIFold: r.Intn(2) == 0}
+		m := Compile(patterns, opts)
+		want := m.FindAll(text)
+		for name, wrap := range readers {
+			var got []Match
+			err := m.FindReader(wrap(strings.NewReader(text)), func(mt Match) error {
+				got = append(got, mt)
+				return nil
+			})
+			if err != nil || !slices.Equal(got, want) {
+				t.Fatalf("%s: %q FindReader(%q) = %v, %v, want %v", name, patterns, text, got, err, want)
+			}
+		}
+	}
+}
+
+func TestFindReaderLongText(t *testing.T) {
+	// matches across the 32 KB reads of FindReader
+	text := strings.Repeat("x", 32*1024-3) + "needle" + strings.Repeat("y", 40*1024) + "needle"
+	m := Compile([]string{"needle"}, Options{})
+	for name, wrap := range readers {
+		var got []Match
+		err := m.FindReader(wrap(strings.NewReader(text)), func(mt Match) error {
+			got = append(got, mt)
+			return nil
+		})
+		if want := m.FindAll(text); err != nil || !slices.Equal(got, want) {
+			t.Errorf("%s: FindReader = %v, %v, want %v", name, got, err, want)
+		}
+	}
+}
+
+func TestFindReaderErrors(t *testing.T) {
+	m := Compile([]string{"a"}, Options{})
+	stop := errors.New("stop")
+	n := 0
+	err := m.FindReader(strings.NewReader("aaaa"), func(Match) error {
+		n++
+		if n == 2 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop || n != 2 {
+		t.Errorf("FindReader with fn failing on the 2nd match = %v after %d matches", err, n)
+	}
+	err = m.FindReader(iotest.TimeoutReader(strings.NewReader("xx")), func(Match) error { return nil })
+	if err != iotest.ErrTimeout {
+		t.Errorf("FindReader with a failing reader = %v, want %v", err, iotest.ErrTimeout)
+	}
+}
+
+func TestReplaceReader(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	for range 1000 {
+		patterns, text := randomCase(r)
+		opts := Options{Kind: MatchKind(r.Intn(2)), ASCIIFold: r.Intn(2) == 0}
+		m := Compile(patterns, opts)
+		repl := make([]string, len(patterns))
+		for i := range repl {
+			repl[i] = "<" + strings.Repeat("*", i) + ">"
+		}
+		want := m.ReplaceAll(text, repl)
+		for name, wrap := range readers {
+			var b bytes.Buffer
+			err := m.ReplaceReader(&b, wrap(strings.NewReader(text)), repl)
+			if err != nil || b.String() != want {
+				t.Fatalf("%s: %q ReplaceReader(%q) = %q, %v, want %q", name, patterns, text, b.String(), err, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package ahocorasick
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// the benchmarks time the package against the standard library on a
+// generated log, for 10, 100 and 1000 patterns:
+//
+//	BenchmarkIndexLoop        every occurrence of every pattern with
+//	BenchmarkFindOverlapping  strings.Index once per pattern, or in one pass
+//	BenchmarkReplacer         replacing the patterns with strings.NewReplacer
+//	BenchmarkReplaceAll       or with ReplaceAll
+//
+//	go test -run '^$' -bench . ./ahocorasick
+
+var benchSizes = []int{10, 100, 1000}
+
+// words returns n different lower case words of 5 to 12 letters.
+func words(r *rand.Rand, n int) []string {
+	seen := make(map[string]bool)
+	var ws []string
+	for len(ws) < n {
+		b := make([]byte, 5+r.Intn(8))
+		for i := range b {
+			b[i] = byte('a' + r.Intn(26))
+		}
+		if w := string(b); !seen[w] {
+			seen[w] = true
+			ws = append(ws, w)
+		}
+	}
+	return ws
+}
+
+// benchPatterns returns k secrets to look for, the same first ones for
+// every k, so a larger set also finds what a smaller one does.
+func benchPatterns(k int) []string {
+	return words(rand.New(rand.NewSource(1)), k)
+}
+
+// logText is about 1 MB of log lines. most words are ordinary; about one
+// line in twenty mentions one of the first 1000 patterns.
+var logText = sync.OnceValue(func() string {
+	r := rand.New(rand.NewSource(2))
+	vocab := words(r, 500)
+	secrets := benchPatterns(1000)
+	levels := []string{"INFO", "WARN", "DEBUG", "ERROR"}
+	var b strings.Builder
+	for b.Len() < 1<<20 {
+		fmt.Fprintf(&b, "2025-03-%02dT%02d:%02d:%02dZ %s user=%s", 1+r.Intn(28), r.Intn(24), r.Intn(60), r.Intn(60),
+			levels[r.Intn(len(levels))], vocab[r.Intn(len(vocab))])
+		for range 3 + r.Intn(6) {
+			b.WriteString(" " + vocab[r.Intn(len(vocab))])
+		}
+		if r.Intn(20) == 0 {
+			b.WriteString(" token=" + secrets[r.Intn(len(secrets))])
+		}
+		fmt.Fprintf(&b, " took=%dms\n", r.Intn(1000))
+	}
+	return b.String()
+})
+
+// indexAll counts the occurrences of every pattern with strings.Index.
+func indexAll(s string, pats []string) int {
+	n := 0
+	for _, p := range pats {
+		for i := 0; ; {
+			j := strings.Index(s[i:], p)
+			if j < 0 {
+				break
+			}
+			n++
+			i += j + 1
+		}
+	}
+	return n
+}
+
+// redaction returns the replacement of each pattern, and the same as pairs
+// for strings.NewReplacer.
+func redaction(pats []string) (repl, oldnew []string) {
+	for _, p := range pats {
+		r := "[" + strings.Repeat("*", len(p)) + "]"
+		repl = append(repl, r)
+		oldnew = append(oldnew, p, r)
+	}
+	return repl, oldnew
+}
+
+func TestBenchmarkAnswers(t *testing.T) {
+	text := logText()
+	for _, k := range benchSizes {
+		pats := benchPatterns(k)
+		m := Compile(pats, Options{})
+		if got, want := len(m.FindOverlapping(text)), indexAll(text, pats); got != want {
+			t.Errorf("%d patterns: FindOverlapping finds %d, strings.Index %d", k, got, want)
+		}
+		repl, oldnew := redaction(pats)
+		if m.ReplaceAll(text, repl) != strings.NewReplacer(oldnew...).Replace(text) {
+			t.Errorf("%d patterns: ReplaceAll and strings.NewReplacer differ", k)
+		}
+	}
+}
+
+var sink int
+
+// benchmark runs f with each number of patterns over the log text.
+func benchmark(b *testing.B, f func(b *testing.B, text string, pats []string)) {
+	text := logText()
+	for _, k := range benchSizes {
+		b.Run(fmt.Sprintf("patterns=%d", k), func(b *testing.B) {
+			b.SetBytes(int64(len(text)))
+			f(b, text, benchPatterns(k))
+		})
+	}
+}
+
+func BenchmarkIndexLoop(b *testing.B) {
+	benchmark(b, func(b *testing.B, text string, pats []string) {
+		for b.Loop() {
+			sink += indexAll(text, pats)
+		}
+	})
+}
+
+func BenchmarkFindOverlapping(b *testing.B) {
+	benchmark(b, func(b *testing.B, text string, pats []string) {
+		m := Compile(pats, Options{})
+		for b.Loop() {
+			sink += len(m.FindOverlapping(text))
+		}
+	})
+}
+
+func BenchmarkReplacer(b *testing.B) {
+	benchmark(b, func(b *testing.B, text string, pats []string) {
+		_, oldnew := redaction(pats)
+		r := strings.NewReplacer(oldnew...)
+		for b.Loop() {
+			sink += len(r.Replace(text))
+		}
+	})
+}
+
+func BenchmarkReplaceAll(b *testing.B) {
+	benchmark(b, func(b *testing.B, text string, pats []string) {
+		m := Compile(pats, Options{})
+		repl, _ := redaction(pats)
+		for b.Loop() {
+			sink += len(m.ReplaceAll(text, repl))
+		}
+	})
+}
+
+func BenchmarkCompile(b *testing.B) {
+	benchmark(b, func(b *testing.B, _ string, pats []string) {
+		b.SetBytes(0)
+		for b.Loop() {
+			sink += Compile(pats, Options{}).patterns
+		}
+	})
+}
@@ -0,0 +1,83 @@
+package ahocorasick
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ReplaceAll returns s with each match, as FindAll finds them, replaced by
+// repl[m.Pattern]. repl must have one entry per pattern. with LeftmostFirst
+// the result is the same as strings.NewReplacer with the same pairs.
+func (m *Matcher) ReplaceAll(s string, repl []string) string {
+	m.checkRepl(repl)
+	var b strings.Builder
+	last := 0
+	for _, mt := range m.FindAll(s) {
+		b.WriteString(s[last:mt.Start])
+		b.WriteString(repl[mt.Pattern])
+		last = mt.End
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// ReplaceReader copies r to w with each match replaced by repl[m.Pattern],
+// for texts too large to hold, like logs to redact. it writes a byte as soon
+// as no match can include it, so it holds about one read plus the longest
+// pattern.
+func (m *Matcher) ReplaceReader(w io.Writer, r io.Reader, repl []string) error {
+	m.checkRepl(repl)
+	var pending []byte // the text from offset base on that is not written yet
+	base := 0
+	var werr error
+	write := func(p []byte) {
+		if werr == nil {
+			_, werr = w.Write(p)
+		}
+	}
+	sc := m.newScanner(false, func(mt Match) bool {
+		write(pending[:mt.Start-base])
+		write([]byte(repl[mt.Pattern]))
+		pending, base = pending[mt.End-base:], mt.End
+		return werr == nil
+	})
+
+	buf := make([]byte, 32*1024)
+	for {
+		n, rerr := r.Read(buf)
+		pending = append(pending, buf[:n]...)
+		if !sc.write(buf[:n]) {
+			return werr
+		}
+		// nothing before the candidate, or before the text the automaton
+		// still remembers, can be part of a match
+		safe := sc.pos - int(m.depth[sc.state])
+		if sc.cand.Pattern >= 0 {
+			safe = min(safe, sc.cand.Start)
+		}
+		if safe > base {
+			write(pending[:safe-base])
+			pending, base = pending[safe-base:], safe
+			pending = append([]byte(nil), pending...) // let the written bytes go
+		}
+		if werr != nil {
+			return werr
+		}
+		if rerr == io.EOF {
+			break
+		}
+		if rerr != nil {
+			return rerr
+		}
+	}
+	sc.close()
+	write(pending)
+	return werr
+}
+
+func (m *Matcher) checkRepl(repl []string) {
+	if len(repl) != m.patterns {
+		panic(fmt.Sprintf("ahocorasick: %d replacements for %d patterns", len(repl), m.patterns))
+	}
+}
@@ -0,0 +1,324 @@
+// Package casing converts identifiers between naming conventions:
+//
+//	camelCase       userID, httpServer
+//	PascalCase      UserID, HTTPServer
+//	snake_case      user_id, http_server
+//	SCREAMING_SNAKE USER_ID, HTTP_SERVER
+//	kebab-case      user-id, http-server
+//	dot.case        user.id, http.server
+//	Title Case      User ID, HTTP Server
+//
+// every conversion first splits the input into words (Words) and then joins
+// them in the new style, so any style converts to any other. a word boundary
+// is a separator (anything not a letter, digit or mark), a lower case letter
+// or digit followed by an upper case one, or the last capital of a run
+// followed by a lower case letter: "HTTPServer" is HTTP + Server. digits
+// stay with the word before them: "utf8String" is utf8 + String, "x86_64"
+// is x86 + 64.
+//
+// acronyms are what strings.Title and cases.Title cannot know: "id" should
+// become "ID" in userID, not "Id". a Converter keeps a list of them, spelled
+// the way they should appear, and uses it both ways: splitting
+// "JSONAPIResponse" into JSON + API + Response, and writing http_server as
+// HTTPServer. a word in capitals that is not on the list, like IDE in
+// "IDEConfig", is kept as written too, unless the whole input is in capitals:
+// USER_NAME is UserName. upper and lower case use golang.org/x/text/cases, so
+// non-ASCII letters are mapped correctly: "ΟΔΟΣ_ΤΕΣΤ" in camelCase is
+// "οδοςΤεστ", with a final sigma.
+package casing
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// Converter holds the acronyms and the language used for case mapping.
+type Converter struct {
+	// Acronyms spelled as they should appear: "ID", "HTTP", "OAuth".
+	// in a split they are matched exactly, in a join case-insensitively; an
+	// acronym followed by digits ("HTTP2") or a plural s ("IDs") keeps its
+	// spelling too.
+	Acronyms []string
+	// Language for upper, lower and title case; the zero value is
+	// language.Und, which suits most identifiers. Turkish or Dutch change
+	// how i and ij are mapped.
+	Language language.Tag
+}
+
+// CommonAcronyms is the list golint checks Go names against.
+var CommonAcronyms = []string{
+	"ACL", "API", "ASCII", "CPU", "CSS", "DNS", "EOF", "GUID", "HTML", "HTTP",
+	"HTTPS", "ID", "IP", "JSON", "LHS", "QPS", "RAM", "RHS", "RPC", "SLA",
+	"SMTP", "SQL", "SSH", "TCP", "TLS", "TTL", "UDP", "UI", "UID", "UUID",
+	"URI", "URL", "UTF8", "VM", "XML", "XMPP", "XSRF", "XSS",
+}
+
+// Default uses CommonAcronyms.
+var Default = Converter{Acronyms: CommonAcronyms}
+
+// Words splits s into its words, keeping their case:
+// Words("parseHTTPResponse2XX") is [parse HTTP Response2 XX].
+func (c Converter) Words(s string) []string {
+	rs := []rune(s)
+	acronyms := c.runeAcronyms()
+	var words []string
+	for i := 0; i < len(rs); {
+		if isSep(rs[i]) {
+			i++
+			continue
+		}
+		end := acronymAt(rs, i, acronyms)
+		if end < 0 {
+			end = wordEnd(rs, i)
+		}
+		words = append(words, string(rs[i:end]))
+		i = end
+	}
+	return words
+}
+
+// Camel returns s in camelCase: the first word in lower case, even an
+// acronym ("idToken"), the others capitalized.
+func (c Converter) Camel(s string) string { return c.joinTitled(s, true) }
+
+// Pascal returns s in PascalCase.
+func (c Converter) Pascal(s string) string { return c.joinTitled(s, false) }
+
+// Snake returns s in snake_case.
+func (c Converter) Snake(s string) string { return c.join(s, "_", cases.Lower(c.Language)) }
+
+// ScreamingSnake returns s in SCREAMING_SNAKE_CASE.
+func (c Converter) ScreamingSnake(s string) string { return c.join(s, "_", cases.Upper(c.Language)) }
+
+// Kebab returns s in kebab-case.
+func (c Converter) Kebab(s string) string { return c.join(s, "-", cases.Lower(c.Language)) }
+
+// Dot returns s in dot.case.
+func (c Converter) Dot(s string) string { return c.join(s, ".", cases.Lower(c.Language)) }
+
+// Title returns the words of s capitalized and separated by spaces:
+// "HTTP Server", "User ID".
+func (c Converter) Title(s string) string {
+	words := c.Words(s)
+	acronyms := c.acronymMap()
+	title, lower := cases.Title(c.Language), cases.Lower(c.Language)
+	keepCaps := hasLower(s)
+	for i, w := range words {
+		words[i] = titleWord(w, acronyms, keepCaps, title, lower)
+	}
+	return strings.Join(words, " ")
+}
+
+func (c Converter) join(s, sep string, caser cases.Caser) string {
+	words := c.Words(s)
+	for i, w := range words {
+		words[i] = caser.String(w)
+	}
+	return strings.Join(words, sep)
+}
+
+// joinTitled joins the words with no separator, except an underscore between
+// two digits: x86 + 64 is "X86_64", not "X8664".
+func (c Converter) joinTitled(s string, lowerFirst bool) string {
+	acronyms := c.acronymMap()
+	title, lower := cases.Title(c.Language), cases.Lower(c.Language)
+	keepCaps := hasLower(s)
+	var b strings.Builder
+	prev := ""
+	for i, w := range c.Words(s) {
+		if i == 0 && lowerFirst {
+			w = lower.String(w)
+		} else {
+			w = titleWord(w, acronyms, keepCaps, title, lower)
+		}
+		if endsWithDigit(prev) && startsWithDigit(w) {
+			b.WriteByte('_')
+		}
+		b.WriteString(w)
+		prev = w
+	}
+	return b.String()
+}
+
+// titleWord capitalizes w, or spells it as the acronym it is. with keepCaps,
+// a word of two or more capitals stays as it is: IDE, XX. a word that starts
+// with a digit has nothing to capitalize.
+func titleWord(w string, acronyms map[string]string, keepCaps bool, title, lower cases.Caser) string {
+	key := strings.ToLower(w)
+	if a, ok := acronyms[key]; ok {
+		return a
+	}
+	if base := strings.TrimRightFunc(key, unicode.IsDigit); base != key {
+		if a, ok := acronyms[base]; ok {
+			return a + key[len(base):] // HTTP2
+		}
+	}
+	if base, ok := strings.CutSuffix(key, "s"); ok {
+		if a, ok := acronyms[base]; ok {
+			return a + "s" // IDs
+		}
+	}
+	if keepCaps && allCaps(w) {
+		return w
+	}
+	if startsWithDigit(w) {
+		return lower.String(w) // 2fa, not 2Fa
+	}
+	return title.String(w)
+}
+
+// allCaps reports whether w has two or more letters, all upper case.
+func allCaps(w string) bool {
+	n := 0
+	for _, r := range w {
+		if isLower(r) || unicode.IsTitle(r) {
+			return false
+		}
+		if unicode.IsUpper(r) {
+			n++
+		}
+	}
+	return n >= 2
+}
+
+// hasLower reports whether s has a lower case letter, so that its capitals
+// were chosen and are not just the style.
+func hasLower(s string) bool {
+	return strings.ContainsFunc(s, func(r rune) bool { return unicode.IsLower(r) })
+}
+
+// acronymMap maps the lower case form of each acronym to its spelling.
+func (c Converter) acronymMap() map[string]string {
+	m := make(map[string]string, len(c.Acronyms))
+	for _, a := range c.Acronyms {
+		m[strings.ToLower(a)] = a
+	}
+	return m
+}
+
+// runeAcronyms returns the acronyms longest first, so HTTPS is tried
+// before HTTP.
+func (c Converter) runeAcronyms() [][]rune {
+	acronyms := make([][]rune, 0, len(c.Acronyms))
+	for _, a := range c.Acronyms {
+		if a != "" {
+			acronyms = append(acronyms, []rune(a))
+		}
+	}
+	slices.SortStableFunc(acronyms, func(a, b []rune) int { return len(b) - len(a) })
+	return acronyms
+}
+
+// acronymAt returns the end of the acronym that starts a word at rs[i], or
+// -1 if none does. an acronym only counts if the next word starts right
+// after it, so ID does not split "IDEConfig" or "Identity".
+func acronymAt(rs []rune, i int, acronyms [][]rune) int {
+	for _, a := range acronyms {
+		if !hasPrefix(rs[i:], a) {
+			continue
+		}
+		end := i + len(a)
+		if endsWord(rs, end, a[len(a)-1], acronyms) {
+			return end
+		}
+		if end < len(rs) && rs[end] == 's' && unicode.IsUpper(a[len(a)-1]) &&
+			(end+1 == len(rs) || isSep(rs[end+1]) || unicode.IsUpper(rs[end+1])) {
+			return end + 1 // IDs
+		}
+	}
+	return -1
+}
+
+// endsWord reports whether a word that ends with last can end before rs[j].
+// digits continue it: HTTP2 is one word, which Pascal writes as HTTP2 again.
+func endsWord(rs []rune, j int, last rune, acronyms [][]rune) bool {
+	switch {
+	case j == len(rs) || isSep(rs[j]):
+		return true
+	case !unicode.IsUpper(rs[j]):
+		return false
+	case !unicode.IsUpper(last): // OAuth|Token
+		return true
+	case j+1 < len(rs) && isLower(rs[j+1]): // JSON|Response
+		return true
+	}
+	return acronymAt(rs, j, acronyms) >= 0 // JSON|API|Response
+}
+
+// wordEnd returns the end of the word starting at rs[i], by case and
+// digits alone.
+func wordEnd(rs []rune, i int) int {
+	prev := rs[i]
+	for j := i + 1; j < len(rs); j++ {
+		r := rs[j]
+		switch {
+		case isSep(r):
+			return j
+		case unicode.Is(unicode.M, r):
+			continue // a combining mark belongs to the letter before it
+		case unicode.IsUpper(r) && (isLower(prev) || unicode.IsDigit(prev)):
+			return j // user|ID, utf8|String
+		case unicode.IsUpper(r) && unicode.IsUpper(prev) && j+1 < len(rs) && isLower(rs[j+1]):
+			return j // HTTP|Server
+		}
+		prev = r
+	}
+	return len(rs)
+}
+
+// isSep reports whether r separates words: anything but letters, digits and
+// marks.
+func isSep(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.Is(unicode.M, r)
+}
+
+// isLower reports whether r is a letter that is not upper or title case;
+// letters without case, like 日, count as lower case.
+func isLower(r rune) bool {
+	return unicode.IsLetter(r) && !unicode.IsUpper(r) && !unicode.IsTitle(r)
+}
+
+func hasPrefix(rs, prefix []rune) bool {
+	return len(rs) >= len(prefix) && slices.Equal(rs[:len(prefix)], prefix)
+}
+
+func startsWithDigit(s string) bool {
+	for _, r := range s {
+		return unicode.IsDigit(r)
+	}
+	return false
+}
+
+func endsWithDigit(s string) bool {
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return unicode.IsDigit(r)
+}
+
+// Words is Default.Words.
+func Words(s string) []string { return Default.Words(s) }
+
+// Camel is Default.Camel.
+func Camel(s string) string { return Default.Camel(s) }
+
+// Pascal is Default.Pascal.
+func Pascal(s string) string { return Default.Pascal(s) }
+
+// Snake is Default.Snake.
+func Snake(s string) string { return Default.Snake(s) }
+
+// ScreamingSnake is Default.ScreamingSnake.
+func ScreamingSnake(s string) string { return Default.ScreamingSnake(s) }
+
+// Kebab is Default.Kebab.
+func Kebab(s string) string { return Default.Kebab(s) }
+
+// Dot is Default.Dot.
+func Dot(s string) string { return Default.Dot(s) }
+
+// Title is Default.Title.
+func Title(s string) string { return Default.Title(s) }
@@ -0,0 +1,76 @@
+package casing
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestWords(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"parseHTTPResponse2XX", []string{"parse", "HTTP", "Response2", "XX"}},
+		{"HTTPServer", []string{"HTTP", "Server"}},
+		{"JSONAPIResponse", []string{"JSON", "API", "Response"}},
+		{"IDEConfig", []string{"IDE", "Config"}},
+		{"userIDs", []string{"user", "IDs"}},
+		{"utf8String", []string{"utf8", "String"}},
+		{"x86_64", []string{"x86", "64"}},
+		{"  kebab--case ", []string{"kebab", "case"}},
+	}
+	for _, tt := range tests {
+		if got := Words(tt.in); !slices.Equal(got, tt.want) {
+			t.Errorf("Words(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		in                   string
+		camel, pascal, snake string
+	}{
+		{"user_id", "userID", "UserID", "user_id"},
+		{"http_server", "httpServer", "HTTPServer", "http_server"},
+		{"HTTPServer", "httpServer", "HTTPServer", "http_server"},
+		{"parseHTTPResponse2XX", "parseHTTPResponse2XX", "ParseHTTPResponse2XX", "parse_http_response2_xx"},
+		{"IDEConfig", "ideConfig", "IDEConfig", "ide_config"},
+		{"loadIDEConfig", "loadIDEConfig", "LoadIDEConfig", "load_ide_config"},
+		{"USER_NAME", "userName", "UserName", "user_name"},
+		{"ID_TOKEN", "idToken", "IDToken", "id_token"},
+		{"x86_64", "x86_64", "X86_64", "x86_64"},
+		{"enable_2fa", "enable2fa", "Enable2fa", "enable_2fa"},
+		{"ΟΔΟΣ_ΤΕΣΤ", "οδοςΤεστ", "ΟδοςΤεστ", "οδος_τεστ"},
+	}
+	for _, tt := range tests {
+		if got := Camel(tt.in); got != tt.camel {
+			t.Errorf("Camel(%q) = %q, want %q", tt.in, got, tt.camel)
+		}
+		if got := Pascal(tt.in); got != tt.pascal {
+			t.Errorf("Pascal(%q) = %q, want %q", tt.in, got, tt.pascal)
+		}
+		if got := Snake(tt.in); got != tt.snake {
+			t.Errorf("Snake(%q) = %q, want %q", tt.in, got, tt.snake)
+		}
+	}
+	if got := Title("loadIDEConfig"); got != "Load IDE Config" {
+		t.Errorf("Title(%q) = %q, want %q", "loadIDEConfig", got, "Load IDE Config")
+	}
+	if got := ScreamingSnake("HTTPServer"); got != "HTTP_SERVER" {
+		t.Errorf("ScreamingSnake(%q) = %q, want %q", "HTTPServer", got, "HTTP_SERVER")
+	}
+}
+
+// TestPascalRoundTrip checks that Pascal leaves Go names that are already
+// PascalCase as they are.
+func TestPascalRoundTrip(t *testing.T) {
+	for _, s := range []string{
+		"ParseHTTPResponse2XX", "IDEConfig", "HTTPServer", "UserID", "JSONAPIResponse",
+		"OAuthToken", "X86_64", "ServeHTTP", "NewCPUProfile", "GOOSValue",
+	} {
+		if got := Pascal(s); got != s {
+			t.Errorf("Pascal(%q) = %q", s, got)
+		}
+	}
+}
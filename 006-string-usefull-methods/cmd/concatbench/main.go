@@ -0,0 +1,76 @@
+// concatbench turns the output of the concat package's benchmarks, which
+// time the ways to build a string from many parts, from += to
+// strings.Builder with Grow, into Markdown: ns/op, B/op and allocs/op for
+// each, and the exponent k of time ∝ parts^k fitted over the sizes. the
+// benchmarks take about a minute, most of it in += and fmt.Sprintf at
+// 100000 parts.
+//
+//	go test -run '^$' -bench Concat ./concat | go run ./cmd/concatbench
+//	go test -run '^$' -bench 'Concat(Builder|PlusEquals)' ./concat > out.txt
+//	go run ./cmd/concatbench out.txt
+//
+// it reads the files given, or standard input. the numbers in the lesson
+// (Q78) come from it.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+
+	"string-methods/concat"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: concatbench [file ...]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	var out bytes.Buffer
+	if flag.NArg() == 0 {
+		if _, err := io.Copy(&out, os.Stdin); err != nil {
+			fmt.Fprintln(os.Stderr, "concatbench:", err)
+			os.Exit(1)
+		}
+	}
+	for _, name := range flag.Args() {
+		b, err := os.ReadFile(name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "concatbench:", err)
+			os.Exit(1)
+		}
+		out.Write(b)
+	}
+
+	results, err := concat.Parse(bytes.NewReader(out.Bytes()))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "concatbench:", err)
+		os.Exit(1)
+	}
+	if len(results) == 0 {
+		fmt.Fprintln(os.Stderr, "concatbench: no BenchmarkConcat results in the input")
+		os.Exit(1)
+	}
+
+	fmt.Printf("building a string from n one-byte parts; %s, %s/%s, %s\n\n",
+		runtime.Version(), field(out.String(), "goos", runtime.GOOS), field(out.String(), "goarch", runtime.GOARCH),
+		field(out.String(), "cpu", "unknown CPU"))
+	concat.Markdown(os.Stdout, results)
+}
+
+// field returns the value of a "key: value" line that go test prints
+// before the benchmarks, or def if there is none.
+func field(out, key, def string) string {
+	for line := range strings.Lines(out) {
+		if v, ok := strings.CutPrefix(line, key+":"); ok {
+			return strings.TrimSpace(v)
+		}
+	}
+	return def
+}
@@ -0,0 +1,238 @@
+// inspect shows how a string is stored: the bytes of each UTF-8 sequence,
+// the rune they decode to, its code point, general category and name.
+//
+//	go run ./cmd/inspect 世界 héllo 👨‍👩‍👧
+//	go run ./cmd/inspect -q 'caf\xc3\xa9 \xff\xe4\xb8'   // Go escapes, for invalid UTF-8
+//	echo -n 'text' | go run ./cmd/inspect                // read stdin
+//
+// a byte that is not valid UTF-8 decodes to U+FFFD, the replacement
+// character, one byte at a time, exactly as for range and
+// utf8.DecodeRuneInString do; inspect marks those rows and says why the byte
+// is invalid. a real U+FFFD in the input is a valid 3 byte sequence and is
+// not marked.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/runenames"
+
+	"string-methods/display"
+	"string-methods/grapheme"
+)
+
+func main() {
+	quoted := flag.Bool("q", false, `read arguments as Go string contents with escapes: \xff, \u00e9, \n`)
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: inspect [-q] [string]...")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	inputs := flag.Args()
+	if len(inputs) == 0 {
+		b, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "inspect:", err)
+			os.Exit(1)
+		}
+		inputs = []string{string(b)}
+	} else if *quoted {
+		for i, s := range inputs {
+			u, err := strconv.Unquote(`"` + strings.ReplaceAll(s, `"`, `\"`) + `"`)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "inspect: %q: bad escape\n", s)
+				os.Exit(1)
+			}
+			inputs[i] = u
+		}
+	}
+
+	color := isTerminal(os.Stdout)
+	for i, s := range inputs {
+		if i > 0 {
+			fmt.Println()
+		}
+		inspect(os.Stdout, s, color)
+	}
+}
+
+// inspect writes the table for s, with invalid rows in red if color is set.
+func inspect(w io.Writer, s string, color bool) {
+	var t display.Table
+	t.Align = []display.Alignment{display.Right}
+	t.Row("offset", "bytes", "rune", "code point", "category", "name")
+	var invalid []int // table rows
+	runes := 0
+	for i, r := range s {
+		size := utf8.RuneLen(r)
+		bad := false
+		if r == utf8.RuneError {
+			if _, n := utf8.DecodeRuneInString(s[i:]); n == 1 {
+				size, bad = 1, true
+			}
+		}
+		runes++
+		if bad {
+			invalid = append(invalid, runes)
+			t.Row(strconv.Itoa(i), hexBytes(s[i:i+size]), "�", "U+FFFD", "", "invalid UTF-8: "+whyInvalid(s[i:]))
+			continue
+		}
+		t.Row(strconv.Itoa(i), hexBytes(s[i:i+size]), show(r), fmt.Sprintf("U+%04X", r), category(r), name(r))
+	}
+
+	lines := strings.SplitAfter(t.String(), "\n")
+	for row, line := range lines {
+		if color && slices.Contains(invalid, row) {
+			line = "\x1b[31m" + strings.TrimSuffix(line, "\n") + "\x1b[0m\n"
+		}
+		io.WriteString(w, line)
+	}
+	fmt.Fprintf(w, "%d bytes, %d runes (%d invalid), %d graphemes, %d columns\n",
+		len(s), runes, len(invalid), grapheme.Count(s), display.Width(s))
+}
+
+func hexBytes(s string) string {
+	parts := make([]string, len(s))
+	for i := range len(s) {
+		parts[i] = fmt.Sprintf("%02x", s[i])
+	}
+	return strings.Join(parts, " ")
+}
+
+// show returns r in a form that can sit in a table cell: controls, spaces
+// and invisible runes quoted, combining marks on a dotted circle.
+func show(r rune) string {
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Me):
+		return "◌" + string(r)
+	case !unicode.IsGraphic(r) || unicode.Is(unicode.Cf, r) || unicode.IsSpace(r):
+		return strconv.QuoteRuneToASCII(r) // '\n', ' ', '\u200d'
+	}
+	return string(r)
+}
+
+// twoLetter holds the names of the general categories, "Lu", "Mn" and so
+// on, without the groups "L", "M" and "LC" (Lu, Ll and Lt).
+var twoLetter = func() []string {
+	var cs []string
+	for c := range unicode.Categories {
+		if len(c) == 2 && c != "LC" {
+			cs = append(cs, c)
+		}
+	}
+	slices.Sort(cs)
+	return cs
+}()
+
+// category returns the general category of r, or "Cn" for unassigned.
+func category(r rune) string {
+	for _, c := range twoLetter {
+		if unicode.Is(unicode.Categories[c], r) {
+			return c
+		}
+	}
+	return "Cn"
+}
+
+// name returns the Unicode name of r. runenames knows the names of an
+// older version of Unicode than package unicode, so a new character has
+// none. controls have no name and are described by their alias, and names
+// that Unicode derives from the code point are filled in.
+func name(r rune) string {
+	n := runenames.Name(r)
+	switch {
+	case n == "" || n == "<control>":
+		if alias, ok := controls[r]; ok {
+			return "<control> " + alias
+		}
+	case strings.HasPrefix(n, "<CJK Ideograph"):
+		return fmt.Sprintf("CJK UNIFIED IDEOGRAPH-%04X", r)
+	case strings.HasPrefix(n, "<Tangut Ideograph"):
+		return fmt.Sprintf("TANGUT IDEOGRAPH-%04X", r)
+	case n == "<Hangul Syllable>":
+		return hangulName(r)
+	}
+	if n == "" {
+		return "(no name in Unicode " + runenames.UnicodeVersion + ")"
+	}
+	return n
+}
+
+// hangulName builds the name of a precomposed Hangul syllable from its
+// jamo, as section 3.12 of the Unicode standard describes: 한 is
+// HANGUL SYLLABLE HAN.
+func hangulName(r rune) string {
+	lead := []string{"G", "GG", "N", "D", "DD", "R", "M", "B", "BB", "S", "SS", "", "J", "JJ", "C", "K", "T", "P", "H"}
+	vowel := []string{"A", "AE", "YA", "YAE", "EO", "E", "YEO", "YE", "O", "WA", "WAE", "OE", "YO", "U", "WEO", "WE", "WI", "YU", "EU", "YI", "I"}
+	trail := []string{"", "G", "GG", "GS", "N", "NJ", "NH", "D", "L", "LG", "LM", "LB", "LS", "LT", "LP", "LH", "M", "B", "BS", "S", "SS", "NG", "J", "C", "K", "T", "P", "H"}
+	i := int(r - 0xAC00)
+	return "HANGUL SYLLABLE " + lead[i/(21*28)] + vowel[i%(21*28)/28] + trail[i%28]
+}
+
+var controls = map[rune]string{
+	0x00: "NULL", 0x07: "BELL", 0x08: "BACKSPACE", 0x09: "CHARACTER TABULATION",
+	0x0A: "LINE FEED", 0x0B: "LINE TABULATION", 0x0C: "FORM FEED",
+	0x0D: "CARRIAGE RETURN", 0x1B: "ESCAPE", 0x7F: "DELETE", 0x85: "NEXT LINE",
+}
+
+// whyInvalid explains why the first byte of s does not start a valid UTF-8
+// sequence.
+func whyInvalid(s string) string {
+	b := s[0]
+	switch {
+	case b < 0xC0:
+		return "continuation byte without a lead byte"
+	case b == 0xC0 || b == 0xC1:
+		return "lead byte of an overlong encoding, never valid"
+	case b >= 0xF5:
+		return "byte never used in UTF-8"
+	}
+	need := 2
+	if b >= 0xF0 {
+		need = 4
+	} else if b >= 0xE0 {
+		need = 3
+	}
+	lo, hi := byte(0x80), byte(0xBF) // the range of the second byte
+	switch b {
+	case 0xE0:
+		lo = 0xA0
+	case 0xED:
+		hi = 0x9F
+	case 0xF0:
+		lo = 0x90
+	case 0xF4:
+		hi = 0x8F
+	}
+	for k := 1; k < need; k++ {
+		if k >= len(s) {
+			return fmt.Sprintf("sequence of %d bytes cut short at the end", need)
+		}
+		c := s[k]
+		switch {
+		case c < 0x80 || c > 0xBF:
+			return fmt.Sprintf("sequence of %d bytes cut short by byte %d", need, k+1)
+		case k == 1 && c < lo:
+			return "overlong encoding"
+		case k == 1 && c > hi && b == 0xED:
+			return "surrogate half (U+D800-U+DFFF)"
+		case k == 1 && c > hi:
+			return "beyond U+10FFFF"
+		}
+	}
+	return "invalid sequence"
+}
+
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
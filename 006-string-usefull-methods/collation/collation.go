@@ -0,0 +1,135 @@
+// Package collation sorts and compares strings in the order a language's
+// dictionary uses, not by bytes.
+//
+// the lesson's "hello" < "world" compares UTF-8 bytes, which puts every
+// upper case letter before every lower case one and accented letters after
+// z:
+//
+//	byte order       Apfel  Zebra  apfel  file10  file2  Äpfel  ärger
+//	German           apfel  Apfel  Äpfel  ärger  file10  file2  Zebra
+//	German, Numeric  apfel  Apfel  Äpfel  ärger  file2  file10  Zebra
+//	Swedish          apfel  Apfel  file10  file2  Zebra  Äpfel  ärger
+//
+// (in Swedish ä is a letter of its own, after z.) a Collator wraps
+// golang.org/x/text/collate with a strength, which decides which differences
+// count, and numeric ordering of digits.
+package collation
+
+import (
+	"bytes"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// Strength is how fine the differences are that make two strings unequal.
+type Strength int
+
+const (
+	Tertiary  Strength = iota // letters, accents and case: a < A < á; the default
+	Primary                   // base letters only: a = A = á = Á
+	Secondary                 // letters and accents: a = A < á = Á
+	Identical                 // Tertiary, and then the bytes: no two different strings are equal
+)
+
+func (s Strength) String() string {
+	switch s {
+	case Primary:
+		return "Primary"
+	case Secondary:
+		return "Secondary"
+	case Tertiary:
+		return "Tertiary"
+	case Identical:
+		return "Identical"
+	}
+	return "Strength(" + strconv.Itoa(int(s)) + ")"
+}
+
+// Options configures a Collator. the zero value compares at Tertiary
+// strength with digits as text.
+type Options struct {
+	Strength Strength
+	// Numeric orders runs of digits by their value: "file2" < "file10".
+	Numeric bool
+}
+
+// Collator compares strings for one language. it keeps buffers between
+// calls, so it is not safe for concurrent use: make one per goroutine.
+type Collator struct {
+	c        *collate.Collator
+	strength Strength
+	buf      collate.Buffer
+}
+
+// New returns a Collator for the language tag, such as language.German or
+// language.MustParse("sv").
+func New(tag language.Tag, opts Options) *Collator {
+	var o []collate.Option
+	switch opts.Strength {
+	case Primary:
+		o = append(o, collate.Loose) // ignore case, accents and width
+	case Secondary:
+		o = append(o, collate.IgnoreCase, collate.IgnoreWidth)
+	}
+	if opts.Numeric {
+		o = append(o, collate.Numeric)
+	}
+	return &Collator{c: collate.New(tag, o...), strength: opts.Strength}
+}
+
+// Compare returns -1, 0 or 1 as a sorts before, the same as, or after b.
+func (c *Collator) Compare(a, b string) int {
+	if r := c.c.CompareString(a, b); r != 0 || c.strength != Identical {
+		return r
+	}
+	return strings.Compare(a, b)
+}
+
+// Equal reports whether a and b are equal at the Collator's strength.
+func (c *Collator) Equal(a, b string) bool { return c.Compare(a, b) == 0 }
+
+// Key returns the sort key of s: comparing keys with bytes.Compare gives the
+// same order as Compare. keys are for sorting or indexing many strings
+// without comparing them again and again; at Identical strength, equal keys
+// still need a byte comparison of the strings.
+func (c *Collator) Key(s string) []byte {
+	defer c.buf.Reset()
+	return bytes.Clone(c.c.KeyFromString(&c.buf, s))
+}
+
+// Sort sorts ss in place. the sort is stable: strings that are equal at
+// the Collator's strength, like "a" and "A" at Primary, keep their order.
+func (c *Collator) Sort(ss []string) {
+	SortFunc(c, ss, func(s string) string { return s })
+}
+
+// SortFunc sorts xs in place by the string key(x), stably, computing each
+// key once:
+//
+//	collation.SortFunc(c, users, func(u User) string { return u.Name })
+func SortFunc[T any](c *Collator, xs []T, key func(T) string) {
+	type item struct {
+		k []byte
+		s string
+		x T
+	}
+	items := make([]item, len(xs))
+	for i, x := range xs {
+		s := key(x)
+		items[i] = item{c.c.KeyFromString(&c.buf, s), s, x}
+	}
+	defer c.buf.Reset()
+	slices.SortStableFunc(items, func(a, b item) int {
+		if r := bytes.Compare(a.k, b.k); r != 0 || c.strength != Identical {
+			return r
+		}
+		return strings.Compare(a.s, b.s)
+	})
+	for i := range items {
+		xs[i] = items[i].x
+	}
+}
@@ -0,0 +1,220 @@
+// Package concat measures the ways to build a string out of many parts,
+// for the lesson's claims about them (Q28, Q37, Q65, Q78): that += in a
+// loop is O(n²), and that strings.Builder is much faster.
+//
+// each Method builds the same string from the same parts, and the package
+// tests have a BenchmarkConcat function for each. Parse reads what go test
+// prints for them, Exponent fits how the time grows with the number of
+// parts, and Markdown writes the results as tables. cmd/concatbench does the
+// last three:
+//
+//	go test -run '^$' -bench Concat ./concat | go run ./cmd/concatbench > results.md
+package concat
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Method is one way to join parts into a string. its benchmark is
+// BenchmarkConcat followed by Bench.
+type Method struct {
+	Name  string
+	Bench string
+	Build func(parts []string) string
+}
+
+// Methods are the ways the lesson talks about, in the order of the report.
+var Methods = []Method{
+	{"+=", "PlusEquals", func(parts []string) string {
+		s := ""
+		for _, p := range parts {
+			s += p
+		}
+		return s
+	}},
+	{"fmt.Sprintf", "Sprintf", func(parts []string) string {
+		s := ""
+		for _, p := range parts {
+			s = fmt.Sprintf("%s%s", s, p)
+		}
+		return s
+	}},
+	{"strings.Join", "Join", func(parts []string) string {
+		return strings.Join(parts, "")
+	}},
+	{"strings.Builder", "Builder", func(parts []string) string {
+		var b strings.Builder
+		for _, p := range parts {
+			b.WriteString(p)
+		}
+		return b.String()
+	}},
+	{"strings.Builder, Grow", "BuilderGrow", func(parts []string) string {
+		n := 0
+		for _, p := range parts {
+			n += len(p)
+		}
+		var b strings.Builder
+		b.Grow(n)
+		for _, p := range parts {
+			b.WriteString(p)
+		}
+		return b.String()
+	}},
+	{"bytes.Buffer", "BytesBuffer", func(parts []string) string {
+		var b bytes.Buffer
+		for _, p := range parts {
+			b.WriteString(p)
+		}
+		return b.String()
+	}},
+	{"[]byte append", "AppendBytes", func(parts []string) string {
+		var b []byte
+		for _, p := range parts {
+			b = append(b, p...)
+		}
+		return string(b)
+	}},
+}
+
+// Parts returns n parts of one byte each, "x", as in Q37's loop.
+func Parts(n int) []string {
+	parts := make([]string, n)
+	for i := range parts {
+		parts[i] = "x"
+	}
+	return parts
+}
+
+// Result is the time and memory one Method takes to build a string of N
+// parts.
+type Result struct {
+	Method      string
+	N           int
+	NsPerOp     float64
+	BytesPerOp  int64
+	AllocsPerOp int64
+}
+
+// benchLine matches the result of one benchmark in the output of go test,
+// such as
+//
+//	BenchmarkConcatBuilder/n=1000-8   1000000   1234 ns/op   3320 B/op   11 allocs/op
+//
+// the -8 is GOMAXPROCS, printed when it is not 1.
+var benchLine = regexp.MustCompile(`^BenchmarkConcat(\w+)/n=(\d+)(?:-\d+)?\s+\d+\s+([\d.]+) ns/op(?:\s+(\d+) B/op\s+(\d+) allocs/op)?`)
+
+// Parse reads the output of go test -bench for the BenchmarkConcat
+// functions and returns their results in the order they ran. it ignores
+// every other line.
+func Parse(r io.Reader) ([]Result, error) {
+	names := make(map[string]string)
+	for _, m := range Methods {
+		names[m.Bench] = m.Name
+	}
+	var rs []Result
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		f := benchLine.FindStringSubmatch(sc.Text())
+		if f == nil {
+			continue
+		}
+		name, ok := names[f[1]]
+		if !ok {
+			return nil, fmt.Errorf("concat: no method for BenchmarkConcat%s", f[1])
+		}
+		res := Result{Method: name}
+		res.N, _ = strconv.Atoi(f[2])
+		res.NsPerOp, _ = strconv.ParseFloat(f[3], 64)
+		if f[4] != "" {
+			res.BytesPerOp, _ = strconv.ParseInt(f[4], 10, 64)
+			res.AllocsPerOp, _ = strconv.ParseInt(f[5], 10, 64)
+		}
+		rs = append(rs, res)
+	}
+	return rs, sc.Err()
+}
+
+// Exponent fits time = c·N^k to the results by least squares on their
+// logarithms and returns k: about 1 for a method that is O(n), 2 for O(n²).
+// it is NaN for fewer than two sizes.
+func Exponent(rs []Result) float64 {
+	if len(rs) < 2 {
+		return math.NaN()
+	}
+	var sx, sy, sxx, sxy float64
+	for _, r := range rs {
+		x, y := math.Log(float64(r.N)), math.Log(r.NsPerOp)
+		sx, sy, sxx, sxy = sx+x, sy+y, sxx+x*x, sxy+x*y
+	}
+	n := float64(len(rs))
+	return (n*sxy - sx*sy) / (n*sxx - sx*sx)
+}
+
+// Markdown writes the results as a table with a row per method and size,
+// followed by a table of the fitted exponents and how far each method is
+// from the fastest at the largest size. the exponent is fitted twice: over
+// all sizes, and over the two largest, where the fixed cost of a call no
+// longer hides how the time grows. with no results it writes nothing.
+func Markdown(w io.Writer, rs []Result) {
+	if len(rs) == 0 {
+		return
+	}
+	var sizes []int
+	byMethod := map[string][]Result{}
+	var names []string
+	for _, r := range rs {
+		if len(byMethod[r.Method]) == 0 {
+			names = append(names, r.Method)
+		}
+		byMethod[r.Method] = append(byMethod[r.Method], r)
+		if !slices.Contains(sizes, r.N) {
+			sizes = append(sizes, r.N)
+		}
+	}
+
+	fmt.Fprintln(w, "| method | parts | ns/op | B/op | allocs/op |")
+	fmt.Fprintln(w, "|---|--:|--:|--:|--:|")
+	for _, name := range names {
+		for _, r := range byMethod[name] {
+			fmt.Fprintf(w, "| %s | %d | %s | %d | %d |\n", r.Method, r.N, duration(r.NsPerOp), r.BytesPerOp, r.AllocsPerOp)
+		}
+	}
+
+	largest := sizes[len(sizes)-1]
+	fastest := math.Inf(1)
+	for _, r := range rs {
+		if r.N == largest {
+			fastest = min(fastest, r.NsPerOp)
+		}
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "| method | k, all sizes | k, two largest | at %d parts vs fastest |\n", largest)
+	fmt.Fprintln(w, "|---|--:|--:|--:|")
+	for _, name := range names {
+		ms := byMethod[name]
+		last := ms[len(ms)-1]
+		fmt.Fprintf(w, "| %s | %.2f | %.2f | %.1fx |\n", name, Exponent(ms), Exponent(ms[max(0, len(ms)-2):]), last.NsPerOp/fastest)
+	}
+}
+
+// duration formats nanoseconds with a unit that keeps 3 or so digits.
+func duration(ns float64) string {
+	switch {
+	case ns >= 1e9:
+		return fmt.Sprintf("%.2f s", ns/1e9)
+	case ns >= 1e6:
+		return fmt.Sprintf("%.2f ms", ns/1e6)
+	case ns >= 1e3:
+		return fmt.Sprintf("%.2f µs", ns/1e3)
+	}
+	return fmt.Sprintf("%.0f ns", ns)
+}
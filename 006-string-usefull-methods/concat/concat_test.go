@@ -0,0 +1,132 @@
+package concat
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// sizes are the numbers of parts each method is timed with. += and
+// fmt.Sprintf take about a second per string at 100000.
+var sizes = []int{10, 100, 1000, 10000, 100000}
+
+var sink string
+
+// benchmarkMethod times the Method with the given Bench building a string
+// of each number of parts.
+func benchmarkMethod(b *testing.B, bench string) {
+	i := slices.IndexFunc(Methods, func(m Method) bool { return m.Bench == bench })
+	if i < 0 {
+		b.Fatalf("no method %q", bench)
+	}
+	m := Methods[i]
+	for _, n := range sizes {
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			parts := Parts(n)
+			b.ReportAllocs()
+			for b.Loop() {
+				sink = m.Build(parts)
+			}
+		})
+	}
+}
+
+func BenchmarkConcatPlusEquals(b *testing.B)  { benchmarkMethod(b, "PlusEquals") }
+func BenchmarkConcatSprintf(b *testing.B)     { benchmarkMethod(b, "Sprintf") }
+func BenchmarkConcatJoin(b *testing.B)        { benchmarkMethod(b, "Join") }
+func BenchmarkConcatBuilder(b *testing.B)     { benchmarkMethod(b, "Builder") }
+func BenchmarkConcatBuilderGrow(b *testing.B) { benchmarkMethod(b, "BuilderGrow") }
+func BenchmarkConcatBytesBuffer(b *testing.B) { benchmarkMethod(b, "BytesBuffer") }
+func BenchmarkConcatAppendBytes(b *testing.B) { benchmarkMethod(b, "AppendBytes") }
+
+func TestMethods(t *testing.T) {
+	parts := []string{"a", "", "bc", "é", "世界", "x"}
+	for _, m := range Methods {
+		if got, want := m.Build(parts), strings.Join(parts, ""); got != want {
+			t.Errorf("%s built %q, want %q", m.Name, got, want)
+		}
+		if got := m.Build(Parts(1000)); got != strings.Repeat("x", 1000) {
+			t.Errorf("%s built %d bytes from Parts(1000)", m.Name, len(got))
+		}
+		if got := m.Build(nil); got != "" {
+			t.Errorf("%s built %q from no parts", m.Name, got)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	out := `goos: linux
+goarch: amd64
+pkg: string-methods/concat
+cpu: Intel(R) Xeon(R) Processor
+BenchmarkConcatPlusEquals/n=10         	 4322174	       276.1 ns/op	      56 B/op	       9 allocs/op
+BenchmarkConcatPlusEquals/n=100-8      	  151622	      7931 ns/op	    5584 B/op	      99 allocs/op
+BenchmarkConcatBuilderGrow/n=10-8      	24041872	        49.83 ns/op	      16 B/op	       1 allocs/op
+BenchmarkConcatJoin/n=10               	 9512836	       126 ns/op
+BenchmarkOther/n=10                    	 9512836	       126 ns/op
+PASS
+ok  	string-methods/concat	12.345s
+`
+	got, err := Parse(strings.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Result{
+		{"+=", 10, 276.1, 56, 9},
+		{"+=", 100, 7931, 5584, 99},
+		{"strings.Builder, Grow", 10, 49.83, 16, 1},
+		{"strings.Join", 10, 126, 0, 0},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Parse = %v, want %v", got, want)
+	}
+
+	if _, err := Parse(strings.NewReader("BenchmarkConcatNope/n=10 1 2 ns/op\n")); err == nil {
+		t.Error("Parse of an unknown method: no error")
+	}
+}
+
+func TestExponent(t *testing.T) {
+	var linear, quadratic []Result
+	for _, n := range sizes {
+		linear = append(linear, Result{N: n, NsPerOp: 3 * float64(n)})
+		quadratic = append(quadratic, Result{N: n, NsPerOp: 0.5 * float64(n) * float64(n)})
+	}
+	if k := Exponent(linear); math.Abs(k-1) > 1e-9 {
+		t.Errorf("Exponent of linear times = %v, want 1", k)
+	}
+	if k := Exponent(quadratic); math.Abs(k-2) > 1e-9 {
+		t.Errorf("Exponent of quadratic times = %v, want 2", k)
+	}
+	if k := Exponent(linear[:1]); !math.IsNaN(k) {
+		t.Errorf("Exponent of one size = %v, want NaN", k)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	var b strings.Builder
+	Markdown(&b, nil)
+	if b.Len() != 0 {
+		t.Errorf("Markdown of no results wrote %q", b.String())
+	}
+	var rs []Result
+	for _, n := range []int{10, 100, 1000} {
+		rs = append(rs,
+			Result{Method: "Join", N: n, NsPerOp: 2 * float64(n), BytesPerOp: int64(n), AllocsPerOp: 1},
+			Result{Method: "+=", N: n, NsPerOp: float64(n) * float64(n), BytesPerOp: int64(n * n), AllocsPerOp: int64(n)})
+	}
+	Markdown(&b, rs)
+	for _, want := range []string{
+		"| Join | 1000 | 2.00 µs | 1000 | 1 |\n",
+		"| += | 100 | 10.00 µs | 10000 | 100 |\n",
+		"at 1000 parts vs fastest",
+		"| Join | 1.00 | 1.00 | 1.0x |\n",
+		"| += | 2.00 | 2.00 | 500.0x |\n",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("Markdown wrote\n%s\nwithout %q", b.String(), want)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+// Package display measures and pads strings by the columns they take up in
+// a terminal, not by bytes or runes.
+//
+// the lesson pads with strings.Repeat(" ", n) + s, which lines up only when
+// every character is one column wide. in a terminal that is not true:
+//
+//	"go"    2 bytes, 2 runes, 2 columns
+//	"日本"  6 bytes, 2 runes, 4 columns   East Asian wide
+//	"é"     3 bytes, 2 runes, 1 column    e + combining acute accent
+//	"👍🏽"   8 bytes, 2 runes, 2 columns   emoji + skin tone modifier
+//	"👨‍👩‍👧"  18 bytes, 5 runes, 2 columns  emoji joined with ZWJ
+//	"🇯🇵"   8 bytes, 2 runes, 2 columns   a flag: two regional indicators
+//
+// Width uses the East Asian Width property from golang.org/x/text/width and
+// treats combining marks, format characters like the zero width joiner, and
+// the parts of emoji sequences as zero columns. PadLeft, PadRight and Center
+// pad to a width, Truncate cuts to one, and Table aligns columns (unlike
+// text/tabwriter, which counts runes).
+//
+// terminals disagree on a few characters; the rules here match the common
+// ones (wcwidth and most modern terminal emulators). control characters,
+// including tab and newline, have width 0: expand tabs before measuring.
+package display
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/width"
+)
+
+// Measure holds the one choice terminals make differently: the width of East
+// Asian ambiguous characters like "°", "±", "Ω" and box drawing, which are
+// 1 column in most locales and 2 in CJK ones. the zero value uses 1.
+type Measure struct {
+	AmbiguousWide bool
+}
+
+// Default measures ambiguous characters as 1 column.
+var Default = Measure{}
+
+const (
+	zwj  = '\u200d' // zero width joiner: glues emoji into one picture
+	vs16 = '\ufe0f' // variation selector 16: emoji presentation, 2 columns
+)
+
+// RuneWidth returns the columns r takes on its own: 0, 1 or 2.
+func (m Measure) RuneWidth(r rune) int {
+	switch {
+	case r < 0x20 || 0x7f <= r && r < 0xa0: // C0 and C1 controls
+		return 0
+	case r < 0x7f: // ASCII, the common case
+		return 1
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf),
+		0x1160 <= r && r <= 0x11ff: // Hangul medial vowels and final consonants
+		return 0
+	}
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	case width.EastAsianAmbiguous:
+		if m.AmbiguousWide {
+			return 2
+		}
+	}
+	return 1
+}
+
+// Width returns the columns s takes in a terminal.
+func (m Measure) Width(s string) int {
+	w := 0
+	for _, c := range m.clusters(s) {
+		w += c.width
+	}
+	return w
+}
+
+// cluster is a run of runes the terminal draws as one picture: a base and
+// its combining marks, modifiers and joined emoji.
+type cluster struct {
+	start, end int // byte offsets in s
+	width      int
+}
+
+// clusters splits s into the runs that must not be cut apart, with their
+// widths. it is not full grapheme segmentation, only the part that matters
+// for width: marks, variation selectors, skin tones, ZWJ sequences and
+// flag pairs attach to what came before.
+func (m Measure) clusters(s string) []cluster {
+	var cs []cluster
+	joined := false // the last rune was a ZWJ
+	riOpen := false // the last cluster is a lone regional indicator
+	for i, r := range s {
+		end := i + utf8.RuneLen(r)
+		if r == utf8.RuneError {
+			end = i + 1
+			if _, n := utf8.DecodeRuneInString(s[i:]); n > 1 {
+				end = i + n // a real U+FFFD
+			}
+		}
+		last := len(cs) - 1
+		attach := last >= 0 && (joined ||
+			m.RuneWidth(r) == 0 && r >= 0x20 && !(0x7f <= r && r < 0xa0) ||
+			isModifier(r) ||
+			isRegional(r) && riOpen)
+		joined = r == zwj
+		if attach {
+			c := &cs[last]
+			c.end = end
+			if r == vs16 && c.width == 1 {
+				c.width = 2 // "❤" is 1 column, "❤️" is 2
+			}
+			riOpen = false
+			continue
+		}
+		w := m.RuneWidth(r)
+		if isRegional(r) {
+			w, riOpen = 2, true
+		} else {
+			riOpen = false
+		}
+		cs = append(cs, cluster{i, end, w})
+	}
+	return cs
+}
+
+// isModifier reports whether r is an emoji skin tone modifier, which is wide
+// on its own but zero width after an emoji.
+func isModifier(r rune) bool { return 0x1f3fb <= r && r <= 0x1f3ff }
+
+// isRegional reports whether r is a regional indicator; two of them make a
+// flag.
+func isRegional(r rune) bool { return 0x1f1e6 <= r && r <= 0x1f1ff }
+
+// PadLeft adds spaces before s until it is n columns wide, aligning it to
+// the right. s is returned unchanged if it is already as wide.
+func (m Measure) PadLeft(s string, n int) string {
+	return strings.Repeat(" ", max(0, n-m.Width(s))) + s
+}
+
+// PadRight adds spaces after s until it is n columns wide.
+func (m Measure) PadRight(s string, n int) string {
+	return s + strings.Repeat(" ", max(0, n-m.Width(s)))
+}
+
+// Center pads s on both sides to n columns; an odd space goes to the right.
+func (m Measure) Center(s string, n int) string {
+	pad := max(0, n-m.Width(s))
+	return strings.Repeat(" ", pad/2) + s + strings.Repeat(" ", pad-pad/2)
+}
+
+// Truncate cuts s to at most n columns without splitting a character, an
+// emoji sequence or a wide character in half. when it cuts, it ends the
+// result with tail (like "…"), which counts toward n. when n is too small
+// for even the tail, the result is the tail cut to n columns: "" for n 0.
+func (m Measure) Truncate(s string, n int, tail string) string {
+	n = max(n, 0)
+	if m.Width(s) <= n {
+		return s
+	}
+	tw := m.Width(tail)
+	if tw > n {
+		return m.Truncate(tail, n, "")
+	}
+	n -= tw
+	end, w := 0, 0
+	for _, c := range m.clusters(s) {
+		if w+c.width > n {
+			break
+		}
+		end, w = c.end, w+c.width
+	}
+	return s[:end] + tail
+}
+
+// RuneWidth is Default.RuneWidth.
+func RuneWidth(r rune) int { return Default.RuneWidth(r) }
+
+// Width is Default.Width.
+func Width(s string) int { return Default.Width(s) }
+
+// PadLeft is Default.PadLeft.
+func PadLeft(s string, n int) string { return Default.PadLeft(s, n) }
+
+// PadRight is Default.PadRight.
+func PadRight(s string, n int) string { return Default.PadRight(s, n) }
+
+// Center is Default.Center.
+func Center(s string, n int) string { return Default.Center(s, n) }
+
+// Truncate is Default.Truncate.
+func Truncate(s string, n int, tail string) string { return Default.Truncate(s, n, tail) }
@@ -0,0 +1,86 @@
+package display
+
+import "testing"
+
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		// the examples in the package doc
+		{"go", 2},
+		{"日本", 4},
+		{"é", 1},
+		{"👍🏽", 2},
+		{"👨‍👩‍👧", 2},
+		{"🇯🇵", 2},
+		// VS16 turns a 1-column symbol into a 2-column emoji
+		{"❤", 1},
+		{"❤️", 2},
+		{"🇯🇵🇺🇸", 4},
+		{"🇯", 2}, // a lone regional indicator
+		{"ｇｏ", 4},
+		{"한국어", 6},
+		{"\u1100\u1161", 2}, // Hangul jamo: the vowel takes no column
+		{"a\tb\n", 2},
+		{"°±", 2},
+		{"", 0},
+		{"\x80", 1},
+	}
+	for _, tt := range tests {
+		if got := Width(tt.s); got != tt.want {
+			t.Errorf("Width(%+q) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+	if got := (Measure{AmbiguousWide: true}).Width("°±"); got != 4 {
+		t.Errorf("Width(°±) with AmbiguousWide = %d, want 4", got)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s    string
+		n    int
+		tail string
+		want string
+	}{
+		{"abcdef", 6, "…", "abcdef"},
+		{"abcdef", 5, "…", "abcd…"},
+		{"abcdef", 1, "…", "…"},
+		{"abcdef", 0, "…", ""},
+		{"abcdef", -1, "…", ""},
+		{"abcdef", 2, "...", ".."},
+		{"abcdef", 3, "", "abc"},
+		{"日本語", 5, "…", "日本…"},
+		{"日本語", 4, "", "日本"},
+		{"日本語", 3, "", "日"}, // a wide character is not cut in half
+		{"👨‍👩‍👧x", 2, "", "👨‍👩‍👧"},
+		{"🇯🇵🇺🇸", 3, "", "🇯🇵"},
+		{"cafés", 4, "", "café"},
+		{"abc", 1, "日本", ""}, // the tail cut to 1 column has nothing left
+	}
+	for _, tt := range tests {
+		got := Truncate(tt.s, tt.n, tt.tail)
+		if got != tt.want {
+			t.Errorf("Truncate(%+q, %d, %q) = %+q, want %+q", tt.s, tt.n, tt.tail, got, tt.want)
+		}
+		if w := Width(got); w > max(tt.n, 0) {
+			t.Errorf("Truncate(%+q, %d, %q) is %d columns wide", tt.s, tt.n, tt.tail, w)
+		}
+	}
+}
+
+func TestPad(t *testing.T) {
+	if got := PadLeft("日本", 6); got != "  日本" {
+		t.Errorf("PadLeft = %q", got)
+	}
+	if got := PadRight("👍🏽", 4); got != "👍🏽  " {
+		t.Errorf("PadRight = %q", got)
+	}
+	if got := Center("é", 4); got != " é  " {
+		t.Errorf("Center = %q", got)
+	}
+	if got := PadRight("toolong", 3); got != "toolong" {
+		t.Errorf("PadRight of a wider string = %q", got)
+	}
+}
@@ -0,0 +1,104 @@
+package display
+
+import (
+	"io"
+	"strings"
+)
+
+// Alignment is how a cell sits in its column.
+type Alignment int
+
+const (
+	Left Alignment = iota
+	Right
+	Centered
+)
+
+// Table lines up rows of cells in columns as wide as their widest cell:
+//
+//	var t display.Table
+//	t.Align = []display.Alignment{display.Left, display.Right}
+//	t.Row("name", "size")
+//	t.Row("日本語.txt", "12")
+//	t.Row("notes 📝", "1024")
+//	fmt.Print(t.String())
+//
+// columns without an entry in Align are aligned Left. the last cell of a row
+// gets no padding after it, so lines have no trailing spaces.
+type Table struct {
+	Sep     string // between columns; "  " if empty
+	Align   []Alignment
+	Measure Measure
+	rows    [][]string
+}
+
+// Row adds a row. rows may have different numbers of cells.
+func (t *Table) Row(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+// String returns the table, one line per row, each ending in a newline.
+func (t *Table) String() string {
+	var b strings.Builder
+	t.WriteTo(&b)
+	return b.String()
+}
+
+// WriteTo writes the table to w.
+func (t *Table) WriteTo(w io.Writer) (int64, error) {
+	sep := t.Sep
+	if sep == "" {
+		sep = "  "
+	}
+	var widths []int
+	for _, row := range t.rows {
+		for i, cell := range row {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], t.Measure.Width(cell))
+		}
+	}
+
+	var total int64
+	var line strings.Builder
+	for _, row := range t.rows {
+		line.Reset()
+		for i, cell := range row {
+			if i > 0 {
+				line.WriteString(sep)
+			}
+			last := i == len(row)-1
+			switch t.align(i) {
+			case Right:
+				line.WriteString(t.Measure.PadLeft(cell, widths[i]))
+			case Centered:
+				pad := widths[i] - t.Measure.Width(cell)
+				line.WriteString(strings.Repeat(" ", pad/2) + cell)
+				if !last {
+					line.WriteString(strings.Repeat(" ", pad-pad/2))
+				}
+			default:
+				if last {
+					line.WriteString(cell)
+				} else {
+					line.WriteString(t.Measure.PadRight(cell, widths[i]))
+				}
+			}
+		}
+		line.WriteByte('\n')
+		n, err := io.WriteString(w, line.String())
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+func (t *Table) align(col int) Alignment {
+	if col < len(t.Align) {
+		return t.Align[col]
+	}
+	return Left
+}
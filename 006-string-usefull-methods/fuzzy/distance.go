@@ -0,0 +1,144 @@
+// Package fuzzy measures how alike two strings are and finds strings that
+// loosely match a pattern, for "did you mean" suggestions and fuzzy finders.
+//
+// strings.Compare and strings.EqualFold only say equal or not. the
+// functions here say how far apart two strings are:
+//
+//	Levenshtein("kitten", "sitting")         3   insertions, deletions, substitutions
+//	DamerauLevenshtein("form", "from")       1   a swap of neighbours counts as one edit
+//	JaroWinkler("martha", "marhta")          0.96  1 is equal, favours a common prefix
+//	LCS("AGGTAB", "GXTXAYB")                 "GTAB"
+//	Jaccard("night", "nacht", 2)             0.14  shared bigrams: only "ht"
+//
+// all of them work on runes, not bytes, so "café" and "cafe" are one
+// substitution apart. Find ranks candidates for a pattern the way fzf does,
+// and Suggest picks the close ones for an unknown command.
+package fuzzy
+
+import "unicode/utf8"
+
+// Levenshtein returns the edit distance between a and b: the fewest rune
+// insertions, deletions and substitutions that turn one into the other.
+func Levenshtein(a, b string) int {
+	return LevenshteinMax(a, b, utf8.RuneCountInString(a)+utf8.RuneCountInString(b))
+}
+
+// LevenshteinMax is Levenshtein for callers that only care about small
+// distances: it stops as soon as the distance must be larger than limit and
+// returns limit+1. that makes it cheap to compare an input against many
+// candidates.
+func LevenshteinMax(a, b string, limit int) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) > len(rb) {
+		ra, rb = rb, ra // ra is the shorter: one row of len(ra)+1
+	}
+	if len(rb)-len(ra) > limit {
+		return limit + 1 // the length difference alone is too much
+	}
+
+	// row[i] is the distance between ra[:i] and rb[:j] for the current j
+	row := make([]int, len(ra)+1)
+	for i := range row {
+		row[i] = i
+	}
+	for j := 1; j <= len(rb); j++ {
+		diag := row[0] // distance of ra[:i-1] and rb[:j-1]
+		row[0] = j
+		best := row[0]
+		for i := 1; i <= len(ra); i++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			diag, row[i] = row[i], min(row[i]+1, row[i-1]+1, diag+cost)
+			best = min(best, row[i])
+		}
+		if best > limit {
+			return limit + 1 // every later row is at least as large
+		}
+	}
+	return min(row[len(ra)], limit+1)
+}
+
+// DamerauLevenshtein is Levenshtein where swapping two neighbouring runes
+// is also one edit, the most common typo: "teh" is 1 from "the", not 2.
+// it is the unrestricted distance, so a substring may be edited again after
+// a swap: "ca" to "abc" is 2 (swap, then insert between), where the simpler
+// optimal string alignment distance gives 3.
+func DamerauLevenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	inf := len(ra) + len(rb)
+
+	// d[i+1][j+1] is the distance between ra[:i] and rb[:j]; row and
+	// column 0 hold inf so a transposition never reaches before the start
+	d := make([][]int, len(ra)+2)
+	for i := range d {
+		d[i] = make([]int, len(rb)+2)
+		d[i][0] = inf
+	}
+	for j := range d[0] {
+		d[0][j] = inf
+	}
+	for i := 0; i <= len(ra); i++ {
+		d[i+1][1] = i
+	}
+	for j := 0; j <= len(rb); j++ {
+		d[1][j+1] = j
+	}
+
+	lastRow := map[rune]int{} // the last row of ra in which each rune was seen
+	for i := 1; i <= len(ra); i++ {
+		lastCol := 0 // the last column in this row where ra[i-1] matched
+		for j := 1; j <= len(rb); j++ {
+			k, l := lastRow[rb[j-1]], lastCol
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost, lastCol = 0, j
+			}
+			d[i+1][j+1] = min(
+				d[i][j]+cost,              // substitution
+				d[i+1][j]+1,               // insertion
+				d[i][j+1]+1,               // deletion
+				d[k][l]+(i-k-1)+1+(j-l-1), // transposition of ra[k-1] and ra[i-1]
+			)
+		}
+		lastRow[ra[i-1]] = i
+	}
+	return d[len(ra)+1][len(rb)+1]
+}
+
+// LCS returns a longest common subsequence of a and b: the longest string
+// whose runes appear in both, in order but not necessarily next to each
+// other. when there are several of the same length, it returns one of them.
+func LCS(a, b string) string {
+	ra, rb := []rune(a), []rune(b)
+
+	// n[i][j] is the length of the LCS of ra[i:] and rb[j:]
+	n := make([][]int, len(ra)+1)
+	for i := range n {
+		n[i] = make([]int, len(rb)+1)
+	}
+	for i := len(ra) - 1; i >= 0; i-- {
+		for j := len(rb) - 1; j >= 0; j-- {
+			if ra[i] == rb[j] {
+				n[i][j] = n[i+1][j+1] + 1
+			} else {
+				n[i][j] = max(n[i+1][j], n[i][j+1])
+			}
+		}
+	}
+
+	out := make([]rune, 0, n[0][0])
+	for i, j := 0, 0; i < len(ra) && j < len(rb); {
+		switch {
+		case ra[i] == rb[j]:
+			out = append(out, ra[i])
+			i, j = i+1, j+1
+		case n[i+1][j] >= n[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return string(out)
+}
@@ -0,0 +1,232 @@
+package fuzzy
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Match is a candidate that contains the pattern's runes in order.
+type Match struct {
+	Str       string
+	Index     int   // of Str in the candidates given to Find
+	Score     int   // higher is better
+	Positions []int // rune indexes in Str of the matched runes, ascending
+}
+
+// scoring, after fzf: every matched rune scores, gaps between matches cost,
+// and a match at the start of a word earns a bonus, so "fb" ranks
+// "foo_bar" above "fabric". the first rune of the pattern earns its bonus
+// twice, and a run of matches keeps the bonus of the rune that started it.
+const (
+	scoreMatch        = 16
+	gapStart          = -3
+	gapExtension      = -1
+	bonusWhite        = 10 // at the start, or after a space
+	bonusDelimiter    = 9  // after / , : ; |
+	bonusBoundary     = 8  // after any other punctuation: _ - .
+	bonusCamel        = 7  // lower case to upper case, letter to digit
+	bonusConsecutive  = 4
+	bonusFirstPattern = 2 // multiplier for the first rune of the pattern
+)
+
+// Find returns the candidates that contain the runes of pattern in order,
+// best first: by score, then shorter, then in their original order. the
+// match ignores case unless the pattern has an upper case letter ("smart
+// case"). an empty pattern matches everything with score 0.
+func Find(pattern string, candidates []string) []Match {
+	var ms []Match
+	for i, c := range candidates {
+		if score, pos, ok := Score(pattern, c); ok {
+			ms = append(ms, Match{Str: c, Index: i, Score: score, Positions: pos})
+		}
+	}
+	slices.SortStableFunc(ms, func(a, b Match) int {
+		return cmp.Or(
+			cmp.Compare(b.Score, a.Score),
+			cmp.Compare(utf8.RuneCountInString(a.Str), utf8.RuneCountInString(b.Str)),
+		)
+	})
+	return ms
+}
+
+// Score matches pattern against s as Find does. it returns the best score
+// of all the ways the pattern's runes can be found in s, and the positions
+// of that way; ok is false if s does not contain them in order.
+func Score(pattern, s string) (score int, positions []int, ok bool) {
+	p, t := []rune(pattern), []rune(s)
+	if len(p) == 0 {
+		return 0, nil, true
+	}
+	fold := !hasUpper(pattern)
+	eq := func(pr, tr rune) bool {
+		if fold {
+			tr = unicode.ToLower(tr)
+		}
+		return pr == tr
+	}
+
+	// the pattern can only match from the first rune of s that equals p[0];
+	// checking it is a subsequence first rejects most candidates cheaply
+	first, i := -1, 0
+	for j, r := range t {
+		if i < len(p) && eq(p[i], r) {
+			if i == 0 {
+				first = j
+			}
+			i++
+		}
+	}
+	if i < len(p) {
+		return 0, nil, false
+	}
+	t = t[first:]
+	bonus := bonuses([]rune(s), first)
+
+	// h[i][j] is the best score of p[:i+1] with p[i] matched at t[j];
+	// chain[i][j] the bonus its run of consecutive matches carries, and
+	// back[i][j] where p[i-1] was matched
+	const none = math.MinInt / 2
+	n := len(t)
+	h := make([][]int, len(p))
+	chain := make([][]int, len(p))
+	back := make([][]int, len(p))
+	for i := range p {
+		h[i], chain[i], back[i] = make([]int, n), make([]int, n), make([]int, n)
+		gap, gapFrom := none, -1 // best h[i-1][k] + gap penalty, over k < j-1
+		for j := range n {
+			h[i][j] = none
+			if i > 0 && j >= 2 {
+				if open := h[i-1][j-2] + gapStart; open >= gap+gapExtension {
+					gap, gapFrom = open, j-2
+				} else {
+					gap += gapExtension
+				}
+			}
+			if !eq(p[i], t[j]) {
+				continue
+			}
+			if i == 0 {
+				h[i][j], chain[i][j] = scoreMatch+bonus[j]*bonusFirstPattern, bonus[j]
+				continue
+			}
+			if gap > none {
+				h[i][j], chain[i][j], back[i][j] = gap+scoreMatch+bonus[j], bonus[j], gapFrom
+			}
+			if j >= 1 && h[i-1][j-1] > none {
+				b := max(chain[i-1][j-1], bonus[j], bonusConsecutive)
+				if c := h[i-1][j-1] + scoreMatch + b; c >= h[i][j] {
+					h[i][j], chain[i][j], back[i][j] = c, b, j-1
+				}
+			}
+		}
+	}
+
+	last := len(p) - 1
+	end := -1
+	for j := range n {
+		if h[last][j] > none && (end < 0 || h[last][j] > h[last][end]) {
+			end = j
+		}
+	}
+	positions = make([]int, len(p))
+	for i, j := last, end; i >= 0; i-- {
+		positions[i] = first + j
+		j = back[i][j]
+	}
+	return h[last][end], positions, true
+}
+
+// bonuses returns the bonus for a match at each rune of s from index from.
+func bonuses(s []rune, from int) []int {
+	b := make([]int, len(s)-from)
+	for j := from; j < len(s); j++ {
+		if j == 0 {
+			b[0] = bonusWhite
+			continue
+		}
+		prev, r := s[j-1], s[j]
+		switch {
+		case !isWord(r):
+			// no bonus for matching punctuation itself
+		case unicode.IsSpace(prev):
+			b[j-from] = bonusWhite
+		case strings.ContainsRune("/,:;|", prev):
+			b[j-from] = bonusDelimiter
+		case !isWord(prev):
+			b[j-from] = bonusBoundary
+		case unicode.IsLower(prev) && unicode.IsUpper(r),
+			!unicode.IsDigit(prev) && unicode.IsDigit(r):
+			b[j-from] = bonusCamel
+		}
+	}
+	return b
+}
+
+func isWord(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }
+
+func hasUpper(s string) bool { return strings.IndexFunc(s, unicode.IsUpper) >= 0 }
+
+// Highlight wraps the runes of s at positions in open and close, joining
+// neighbours into one span: Highlight("foo_bar", []int{0, 1, 4}, "[", "]")
+// is "[fo]o_[b]ar". for a terminal, open and close can be escape codes like
+// "\x1b[1m" and "\x1b[0m".
+func Highlight(s string, positions []int, open, close string) string {
+	var b strings.Builder
+	k, in := 0, false
+	i := 0
+	for _, r := range s {
+		hit := k < len(positions) && positions[k] == i
+		if hit {
+			k++
+		}
+		if hit != in {
+			if hit {
+				b.WriteString(open)
+			} else {
+				b.WriteString(close)
+			}
+			in = hit
+		}
+		b.WriteRune(r)
+		i++
+	}
+	if in {
+		b.WriteString(close)
+	}
+	return b.String()
+}
+
+// Suggest returns the candidates close enough to input to be what was
+// meant, closest first, for a "did you mean" message. close enough is a
+// Damerau-Levenshtein distance of at most a third of the input's length
+// (at least 1), ignoring case, or starting with the input.
+func Suggest(input string, candidates []string) []string {
+	in := strings.ToLower(input)
+	limit := max(1, utf8.RuneCountInString(in)/3)
+	type suggestion struct {
+		s    string
+		dist int
+		sim  float64
+	}
+	var ss []suggestion
+	for _, c := range candidates {
+		lc := strings.ToLower(c)
+		d := DamerauLevenshtein(in, lc)
+		if d > limit && !(in != "" && strings.HasPrefix(lc, in)) {
+			continue
+		}
+		ss = append(ss, suggestion{c, d, JaroWinkler(in, lc)})
+	}
+	slices.SortStableFunc(ss, func(a, b suggestion) int {
+		return cmp.Or(cmp.Compare(a.dist, b.dist), cmp.Compare(b.sim, a.sim))
+	})
+	out := make([]string, len(ss))
+	for i, s := range ss {
+		out[i] = s.s
+	}
+	return out
+}
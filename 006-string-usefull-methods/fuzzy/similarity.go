@@ -0,0 +1,105 @@
+package fuzzy
+
+// Jaro returns the Jaro similarity of a and b, from 0 (nothing in common)
+// to 1 (equal). it counts the runes the two share within a window of half
+// the longer length, and how many of those are out of order.
+func Jaro(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 && len(rb) == 0 {
+		return 1
+	}
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+	window := max(0, max(len(ra), len(rb))/2-1)
+
+	matchedA := make([]bool, len(ra))
+	matchedB := make([]bool, len(rb))
+	matches := 0
+	for i, r := range ra {
+		for j := max(0, i-window); j <= min(len(rb)-1, i+window); j++ {
+			if !matchedB[j] && rb[j] == r {
+				matchedA[i], matchedB[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	// the matched runes of a and b, in order: each pair that differs is half
+	// a transposition
+	half, j := 0, 0
+	for i := range ra {
+		if !matchedA[i] {
+			continue
+		}
+		for !matchedB[j] {
+			j++
+		}
+		if ra[i] != rb[j] {
+			half++
+		}
+		j++
+	}
+	m := float64(matches)
+	return (m/float64(len(ra)) + m/float64(len(rb)) + (m-float64(half)/2)/m) / 3
+}
+
+// JaroWinkler is Jaro with a bonus for a common prefix of up to 4 runes,
+// since typos are rarer at the start of a word. as in Winkler's paper the
+// bonus only applies when the Jaro similarity is above 0.7.
+func JaroWinkler(a, b string) float64 {
+	const (
+		scale     = 0.1 // bonus per prefix rune
+		maxPrefix = 4
+		threshold = 0.7
+	)
+	j := Jaro(a, b)
+	if j <= threshold {
+		return j
+	}
+	ra, rb := []rune(a), []rune(b)
+	prefix := 0
+	for prefix < min(len(ra), len(rb), maxPrefix) && ra[prefix] == rb[prefix] {
+		prefix++
+	}
+	return j + float64(prefix)*scale*(1-j)
+}
+
+// Jaccard returns how many n-grams (runs of n runes) a and b share, out of
+// all the n-grams either has: from 0 to 1. a string shorter than n is one
+// n-gram on its own. two empty strings are equal, so their similarity is 1.
+// an n below 1 is treated as 1, comparing the sets of runes.
+func Jaccard(a, b string, n int) float64 {
+	n = max(n, 1)
+	ga, gb := ngrams(a, n), ngrams(b, n)
+	if len(ga) == 0 && len(gb) == 0 {
+		return 1
+	}
+	shared := 0
+	for g := range ga {
+		if gb[g] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(ga)+len(gb)-shared)
+}
+
+func ngrams(s string, n int) map[string]bool {
+	rs := []rune(s)
+	grams := map[string]bool{}
+	if len(rs) == 0 {
+		return grams
+	}
+	if len(rs) <= n {
+		grams[s] = true
+		return grams
+	}
+	for i := 0; i+n <= len(rs); i++ {
+		grams[string(rs[i:i+n])] = true
+	}
+	return grams
+}
@@ -0,0 +1,51 @@
+package fuzzy
+
+import (
+	"math"
+	"testing"
+)
+
+func TestJaccard(t *testing.T) {
+	tests := []struct {
+		a, b string
+		n    int
+		want float64
+	}{
+		{"night", "nacht", 2, 1.0 / 7}, // ht of ni ig gh ht na ac ch
+		{"abc", "abc", 3, 1},
+		{"ab", "abc", 3, 0}, // "ab" is one 2-rune n-gram
+		{"", "", 2, 1},
+		{"", "a", 2, 0},
+		{"abc", "cba", 1, 1},
+		// n below 1 compares the runes, as n = 1 does
+		{"abc", "cba", 0, 1},
+		{"night", "nacht", -1, 3.0 / 7},
+		{"", "", -5, 1},
+	}
+	for _, tt := range tests {
+		if got := Jaccard(tt.a, tt.b, tt.n); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("Jaccard(%q, %q, %d) = %v, want %v", tt.a, tt.b, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestJaroWinkler(t *testing.T) {
+	tests := []struct {
+		a, b          string
+		jaro, winkler float64
+	}{
+		{"MARTHA", "MARHTA", 0.944, 0.961},
+		{"DWAYNE", "DUANE", 0.822, 0.840},
+		{"DIXON", "DICKSONX", 0.767, 0.813},
+		{"abc", "xyz", 0, 0},
+		{"", "", 1, 1},
+	}
+	for _, tt := range tests {
+		if got := Jaro(tt.a, tt.b); math.Abs(got-tt.jaro) > 5e-4 {
+			t.Errorf("Jaro(%q, %q) = %.3f, want %.3f", tt.a, tt.b, got, tt.jaro)
+		}
+		if got := JaroWinkler(tt.a, tt.b); math.Abs(got-tt.winkler) > 5e-4 {
+			t.Errorf("JaroWinkler(%q, %q) = %.3f, want %.3f", tt.a, tt.b, got, tt.winkler)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+//go:build ignore
+
+// gen.go writes tables.go, the Unicode properties the segmenter needs for
+// every code point: Grapheme_Cluster_Break, Extended_Pictographic and
+// Indic_Conjunct_Break. run it with go generate.
+//
+// Grapheme_Cluster_Break is derived from the tables in package unicode the
+// way UAX #29 (table 2) defines it, so the output follows the Unicode version
+// of the Go release that runs this. the inputs package unicode does not have
+// (Hangul and Kirat Rai syllable types, the Extended_Pictographic ranges,
+// the Indic syllabic categories behind Prepend and Indic_Conjunct_Break)
+// are listed below, copied from the Unicode Character Database; the test
+// against testdata/GraphemeBreakTest.txt catches a list that is out of date.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"unicode"
+)
+
+// property values; the same names and bits as in grapheme.go
+const (
+	prOther = iota
+	prCR
+	prLF
+	prControl
+	prExtend
+	prZWJ
+	prRegionalIndicator
+	prPrepend
+	prSpacingMark
+	prL
+	prV
+	prT
+	prLV
+	prLVT
+)
+
+const (
+	pictographic = 1 << 4
+	inLinker     = 1 << 5
+	inConsonant  = 2 << 5
+	inExtend     = 3 << 5
+)
+
+var gcbNames = [...]string{
+	"prOther", "prCR", "prLF", "prControl", "prExtend", "prZWJ", "prRegionalIndicator",
+	"prPrepend", "prSpacingMark", "prL", "prV", "prT", "prLV", "prLVT",
+}
+
+// Extended_Pictographic, from emoji-data.txt. since Emoji 17.0 the ranges
+// leave out unassigned code points and the symbols that are not emoji.
+var extPict = ranges(
+	0x00A9, 0x00A9, 0x00AE, 0x00AE, 0x203C, 0x203C, 0x2049, 0x2049,
+	0x2122, 0x2122, 0x2139, 0x2139, 0x2194, 0x2199, 0x21A9, 0x21AA,
+	0x231A, 0x231B, 0x2328, 0x2328, 0x23CF, 0x23CF, 0x23E9, 0x23F3,
+	0x23F8, 0x23FA, 0x24C2, 0x24C2, 0x25AA, 0x25AB, 0x25B6, 0x25B6,
+	0x25C0, 0x25C0, 0x25FB, 0x25FE, 0x2600, 0x2604, 0x260E, 0x260E,
+	0x2611, 0x2611, 0x2614, 0x2615, 0x2618, 0x2618, 0x261D, 0x261D,
+	0x2620, 0x2620, 0x2622, 0x2623, 0x2626, 0x2626, 0x262A, 0x262A,
+	0x262E, 0x262F, 0x2638, 0x263A, 0x2640, 0x2640, 0x2642, 0x2642,
+	0x2648, 0x2653, 0x265F, 0x2660, 0x2663, 0x2663, 0x2665, 0x2666,
+	0x2668, 0x2668, 0x267B, 0x267B, 0x267E, 0x267F, 0x2692, 0x2697,
+	0x2699, 0x2699, 0x269B, 0x269C, 0x26A0, 0x26A1, 0x26A7, 0x26A7,
+	0x26AA, 0x26AB, 0x26B0, 0x26B1, 0x26BD, 0x26BE, 0x26C4, 0x26C5,
+	0x26C8, 0x26C8, 0x26CE, 0x26CF, 0x26D1, 0x26D1, 0x26D3, 0x26D4,
+	0x26E9, 0x26EA, 0x26F0, 0x26F5, 0x26F7, 0x26FA, 0x26FD, 0x26FD,
+	0x2702, 0x2702, 0x2705, 0x2705, 0x2708, 0x270D, 0x270F, 0x270F,
+	0x2712, 0x2712, 0x2714, 0x2714, 0x2716, 0x2716, 0x271D, 0x271D,
+	0x2721, 0x2721, 0x2728, 0x2728, 0x2733, 0x2734, 0x2744, 0x2744,
+	0x2747, 0x2747, 0x274C, 0x274C, 0x274E, 0x274E, 0x2753, 0x2755,
+	0x2757, 0x2757, 0x2763, 0x2764, 0x2795, 0x2797, 0x27A1, 0x27A1,
+	0x27B0, 0x27B0, 0x27BF, 0x27BF, 0x2934, 0x2935, 0x2B05, 0x2B07,
+	0x2B1B, 0x2B1C, 0x2B50, 0x2B50, 0x2B55, 0x2B55, 0x3030, 0x3030,
+	0x303D, 0x303D, 0x3297, 0x3297, 0x3299, 0x3299, 0x1F004, 0x1F004,
+	0x1F02C, 0x1F02F, 0x1F094, 0x1F09F, 0x1F0AF, 0x1F0B0, 0x1F0C0, 0x1F0C0,
+	0x1F0CF, 0x1F0D0, 0x1F0F6, 0x1F0FF, 0x1F170, 0x1F171, 0x1F17E, 0x1F17F,
+	0x1F18E, 0x1F18E, 0x1F191, 0x1F19A, 0x1F1AE, 0x1F1E5, 0x1F201, 0x1F20F,
+	0x1F21A, 0x1F21A, 0x1F22F, 0x1F22F, 0x1F232, 0x1F23A, 0x1F23C, 0x1F23F,
+	0x1F249, 0x1F25F, 0x1F266, 0x1F321, 0x1F324, 0x1F393, 0x1F396, 0x1F397,
+	0x1F399, 0x1F39B, 0x1F39E, 0x1F3F0, 0x1F3F3, 0x1F3F5, 0x1F3F7, 0x1F3FA,
+	0x1F400, 0x1F4FD, 0x1F4FF, 0x1F53D, 0x1F549, 0x1F54E, 0x1F550, 0x1F567,
+	0x1F56F, 0x1F570, 0x1F573, 0x1F57A, 0x1F587, 0x1F587, 0x1F58A, 0x1F58D,
+	0x1F590, 0x1F590, 0x1F595, 0x1F596, 0x1F5A4, 0x1F5A5, 0x1F5A8, 0x1F5A8,
+	0x1F5B1, 0x1F5B2, 0x1F5BC, 0x1F5BC, 0x1F5C2, 0x1F5C4, 0x1F5D1, 0x1F5D3,
+	0x1F5DC, 0x1F5DE, 0x1F5E1, 0x1F5E1, 0x1F5E3, 0x1F5E3, 0x1F5E8, 0x1F5E8,
+	0x1F5EF, 0x1F5EF, 0x1F5F3, 0x1F5F3, 0x1F5FA, 0x1F64F, 0x1F680, 0x1F6C5,
+	0x1F6CB, 0x1F6D2, 0x1F6D5, 0x1F6E5, 0x1F6E9, 0x1F6E9, 0x1F6EB, 0x1F6F0,
+	0x1F6F3, 0x1F6FF, 0x1F7DA, 0x1F7FF, 0x1F80C, 0x1F80F, 0x1F848, 0x1F84F,
+	0x1F85A, 0x1F85F, 0x1F888, 0x1F88F, 0x1F8AE, 0x1F8AF, 0x1F8BC, 0x1F8BF,
+	0x1F8C2, 0x1F8CF, 0x1F8D9, 0x1F8FF, 0x1F90C, 0x1F93A, 0x1F93C, 0x1F945,
+	0x1F947, 0x1F9FF, 0x1FA58, 0x1FA5F, 0x1FA6E, 0x1FAFF, 0x1FC00, 0x1FFFD,
+)
+
+// Indic_Syllabic_Category Consonant_Preceding_Repha and Consonant_Prefixed,
+// which are Prepend along with Prepended_Concatenation_Mark.
+var prepend = ranges(
+	0x0D4E, 0x0D4E, 0x111C2, 0x111C3, 0x113D1, 0x113D1, 0x1193F, 0x1193F,
+	0x11941, 0x11941, 0x11A84, 0x11A89, 0x11D46, 0x11D46, 0x11F02, 0x11F02,
+)
+
+// SpacingMark is General_Category Mc that is not Extend, with these
+// exceptions from UAX #29, and U+0E33 and U+0EB3 besides.
+var notSpacingMark = ranges(
+	0x102B, 0x102C, 0x1038, 0x1038, 0x1062, 0x1064, 0x1067, 0x106D,
+	0x1083, 0x1083, 0x1087, 0x108C, 0x108F, 0x108F, 0x109A, 0x109C,
+	0x1A61, 0x1A61, 0x1A63, 0x1A64, 0xAA7B, 0xAA7B, 0xAA7D, 0xAA7D,
+	0x11720, 0x11721,
+)
+
+// Indic_Conjunct_Break, for rule GB9c, from DerivedCoreProperties.txt: the
+// viramas that join consonants into a conjunct, and the consonants of the
+// same scripts, from Devanagari to Kawi. InCB=Extend is derived: the other
+// Extend characters and ZWJ, without U+200C ZWNJ.
+var (
+	linker = ranges(
+		0x094D, 0x094D, 0x09CD, 0x09CD, 0x0ACD, 0x0ACD, 0x0B4D, 0x0B4D,
+		0x0C4D, 0x0C4D, 0x0D4D, 0x0D4D, 0x1039, 0x1039, 0x17D2, 0x17D2,
+		0x1A60, 0x1A60, 0x1B44, 0x1B44, 0x1BAB, 0x1BAB, 0xA9C0, 0xA9C0,
+		0xAAF6, 0xAAF6, 0x10A3F, 0x10A3F, 0x11133, 0x11133, 0x113D0, 0x113D0,
+		0x1193E, 0x1193E, 0x11A47, 0x11A47, 0x11A99, 0x11A99, 0x11F42, 0x11F42,
+	)
+	consonant = ranges(
+		0x0915, 0x0939, 0x0958, 0x095F, 0x0978, 0x097F, 0x0995, 0x09A8,
+		0x09AA, 0x09B0, 0x09B2, 0x09B2, 0x09B6, 0x09B9, 0x09DC, 0x09DD,
+		0x09DF, 0x09DF, 0x09F0, 0x09F1, 0x0A95, 0x0AA8, 0x0AAA, 0x0AB0,
+		0x0AB2, 0x0AB3, 0x0AB5, 0x0AB9, 0x0AF9, 0x0AF9, 0x0B15, 0x0B28,
+		0x0B2A, 0x0B30, 0x0B32, 0x0B33, 0x0B35, 0x0B39, 0x0B5C, 0x0B5D,
+		0x0B5F, 0x0B5F, 0x0B71, 0x0B71, 0x0C15, 0x0C28, 0x0C2A, 0x0C39,
+		0x0C58, 0x0C5A, 0x0D15, 0x0D3A, 0x1000, 0x102A, 0x103F, 0x103F,
+		0x1050, 0x1055, 0x105A, 0x105D, 0x1061, 0x1061, 0x1065, 0x1066,
+		0x106E, 0x1070, 0x1075, 0x1081, 0x108E, 0x108E, 0x1780, 0x17B3,
+		0x1A20, 0x1A54, 0x1B0B, 0x1B0C, 0x1B13, 0x1B33, 0x1B45, 0x1B4C,
+		0x1B83, 0x1BA0, 0x1BAE, 0x1BAF, 0x1BBB, 0x1BBD, 0xA989, 0xA98B,
+		0xA98F, 0xA9B2, 0xA9E0, 0xA9E4, 0xA9E7, 0xA9EF, 0xA9FA, 0xA9FE,
+		0xAA60, 0xAA6F, 0xAA71, 0xAA73, 0xAA7A, 0xAA7A, 0xAA7E, 0xAA7F,
+		0xAAE0, 0xAAEA, 0xABC0, 0xABDA, 0x10A00, 0x10A00, 0x10A10, 0x10A13,
+		0x10A15, 0x10A17, 0x10A19, 0x10A35, 0x11103, 0x11126, 0x11144, 0x11144,
+		0x11147, 0x11147, 0x11380, 0x11389, 0x1138B, 0x1138B, 0x1138E, 0x1138E,
+		0x11390, 0x113B5, 0x11900, 0x11906, 0x11909, 0x11909, 0x1190C, 0x11913,
+		0x11915, 0x11916, 0x11918, 0x1192F, 0x11A00, 0x11A00, 0x11A0B, 0x11A32,
+		0x11A50, 0x11A50, 0x11A5C, 0x11A83, 0x11F04, 0x11F10, 0x11F12, 0x11F33,
+	)
+)
+
+func ranges(bounds ...rune) *unicode.RangeTable {
+	t := new(unicode.RangeTable)
+	for i := 0; i < len(bounds); i += 2 {
+		t.R32 = append(t.R32, unicode.Range32{Lo: uint32(bounds[i]), Hi: uint32(bounds[i+1]), Stride: 1})
+	}
+	return t
+}
+
+func gcb(r rune) int {
+	switch {
+	case r == '\r':
+		return prCR
+	case r == '\n':
+		return prLF
+	case r == 0x200D:
+		return prZWJ
+	case 0x1F1E6 <= r && r <= 0x1F1FF:
+		return prRegionalIndicator
+	case unicode.Is(unicode.Prepended_Concatenation_Mark, r) || unicode.Is(prepend, r):
+		return prPrepend
+	case 0x1100 <= r && r <= 0x115F || 0xA960 <= r && r <= 0xA97C:
+		return prL
+	case 0x1160 <= r && r <= 0x11A7 || 0xD7B0 <= r && r <= 0xD7C6,
+		r == 0x16D63 || 0x16D67 <= r && r <= 0x16D6A: // Kirat Rai vowel signs
+		return prV
+	case 0x11A8 <= r && r <= 0x11FF || 0xD7CB <= r && r <= 0xD7FB:
+		return prT
+	case 0xAC00 <= r && r <= 0xD7A3:
+		if (r-0xAC00)%28 == 0 {
+			return prLV
+		}
+		return prLVT
+	case extend(r):
+		return prExtend
+	case unicode.In(r, unicode.Zl, unicode.Zp, unicode.Cc, unicode.Cf),
+		!assigned(r) && unicode.Is(unicode.Other_Default_Ignorable_Code_Point, r):
+		return prControl
+	case unicode.Is(unicode.Mc, r) && !unicode.Is(notSpacingMark, r), r == 0x0E33, r == 0x0EB3:
+		return prSpacingMark
+	}
+	return prOther
+}
+
+// extend is Grapheme_Extend (Mn, Me and Other_Grapheme_Extend) or
+// Emoji_Modifier, the skin tones.
+func extend(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Other_Grapheme_Extend) ||
+		0x1F3FB <= r && r <= 0x1F3FF
+}
+
+// assigned leaves out Cn. unicode.C is not only Cc, Cf, Co and Cs: it
+// takes in the unassigned code points too.
+func assigned(r rune) bool {
+	return unicode.In(r, unicode.L, unicode.M, unicode.N, unicode.P, unicode.S, unicode.Z,
+		unicode.Cc, unicode.Cf, unicode.Co, unicode.Cs)
+}
+
+func property(r rune) int {
+	p := gcb(r)
+	if unicode.Is(extPict, r) {
+		p |= pictographic
+	}
+	switch {
+	case unicode.Is(linker, r):
+		p |= inLinker
+	case unicode.Is(consonant, r):
+		p |= inConsonant
+	case (p == prExtend || p == prZWJ) && r != 0x200C:
+		p |= inExtend
+	}
+	return p
+}
+
+func name(p int) string {
+	s := gcbNames[p&0xF]
+	if p&pictographic != 0 {
+		s += " | pictographic"
+	}
+	switch p &^ (pictographic | 0xF) {
+	case inLinker:
+		s += " | inLinker"
+	case inConsonant:
+		s += " | inConsonant"
+	case inExtend:
+		s += " | inExtend"
+	}
+	return s
+}
+
+func main() {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by gen.go; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package grapheme\n\n")
+	fmt.Fprintf(&b, "// UnicodeVersion is the version of Unicode the tables were generated from.\n")
+	fmt.Fprintf(&b, "const UnicodeVersion = %q\n\n", unicode.Version)
+	fmt.Fprintf(&b, "// properties lists every code point whose property is not prOther, as\n")
+	fmt.Fprintf(&b, "// sorted ranges.\n")
+	fmt.Fprintf(&b, "var properties = [...]propertyRange{\n")
+	n := 0
+	lo, cur := rune(0), property(0)
+	flush := func(hi rune) {
+		if cur != prOther {
+			fmt.Fprintf(&b, "\t{0x%04X, 0x%04X, %s},\n", lo, hi, name(cur))
+			n++
+		}
+	}
+	for r := rune(1); r <= unicode.MaxRune; r++ {
+		if p := property(r); p != cur {
+			flush(r - 1)
+			lo, cur = r, p
+		}
+	}
+	flush(unicode.MaxRune)
+	fmt.Fprintf(&b, "}\n")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("tables.go", src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("tables.go: %d ranges, Unicode %s", n, unicode.Version)
+}
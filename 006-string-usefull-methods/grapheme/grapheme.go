@@ -0,0 +1,246 @@
+// Package grapheme splits strings into grapheme clusters, the "characters"
+// a reader sees, following the extended grapheme cluster rules of Unicode
+// Standard Annex #29.
+//
+// the lesson counts bytes with len and characters with
+// utf8.RuneCountInString, but one visible character can be several runes:
+//
+//	"é"     e + U+0301 combining acute accent        2 runes, 1 grapheme
+//	"🇯🇵"    two regional indicators, J and P           2 runes, 1 grapheme
+//	"👍🏽"    thumbs up + skin tone modifier             2 runes, 1 grapheme
+//	"👨‍👩‍👧"   man, woman, girl joined by two U+200D ZWJ   5 runes, 1 grapheme
+//	"क्षि"    क + virama + ष + vowel sign i               4 runes, 1 grapheme
+//
+// so reversing the runes, as Q81 does, breaks them apart: the accent lands
+// on the wrong letter, "🇯🇵🇺🇸" becomes "🇸🇺🇵🇯" (two other flags), and the
+// family falls apart into three people. Reverse here keeps each cluster
+// whole. Count, Substring and Truncate count in clusters too, and All and
+// Next walk them.
+//
+// the property tables in tables.go are generated by gen.go from package
+// unicode, so they follow the Unicode version of Go (UnicodeVersion).
+package grapheme
+
+//go:generate go run gen.go
+
+import (
+	"iter"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// property packs what the rules need to know about a rune: its
+// Grapheme_Cluster_Break value in the low 4 bits, whether it is
+// Extended_Pictographic, and its Indic_Conjunct_Break value.
+type property uint8
+
+// Grapheme_Cluster_Break values
+const (
+	prOther property = iota
+	prCR
+	prLF
+	prControl
+	prExtend
+	prZWJ
+	prRegionalIndicator
+	prPrepend
+	prSpacingMark
+	prL
+	prV
+	prT
+	prLV
+	prLVT
+)
+
+const (
+	pictographic property = 1 << 4 // Extended_Pictographic
+
+	// Indic_Conjunct_Break values
+	inLinker    property = 1 << 5
+	inConsonant property = 2 << 5
+	inExtend    property = 3 << 5
+)
+
+func (p property) gcb() property  { return p & 0xF }
+func (p property) incb() property { return p & (3 << 5) }
+
+type propertyRange struct {
+	lo, hi rune
+	p      property
+}
+
+func lookup(r rune) property {
+	if r < 0x20 || r >= 0x7F {
+		i := sort.Search(len(properties), func(i int) bool { return properties[i].hi >= r })
+		if i < len(properties) && properties[i].lo <= r {
+			return properties[i].p
+		}
+	}
+	return prOther // printable ASCII and most letters
+}
+
+// state carries what the rules that look further back than one rune need,
+// from the start of the current cluster.
+type state struct {
+	prev  property
+	ri    bool // prev is a regional indicator that has no partner yet (GB12, GB13)
+	emoji int  // 1: in Pictographic Extend*, 2: in Pictographic Extend* ZWJ (GB11)
+	incb  int  // 1: in Consonant [Extend Linker]*, 2: and a Linker was seen (GB9c)
+}
+
+// add returns the state after a rune with property p joined the cluster.
+// state{}.add(p) starts a cluster.
+func (st state) add(p property) state {
+	next := state{prev: p}
+	next.ri = p.gcb() == prRegionalIndicator && !st.ri
+	switch {
+	case p&pictographic != 0:
+		next.emoji = 1
+	case st.emoji == 1 && p.gcb() == prExtend:
+		next.emoji = 1
+	case st.emoji == 1 && p.gcb() == prZWJ:
+		next.emoji = 2
+	}
+	switch {
+	case p.incb() == inConsonant:
+		next.incb = 1
+	case st.incb > 0 && p.incb() == inLinker:
+		next.incb = 2
+	case st.incb > 0 && p.incb() == inExtend:
+		next.incb = st.incb
+	}
+	return next
+}
+
+// joins reports whether there is no boundary between the previous rune and
+// one with property p, and if so adds it to the state.
+func (st *state) joins(p property) bool {
+	a, b := st.prev.gcb(), p.gcb()
+	join := false
+	switch {
+	case a == prCR && b == prLF: // GB3
+		join = true
+	case a == prControl || a == prCR || a == prLF: // GB4
+	case b == prControl || b == prCR || b == prLF: // GB5
+	case a == prL && (b == prL || b == prV || b == prLV || b == prLVT): // GB6
+		join = true
+	case (a == prLV || a == prV) && (b == prV || b == prT): // GB7
+		join = true
+	case (a == prLVT || a == prT) && b == prT: // GB8
+		join = true
+	case b == prExtend || b == prZWJ || b == prSpacingMark || a == prPrepend: // GB9, GB9a, GB9b
+		join = true
+	case st.incb == 2 && p.incb() == inConsonant: // GB9c
+		join = true
+	case st.emoji == 2 && p&pictographic != 0: // GB11
+		join = true
+	case st.ri && b == prRegionalIndicator: // GB12, GB13
+		join = true
+	}
+	if join {
+		*st = st.add(p)
+	}
+	return join
+}
+
+// firstLen returns the length in bytes of the first cluster of s.
+func firstLen(s string) int {
+	if len(s) == 0 {
+		return 0
+	}
+	if s[0] < utf8.RuneSelf && s[0] != '\r' && (len(s) == 1 || s[1] < utf8.RuneSelf) {
+		return 1 // ASCII followed by ASCII: always a boundary, except CR LF
+	}
+	r, n := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError && n == 1 {
+		return 1 // an invalid byte takes no marks after it
+	}
+	st := state{}.add(lookup(r))
+	i := n
+	for i < len(s) {
+		r, n := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && n == 1 || !st.joins(lookup(r)) {
+			break
+		}
+		i += n
+	}
+	return i
+}
+
+// Next returns the first grapheme cluster of s and the rest of s. for an
+// empty s both are empty. invalid UTF-8 bytes are clusters of their own.
+func Next(s string) (g, rest string) {
+	n := firstLen(s)
+	return s[:n], s[n:]
+}
+
+// All iterates over the clusters of s with their byte offsets, like
+// for i, r := range s does with runes.
+func All(s string) iter.Seq2[int, string] {
+	return func(yield func(int, string) bool) {
+		for i := 0; i < len(s); {
+			n := firstLen(s[i:])
+			if !yield(i, s[i:i+n]) {
+				return
+			}
+			i += n
+		}
+	}
+}
+
+// Count returns the number of grapheme clusters in s.
+func Count(s string) int {
+	n := 0
+	for len(s) > 0 {
+		s = s[firstLen(s):]
+		n++
+	}
+	return n
+}
+
+// Reverse returns s with its clusters in reverse order; each cluster keeps
+// its runes in order.
+func Reverse(s string) string {
+	var gs []string
+	for _, g := range All(s) {
+		gs = append(gs, g)
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := len(gs) - 1; i >= 0; i-- {
+		b.WriteString(gs[i])
+	}
+	return b.String()
+}
+
+// Substring returns the clusters of s from index start up to, not including,
+// end, counting in clusters. indexes past the end of s are treated as the
+// end; a negative start is 0.
+func Substring(s string, start, end int) string {
+	start = max(start, 0)
+	if start >= end {
+		return ""
+	}
+	from, n := len(s), 0
+	for i := range All(s) {
+		if n == start {
+			from = i
+		}
+		if n == end {
+			return s[from:i]
+		}
+		n++
+	}
+	return s[from:]
+}
+
+// Truncate cuts s to at most n clusters. when it cuts, it ends the result
+// with tail (like "…"), which counts toward n.
+func Truncate(s string, n int, tail string) string {
+	if Count(s) <= n {
+		return s
+	}
+	keep := max(0, n-Count(tail))
+	return Substring(s, 0, keep) + tail
+}
@@ -0,0 +1,109 @@
+package grapheme
+
+import (
+	"bufio"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestConformance checks every case of the Unicode test file for the
+// version the tables were generated from. a new Go with a new Unicode needs
+// the file of that version, from
+// https://www.unicode.org/Public/<version>/ucd/auxiliary/GraphemeBreakTest.txt.
+func TestConformance(t *testing.T) {
+	f, err := os.Open("testdata/GraphemeBreakTest.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	sc.Scan()
+	if want := "# GraphemeBreakTest-" + UnicodeVersion + ".txt"; sc.Text() != want {
+		t.Fatalf("testdata is %q, want %q", sc.Text(), want)
+	}
+	n := 0
+	for line := 1; sc.Scan(); line++ {
+		fields, _, _ := strings.Cut(sc.Text(), "#")
+		if strings.TrimSpace(fields) == "" {
+			continue
+		}
+		// ÷ 0061 × 0308 ÷ 0062 ÷: ÷ is a break, × is none
+		var s string
+		var want []string
+		for _, f := range strings.Fields(fields) {
+			switch f {
+			case "÷":
+				want = append(want, "")
+			case "×":
+			default:
+				r, err := strconv.ParseUint(f, 16, 32)
+				if err != nil {
+					t.Fatalf("line %d: %v", line, err)
+				}
+				s += string(rune(r))
+				want[len(want)-1] += string(rune(r))
+			}
+		}
+		want = want[:len(want)-1] // the ÷ at the end starts no cluster
+		var got []string
+		for _, g := range All(s) {
+			got = append(got, g)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("line %d: %+q split into %+q, want %+q", line, s, got, want)
+		}
+		if Count(s) != len(want) {
+			t.Errorf("line %d: Count(%+q) = %d, want %d", line, s, Count(s), len(want))
+		}
+		n++
+	}
+	if err := sc.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if n < 700 {
+		t.Fatalf("only %d cases in testdata", n)
+	}
+}
+
+func TestInvalidUTF8(t *testing.T) {
+	tests := []struct {
+		s    string
+		want []string
+	}{
+		{"\x80́", []string{"\x80", "́"}},
+		{"e\x80́", []string{"e", "\x80", "́"}},
+		{"é\xff", []string{"é", "\xff"}},
+		{"\xe4\xb8", []string{"\xe4", "\xb8"}},
+		{"a‍\xff", []string{"a‍", "\xff"}},
+		// U+FFFD itself is a valid rune like any other
+		{"�́", []string{"�́"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, g := range All(tt.s) {
+			got = append(got, g)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("All(%+q) = %+q, want %+q", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestReverse(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"café", "éfac"},
+		{"🇯🇵🇺🇸", "🇺🇸🇯🇵"},
+		{"a👨‍👩‍👧b", "b👨‍👩‍👧a"},
+		{"👍🏽!", "!👍🏽"},
+		{"\r\nx", "x\r\n"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Reverse(tt.in); got != tt.want {
+			t.Errorf("Reverse(%+q) = %+q, want %+q", tt.in, got, tt.want)
+		}
+	}
+}
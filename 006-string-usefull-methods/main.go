@@ -61,6 +61,11 @@ func main() {
 	// count occurrences
 	fmt.Println("total t appears in s1: ", strings.Count(s1, "t"))           // 2
 	fmt.Println("total 'aeiou' appears in s1: ", strings.Count(s1, "aeiou")) // 0 (not a substring)
+	// Index, Count and Contains look for one pattern; checking a text for
+	// hundreds of keywords with them reads it hundreds of times. the
+	// ./ahocorasick package finds (and replaces) all of them in one pass;
+	// go test -run '^$' -bench . ./ahocorasick compares it with a
+	// strings.Index loop and strings.NewReplacer.
 
 	// 3. case conversion
 	fmt.Println("============================= CASE CONVERSION =============================")
@@ -73,6 +78,10 @@ func main() {
 	// for propper title casing use:
 	caser := cases.Title(language.English)
 	fmt.Println(caser.String(s3)) // "Hello, World!"
+	// for identifiers (userID, http_server, HTTPServer) title casing is not
+	// enough: it writes "Id" and "Http". the ./casing package converts between
+	// camelCase, PascalCase, snake_case, kebab-case and others, keeping
+	// acronyms like ID and HTTP intact.
 
 	// 4. trimming (remove characters)
 	fmt.Println("============================= TRIMMING =============================")
@@ -119,6 +128,10 @@ func main() {
 	fmt.Println("pad left: ", strings.Repeat(" ", 5)+s1)                        // "     the string package" (pad left with 5 spaces)
 	fmt.Println("pad right: ", s1+strings.Repeat(" ", 5))                       // "the string package     " (pad right with 5 spaces)
 	fmt.Println("pad both: ", strings.Repeat(" ", 2)+s1+strings.Repeat(" ", 2)) // "  the string package  " (pad both sides with 2 spaces)
+	// these line up only if every character is 1 column wide: "日本" is 4 columns,
+	// "é" can be 2 runes in 1 column, "👍🏽" is 2 runes in 2 columns.
+	// the ./display package pads by terminal columns (PadLeft, PadRight, Center)
+	// and aligns columns of a table the same way.
 
 	// 9. comparing
 	fmt.Println("============================= COMPARING =============================")
@@ -129,6 +142,10 @@ func main() {
 	fmt.Println("compare: ", strings.EqualFold("go", "gO"))              // true (case insensitive)
 	fmt.Println("compare: ", strings.EqualFold("go", "go"))              // true (case insensitive)
 	fmt.Println("compare: ", strings.EqualFold("go", "golang"))          // false (case insensitive)
+	// Compare and EqualFold only say equal or not. to say how close two strings
+	// are ("did you mean status?" for "stauts") see the ./fuzzy package:
+	// Levenshtein, Damerau-Levenshtein, Jaro-Winkler, LCS, n-gram Jaccard,
+	// and Find, an fzf-style fuzzy finder.
 
 	// NOTE:
 	// DIRECT COMPARISION PREFERRED FOR EQUALITIY
@@ -139,6 +156,10 @@ func main() {
 	// - it's the order that words would appear in a dictionary
 	// - it's the order that words would appear in a book
 	// - it's the order that words would appear in a phone book
+	// but < compares bytes, not a dictionary: "Zebra" < "apfel" < "Äpfel", and
+	// "file10" < "file2". the ./collation package sorts the way a language
+	// does (German ä with a, Swedish ä after z), ignoring case or accents if
+	// asked, with digits compared by value.
 
 	// 10. understanding rues
 	fmt.Println("============================= UNDERSTANDING RUNES =============================")
@@ -147,6 +168,8 @@ func main() {
 	// rune: Unicode code point (a character) int32
 	// string: []byte (under the hood)
 	// rune: int32 (under the hood)
+	// to see the bytes, runes, code points and names of any string, including
+	// invalid UTF-8: go run ./cmd/inspect 世界 héllo
 
 	// 11. byte slices & strings
 	fmt.Println("============================= BYTE SLICES & STRINGS =============================")
@@ -634,6 +657,8 @@ Explanation:
 // ============================================================================
 // SECTION 4: RUNES AND UTF-8 QUESTIONS
 // ============================================================================
+// every answer below can be checked with go run ./cmd/inspect <string>: it
+// prints each UTF-8 sequence with its byte offset, bytes, rune and name.
 
 // Q31. What is a rune in Go?
 /*
@@ -769,6 +794,8 @@ Problem: Very inefficient!
 - Each += creates a new string (10000 allocations)
 - O(n²) time complexity
 - Lots of garbage for GC
+- Measured: 13.9ms and 53MB allocated, against 69µs and 47KB for
+  strings.Builder (see Q78 and the benchmarks in ./concat)
 
 Solution: Use strings.Builder
 */
@@ -1450,7 +1477,7 @@ Each += operation:
 3. Appends new content
 4. Old string becomes garbage
 
-For n=10000: ~50 million bytes allocated!
+For n=10000: 1+2+...+10000 bytes, ~50 million bytes allocated!
 
 Solution: strings.Builder
 var b strings.Builder
@@ -1459,10 +1486,23 @@ for i := 0; i < n; i++ {
 }
 s := b.String()
 
-Benchmark results (n=10000):
-- Concatenation: ~500ms, 50MB allocated
-- Builder: ~0.5ms, 20KB allocated
-- 1000x faster!
+Measured with go test -run '^$' -bench Concat ./concat, turned into a table
+by go run ./cmd/concatbench (go1.27.1, linux/amd64, Intel Xeon), n one-byte
+parts:
+                         n=10000                     n=100000
+- +=                     13.9ms, 53MB, 9999 allocs   1.99s, 5.3GB
+- fmt.Sprintf            17.1ms, 53MB, 30034 allocs  3.13s, 8.3GB
+- strings.Join           88µs, 10KB, 1 alloc         1.02ms
+- strings.Builder        69µs, 47KB, 16 allocs       0.99ms
+- Builder with Grow(n)   48µs, 10KB, 1 alloc         0.57ms
+- bytes.Buffer           80µs, 43KB, 10 allocs       1.10ms
+- []byte append          50µs, 57KB, 17 allocs       0.83ms
+- Builder ~200x faster than += at n=10000, ~2000x at n=100000
+
+Fitting time ∝ n^k between n=10000 and n=100000 gives k = 2.16 for +=
+(O(n²): 10x the parts, ~140x the time) and k = 1.06-1.22 for the others
+(O(n)). Below ~100 parts the difference is small: at n=10, += takes 276ns
+and Builder 145ns. Numbers vary by machine; run the benchmarks for yours.
 */
 
 // Q79. How do you efficiently check if string contains any of multiple substrings?
@@ -1554,6 +1594,14 @@ Example:
 reverseASCII("hello")  // "olleh" ✓
 reverseASCII("世界")   // Garbled ✗
 reverseUnicode("世界") // "界世" ✓
+
+But []rune is not "proper" either: a character the reader sees can be
+several runes (a grapheme cluster).
+reverseUnicode("é")  // accent now comes before the e ✗
+reverseUnicode("🇯🇵🇺🇸")    // "🇸🇺🇵🇯": two different flags ✗
+reverseUnicode("👨‍👩‍👧")     // the family falls apart ✗
+The ./grapheme package segments by Unicode's rules (UAX #29):
+grapheme.Reverse, Count, Substring and Truncate keep clusters whole.
 */
 
 // Q82. What is the difference between strings.Title and cases.Title?
@@ -1624,6 +1672,13 @@ s2 := s1[0:3]  // "hel"
 // Both point to same underlying array
 
 Slicing is O(1) - just creates new header
+
+Editing is the opposite: s[:i] + "x" + s[i:] copies all of s, so typing
+into the middle of a 1 MB string copies 1 MB per key. The ./rope package
+keeps a large text as a balanced tree of such slices, with O(log n)
+Insert, Delete, Split and Concat, and RuneOffset and LineOffset to find
+runes and lines. go test -run '^$' -bench Typing ./rope compares it with
+strings.
 */
 
 // Q85. How do you handle case-insensitive string operations?
@@ -1650,6 +1705,15 @@ re := regexp.MustCompile("(?i)old")
 result := re.ReplaceAllString(s, "new")
 
 Note: ToLower/ToUpper create new strings (allocations)
+
+Caveat: lower casing is not case folding, and neither ignores accents or
+the two ways to write "é" (one rune, or e + combining accent):
+strings.EqualFold("straße", "STRASSE")  // false
+strings.Contains("café", "cafe")        // false
+The ./normalize package does this with golang.org/x/text:
+normalize.EqualFold("straße", "STRASSE")  // true
+normalize.Contains("Café", "cafe")        // true
+normalize.Index(s, substr)                // start, end of the match in s
 */
 
 // ============================================================================
@@ -0,0 +1,195 @@
+// Package normalize compares and searches text the way a reader would:
+// ignoring case, accents and the different ways Unicode can spell the same
+// character.
+//
+// "é" can be one rune (U+00E9) or two (e + U+0301 combining acute), and
+// "ﬁ" is a ligature of f and i. == sees different strings; normalization
+// picks one spelling:
+//
+//	NFC   composed:        e + U+0301 → é          what to store and compare
+//	NFD   decomposed:      é → e + U+0301          to work on the accents
+//	NFKC  compatibility:   ﬁ → fi, Ｇｏ → Go, ² → 2  for identifiers and search
+//
+// Q85's strings.ToLower on both sides misses all of that, and lower casing is
+// not case folding: "STRASSE" and "straße" are the same word, and so are
+// "ΣΊΣΥΦΟΣ" and "σίσυφος". Fold does full Unicode case folding, and Key
+// combines everything into a search key, on which EqualFold, Contains and
+// Index work. they build on golang.org/x/text: unicode/norm, runes, cases
+// and transform.
+package normalize
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NFC returns s in Normalization Form C, composed.
+func NFC(s string) string { return norm.NFC.String(s) }
+
+// NFD returns s in Normalization Form D, decomposed.
+func NFD(s string) string { return norm.NFD.String(s) }
+
+// NFKC returns s in Normalization Form KC: compatibility characters like
+// ligatures, full width letters and superscripts replaced by their plain
+// form, then composed.
+func NFKC(s string) string { return norm.NFKC.String(s) }
+
+// strokes maps letters whose mark is part of the letter, and so does not
+// come off in NFD, to the letter without it.
+var strokes = runes.Map(func(r rune) rune {
+	switch r {
+	case 'ø':
+		return 'o'
+	case 'Ø':
+		return 'O'
+	case 'ł':
+		return 'l'
+	case 'Ł':
+		return 'L'
+	case 'đ':
+		return 'd'
+	case 'Đ':
+		return 'D'
+	case 'ħ':
+		return 'h'
+	case 'Ħ':
+		return 'H'
+	case 'ŧ':
+		return 't'
+	case 'Ŧ':
+		return 'T'
+	}
+	return r
+})
+
+// StripAccents removes diacritics: "Café" is "Cafe", "Łódź" is "Lodz",
+// "Ελλάδα" is "Ελλαδα". it decomposes s, drops the combining marks on Latin,
+// Greek and Cyrillic letters and composes the rest again. in other scripts a
+// nonspacing mark is not an accent but a vowel or part of the letter, as in
+// が or कु, so they are unchanged, and so are letters like ß or 日.
+func StripAccents(s string) string {
+	out, _, err := transform.String(norm.NFD, s)
+	if err != nil {
+		return s
+	}
+	out, _, err = transform.String(transform.Chain(strokes, norm.NFC), stripMarks(out))
+	if err != nil {
+		return s
+	}
+	return out
+}
+
+// stripMarks removes from decomposed text the nonspacing marks that follow a
+// Latin, Greek or Cyrillic letter. invalid UTF-8 is kept as it is.
+func stripMarks(s string) string {
+	var b strings.Builder
+	accents := false // whether the marks so far are on a letter with accents
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if unicode.Is(unicode.Mn, r) {
+			if !accents {
+				b.WriteString(s[i : i+size])
+			}
+		} else {
+			accents = unicode.In(r, unicode.Latin, unicode.Greek, unicode.Cyrillic)
+			b.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	return b.String()
+}
+
+// Fold returns s with full Unicode case folding: the form in which strings
+// that differ only in case are equal. unlike strings.ToLower it maps "ß" to
+// "ss" and both sigmas, σ and ς, to σ.
+func Fold(s string) string { return cases.Fold().String(s) }
+
+// Key returns the form of s that EqualFold, Contains and Index compare:
+// case folded, compatibility decomposed, without accents as StripAccents
+// removes them, composed again.
+// two strings that a reader would call the same word have the same Key:
+// "Straße", "STRASSE" and "strasse" do, so do "Café" and "cafe".
+func Key(s string) string {
+	k, _ := key(s, false)
+	return k
+}
+
+// key returns Key(s), and if offsets is set, for each byte of the key the
+// offset in s of the normalization segment it came from, plus len(s) at the
+// end.
+func key(s string, offsets bool) (string, []int) {
+	decompose := transform.Chain(cases.Fold(), norm.NFKD)
+	compose := transform.Chain(strokes, norm.NFC)
+	var b strings.Builder
+	var offs []int
+
+	// a segment starts at a character that does not combine with what came
+	// before it, so keying the segments one by one gives the key of s
+	var it norm.Iter
+	it.InitString(norm.NFKD, s)
+	for !it.Done() {
+		start := it.Pos()
+		it.Next()
+		seg, _, err := transform.String(decompose, s[start:it.Pos()])
+		if err == nil {
+			seg, _, err = transform.String(compose, stripMarks(seg))
+		}
+		if err != nil {
+			seg = s[start:it.Pos()]
+		}
+		b.WriteString(seg)
+		if offsets {
+			for range len(seg) {
+				offs = append(offs, start)
+			}
+		}
+	}
+	if offsets {
+		offs = append(offs, len(s))
+	}
+	return b.String(), offs
+}
+
+// EqualFold reports whether a and b are the same text ignoring case,
+// accents and compatibility differences: EqualFold("Crème Brûlée",
+// "CREME BRULEE") is true. strings.EqualFold only ignores simple case.
+func EqualFold(a, b string) bool { return Key(a) == Key(b) }
+
+// Contains reports whether substr is in s, ignoring case and accents.
+func Contains(s, substr string) bool {
+	start, _ := Index(s, substr)
+	return start >= 0
+}
+
+// Index returns where the first match of substr, ignoring case and accents,
+// is in s: s[start:end]. the match can be a different length than substr,
+// so unlike strings.Index it returns the end too: "Straße" matches "SS" at
+// s[4:6], the bytes of ß. a match that begins or ends inside a character,
+// like "f" in "ﬁ", covers the whole character. start and end are -1 if
+// there is no match.
+func Index(s, substr string) (start, end int) {
+	sub := Key(substr)
+	k, offs := key(s, true)
+	i := strings.Index(k, sub)
+	if i < 0 {
+		return -1, -1
+	}
+	if sub == "" {
+		return 0, 0
+	}
+	last := i + len(sub) - 1 // the last key byte of the match
+	end = len(s)
+	for j := last + 1; j < len(offs); j++ {
+		if offs[j] != offs[last] {
+			end = offs[j]
+			break
+		}
+	}
+	return offs[i], end
+}
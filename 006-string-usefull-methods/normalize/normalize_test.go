@@ -0,0 +1,73 @@
+package normalize
+
+import "testing"
+
+func TestStripAccents(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"Café", "Cafe"},
+		{"Cafe\u0301", "Cafe"},
+		{"Łódź", "Lodz"},
+		{"Crème Brûlée", "Creme Brulee"},
+		{"Ελλάδα", "Ελλαδα"},
+		{"Йошкар-Ола", "Иошкар-Ола"},
+		{"straße", "straße"},
+		{"日本", "日本"},
+		// marks in other scripts are vowels or part of the letter
+		{"が", "が"},
+		{"パ", "パ"},
+		{"कु", "कु"},
+		{"שָׁלוֹם", "שָׁלוֹם"},
+		{"مَرْحَبًا", "مَرْحَبًا"},
+		// a mark with no letter before it stays
+		{"\u0301a", "\u0301a"},
+	}
+	for _, tt := range tests {
+		if got := StripAccents(tt.in); got != tt.want {
+			t.Errorf("StripAccents(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestEqualFold(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"Straße", "STRASSE", true},
+		{"Café", "cafe", true},
+		{"Crème Brûlée", "CREME BRULEE", true},
+		{"ΣΊΣΥΦΟΣ", "σίσυφος", true},
+		{"ﬁle", "FILE", true},
+		{"Ｇｏ", "go", true},
+		{"が", "か", false},
+		{"कु", "क", false},
+		{"שָׁלוֹם", "שלום", false},
+		{"cafe", "cafes", false},
+	}
+	for _, tt := range tests {
+		if got := EqualFold(tt.a, tt.b); got != tt.want {
+			t.Errorf("EqualFold(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestIndex(t *testing.T) {
+	tests := []struct {
+		s, substr  string
+		start, end int
+	}{
+		{"Straße", "SS", 4, 6},
+		{"Un café noir", "CAFE", 3, 8},
+		{"une ﬁle", "f", 4, 7},
+		{"abc", "", 0, 0},
+		{"abc", "d", -1, -1},
+		{"かがみ", "が", 3, 6},
+		{"かがみ", "かか", -1, -1},
+	}
+	for _, tt := range tests {
+		start, end := Index(tt.s, tt.substr)
+		if start != tt.start || end != tt.end {
+			t.Errorf("Index(%q, %q) = %d, %d, want %d, %d", tt.s, tt.substr, start, end, tt.start, tt.end)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+// Package rope holds a large text as a balanced tree of strings, so that
+// editing it does not copy it.
+//
+// Q76 and Q84: a string cannot change, and slicing one is O(1) because the
+// slice shares the bytes. the other side of that is that every edit builds
+// a new string: inserting one byte in the middle of 10 MB copies 10 MB, and
+// typing 1000 characters into it copies 10 GB. a Rope keeps the text in
+// leaves of up to 1 KB, which are slices of the strings it was given, under
+// a balanced tree that counts bytes, runes and lines. an edit rebuilds the
+// O(log n) nodes on one path and shares the rest:
+//
+//	r := rope.New(text)
+//	r = r.Insert(r.LineOffset(99), "a new line 100\n")
+//	r = r.Delete(0, r.RuneOffset(10))  // the first 10 runes
+//	r.WriteTo(f)                       // without building the string
+//
+// a Rope is a value that never changes, like a string: every edit returns a
+// new Rope and the old one stays as it was, so keeping the old ones is an
+// undo history, and a Rope is safe for concurrent use. the zero value is
+// the empty text. offsets are in bytes, as for strings, and out of range
+// offsets panic, as slicing a string does.
+package rope
+
+import (
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+	"unicode/utf8"
+)
+
+// Rope is an immutable text.
+type Rope struct {
+	root *node
+}
+
+// New returns a Rope of s. the leaves are slices of s, so s is not copied.
+func New(s string) Rope {
+	var leaves []*node
+	for len(s) > 0 {
+		n := cut(s, maxLeaf)
+		leaves = append(leaves, leaf(s[:n]))
+		s = s[n:]
+	}
+	return Rope{build(leaves)}
+}
+
+// Len returns the length of the text in bytes.
+func (r Rope) Len() int {
+	if r.root == nil {
+		return 0
+	}
+	return r.root.bytes
+}
+
+// RuneCount returns the number of runes in the text, as
+// utf8.RuneCountInString would.
+func (r Rope) RuneCount() int {
+	if r.root == nil {
+		return 0
+	}
+	return r.root.runes
+}
+
+// LineCount returns the number of lines: the number of '\n' plus one, so
+// "a\nb" and "a\n" have 2 lines, and the empty text has 1.
+func (r Rope) LineCount() int {
+	if r.root == nil {
+		return 1
+	}
+	return r.root.newlines + 1
+}
+
+// String returns the text as one string. it copies the whole text; Chunks,
+// WriteTo and Reader do not.
+func (r Rope) String() string {
+	var b strings.Builder
+	b.Grow(r.Len())
+	for s := range r.Chunks() {
+		b.WriteString(s)
+	}
+	return b.String()
+}
+
+// Concat returns the text of r followed by that of o.
+func (r Rope) Concat(o Rope) Rope {
+	switch {
+	case r.root == nil:
+		return o
+	case o.root == nil:
+		return r
+	case !utf8.RuneStart(byteAt(o.root, 0)):
+		return Rope{mend(r.root, o.root)}
+	}
+	return Rope{join(r.root, o.root)}
+}
+
+// Split returns the text before byte offset i and the text from i on. i
+// may be inside a UTF-8 sequence: the two halves are then invalid UTF-8,
+// but concatenated again they are the text they came from.
+func (r Rope) Split(i int) (Rope, Rope) {
+	r.check(i)
+	a, b := split(r.root, i)
+	return Rope{a}, Rope{b}
+}
+
+// Slice returns the text of bytes [i, j), as s[i:j] does for a string.
+func (r Rope) Slice(i, j int) Rope {
+	r.checkRange(i, j)
+	_, b := split(r.root, i)
+	a, _ := split(b, j-i)
+	return Rope{a}
+}
+
+// Insert returns the text with s inserted at byte offset i.
+func (r Rope) Insert(i int, s string) Rope {
+	a, b := r.Split(i)
+	return a.Concat(New(s)).Concat(b)
+}
+
+// Delete returns the text without bytes [i, j).
+func (r Rope) Delete(i, j int) Rope {
+	r.checkRange(i, j)
+	a, _ := split(r.root, i)
+	_, b := split(r.root, j)
+	return Rope{a}.Concat(Rope{b})
+}
+
+// ByteAt returns the byte at offset i.
+func (r Rope) ByteAt(i int) byte {
+	if i < 0 || i >= r.Len() {
+		panic(fmt.Sprintf("rope: index %d out of range [0:%d]", i, r.Len()))
+	}
+	return byteAt(r.root, i)
+}
+
+// RuneAt decodes the rune at byte offset i and returns it with its size,
+// as utf8.DecodeRuneInString(s[i:]) does.
+func (r Rope) RuneAt(i int) (rune, int) {
+	if i == r.Len() {
+		return utf8.RuneError, 0
+	}
+	r.ByteAt(i) // check i
+	s, off := leafAt(r.root, i)
+	return utf8.DecodeRuneInString(s[i-off:])
+}
+
+// RuneOffset returns the byte offset of rune n, counting from 0; for n ==
+// RuneCount it is Len. it is what indexing a []rune of the text by n would
+// give, without making the []rune.
+func (r Rope) RuneOffset(n int) int {
+	if n < 0 || n > r.RuneCount() {
+		panic(fmt.Sprintf("rope: rune %d out of range [0:%d]", n, r.RuneCount()))
+	}
+	if n == r.RuneCount() {
+		return r.Len()
+	}
+	t, off := r.root, 0
+	for t.left != nil {
+		if n < t.left.runes {
+			t = t.left
+		} else {
+			n -= t.left.runes
+			off += t.left.bytes
+			t = t.right
+		}
+	}
+	i := 0
+	for ; n > 0; n-- {
+		_, size := utf8.DecodeRuneInString(t.s[i:])
+		i += size
+	}
+	return off + i
+}
+
+// LineOffset returns the byte offset where line n starts, counting from 0:
+// 0 for line 0, and just after the n-th '\n' for the others.
+func (r Rope) LineOffset(n int) int {
+	if n < 0 || n >= r.LineCount() {
+		panic(fmt.Sprintf("rope: line %d out of range [0:%d]", n, r.LineCount()))
+	}
+	if n == 0 {
+		return 0
+	}
+	t, off := r.root, 0
+	for t.left != nil {
+		if n <= t.left.newlines {
+			t = t.left
+		} else {
+			n -= t.left.newlines
+			off += t.left.bytes
+			t = t.right
+		}
+	}
+	i := 0
+	for ; n > 0; n-- {
+		i += strings.IndexByte(t.s[i:], '\n') + 1
+	}
+	return off + i
+}
+
+// Line returns line n, counting from 0, with its '\n' if it has one, as
+// strings.SplitAfter(s, "\n") would.
+func (r Rope) Line(n int) Rope {
+	start := r.LineOffset(n)
+	if n+1 == r.LineCount() {
+		return r.Slice(start, r.Len())
+	}
+	return r.Slice(start, r.LineOffset(n+1))
+}
+
+// Chunks returns an iterator over pieces of the text that, concatenated,
+// are the text. they are the leaves of the tree, so there are about Len/1K
+// of them and nothing is copied.
+func (r Rope) Chunks() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		walk(r.root, yield)
+	}
+}
+
+// WriteTo writes the text to w chunk by chunk. it implements io.WriterTo,
+// so io.Copy(w, r) uses it.
+func (r Rope) WriteTo(w io.Writer) (int64, error) {
+	var n int64
+	var err error
+	walk(r.root, func(s string) bool {
+		var k int
+		k, err = io.WriteString(w, s)
+		n += int64(k)
+		return err == nil
+	})
+	return n, err
+}
+
+// Reader returns an io.Reader of the text.
+func (r Rope) Reader() *Reader {
+	return &Reader{r: r}
+}
+
+func (r Rope) check(i int) {
+	if i < 0 || i > r.Len() {
+		panic(fmt.Sprintf("rope: offset %d out of range [0:%d]", i, r.Len()))
+	}
+}
+
+func (r Rope) checkRange(i, j int) {
+	if i < 0 || j < i || j > r.Len() {
+		panic(fmt.Sprintf("rope: slice bounds [%d:%d] out of range [0:%d]", i, j, r.Len()))
+	}
+}
+
+// Reader reads a Rope. it implements io.Reader and io.WriterTo.
+type Reader struct {
+	r   Rope
+	off int
+}
+
+// Read copies the next bytes of the text into p.
+func (rd *Reader) Read(p []byte) (int, error) {
+	if rd.off >= rd.r.Len() {
+		return 0, io.EOF
+	}
+	n := 0
+	for n < len(p) && rd.off < rd.r.Len() {
+		s, start := leafAt(rd.r.root, rd.off)
+		k := copy(p[n:], s[rd.off-start:])
+		n += k
+		rd.off += k
+	}
+	return n, nil
+}
+
+// WriteTo writes the rest of the text to w.
+func (rd *Reader) WriteTo(w io.Writer) (int64, error) {
+	n, err := rd.r.Slice(rd.off, rd.r.Len()).WriteTo(w)
+	rd.off += int(n)
+	return n, err
+}
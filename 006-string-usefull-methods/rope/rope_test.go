@@ -0,0 +1,223 @@
+package rope
+
+import (
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"unicode/utf8"
+)
+
+// compare checks every query of r against the string s it should hold. the
+// offsets are checked at up to about 100 runes and lines, spread over s.
+func compare(t *testing.T, r Rope, s string) {
+	t.Helper()
+	if r.Len() != len(s) {
+		t.Fatalf("Len = %d, want %d", r.Len(), len(s))
+	}
+	if got := r.String(); got != s {
+		t.Fatalf("String = %q, want %q", got, s)
+	}
+	if want := utf8.RuneCountInString(s); r.RuneCount() != want {
+		t.Fatalf("RuneCount = %d, want %d", r.RuneCount(), want)
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if r.LineCount() != len(lines) {
+		t.Fatalf("LineCount = %d, want %d", r.LineCount(), len(lines))
+	}
+
+	var offs []int // the byte offset of each rune, as ranging over s gives
+	for i := range s {
+		offs = append(offs, i)
+	}
+	offs = append(offs, len(s))
+	for n := 0; n < len(offs); n += max(1, len(offs)/64) {
+		if got := r.RuneOffset(n); got != offs[n] {
+			t.Fatalf("RuneOffset(%d) = %d, want %d", n, got, offs[n])
+		}
+		rn, size := r.RuneAt(offs[n])
+		if wr, wsize := utf8.DecodeRuneInString(s[offs[n]:]); rn != wr || size != wsize {
+			t.Fatalf("RuneAt(%d) = %q %d, want %q %d", offs[n], rn, size, wr, wsize)
+		}
+	}
+	if got := r.RuneOffset(len(offs) - 1); got != len(s) {
+		t.Fatalf("RuneOffset(%d) = %d, want %d", len(offs)-1, got, len(s))
+	}
+
+	start := 0
+	for l, line := range lines {
+		if l%max(1, len(lines)/32) == 0 || l == len(lines)-1 {
+			if got := r.LineOffset(l); got != start {
+				t.Fatalf("LineOffset(%d) = %d, want %d", l, got, start)
+			}
+			if got := r.Line(l).String(); got != line {
+				t.Fatalf("Line(%d) = %q, want %q", l, got, line)
+			}
+		}
+		start += len(line)
+	}
+}
+
+// compareReaders checks the ways of reading r without building a string.
+func compareReaders(t *testing.T, r Rope, s string) {
+	t.Helper()
+	var b strings.Builder
+	if n, err := r.WriteTo(&b); err != nil || n != int64(len(s)) || b.String() != s {
+		t.Fatalf("WriteTo = %d, %v, and wrote a different text", n, err)
+	}
+	got, err := io.ReadAll(iotest.HalfReader(r.Reader()))
+	if err != nil || string(got) != s {
+		t.Fatalf("reading Reader = %v, and read a different text", err)
+	}
+	if err := iotest.TestReader(r.Reader(), []byte(s)); err != nil {
+		t.Fatal(err)
+	}
+	var chunks []string
+	for c := range r.Chunks() {
+		chunks = append(chunks, c)
+	}
+	if strings.Join(chunks, "") != s {
+		t.Fatal("Chunks differ")
+	}
+}
+
+// edit applies one edit, chosen by op, a and b, to the Rope r and to the
+// string s it holds, and returns both. text is what inserts insert from.
+// offsets can be anywhere, inside a rune or not. the edits that add text do
+// nothing once s is 16 KB, so that the checks stay fast.
+func edit(t *testing.T, r Rope, s string, text string, op byte, a, b int) (Rope, string) {
+	t.Helper()
+	i := a % (len(s) + 1)
+	j := i + b%(len(s)-i+1)
+	if len(s) > 1<<14 && (op%5 == 0 || op%5 == 4) {
+		return r, s
+	}
+	switch op % 5 {
+	case 0: // Insert a piece of text, repeated, so the tree grows leaves
+		ins := strings.Repeat(text[b%(len(text)+1):], 1+int(op>>3))
+		ins = ins[:min(len(ins), 4096)]
+		return r.Insert(i, ins), s[:i] + ins + s[i:]
+	case 1:
+		return r.Delete(i, j), s[:i] + s[j:]
+	case 2: // cut in three and put the pieces back in another order
+		x, yz := r.Split(i)
+		y, z := yz.Split(j - i)
+		return z.Concat(x).Concat(y), s[j:] + s[:i] + s[i:j]
+	case 3:
+		if got := r.Slice(i, j).String(); got != s[i:j] {
+			t.Fatalf("Slice(%d, %d) = %q, want %q", i, j, got, s[i:j])
+		}
+		if op&8 != 0 {
+			return r.Slice(i, j), s[i:j]
+		}
+	case 4: // a Rope built from the text so far, and one edited into it
+		return New(s).Concat(r.Slice(i, j)), s + s[i:j]
+	}
+	return r, s
+}
+
+// FuzzRope applies the edits that ops encodes, five bytes each and up to
+// 100 of them, to a Rope and to a string, and compares them after every
+// edit.
+func FuzzRope(f *testing.F) {
+	f.Add("hello, world\n", []byte{0, 0, 5, 0, 3, 1, 0, 2, 0, 4, 2, 0, 1, 0, 9})
+	f.Add("héllo\nwörld 世界 🙂\n", []byte{0x38, 0, 7, 0, 2, 2, 0, 8, 0, 3, 11, 0, 4, 0, 10, 4, 0, 1, 0, 30})
+	// invalid UTF-8, and offsets that cut the runes in two
+	f.Add("\xff\x80\xe4\xb8 世\x96🙂", []byte{0xf8, 0, 1, 0, 5, 2, 0, 3, 0, 1, 1, 0, 2, 0, 1, 0, 0, 9, 0, 2})
+	f.Add("世界", []byte{2, 0, 1, 0, 1, 2, 0, 2, 0, 2, 1, 0, 1, 0, 1})
+	f.Add("a\nb\n\n", []byte{0xff, 0, 0, 0, 0, 0xfb, 0x12, 0x34, 0, 0, 3, 0x20, 0, 0x40, 0})
+	f.Fuzz(func(t *testing.T, text string, ops []byte) {
+		r, s := New(text), text
+		compare(t, r, s)
+		ops = ops[:min(len(ops), 500)]
+		for len(ops) >= 5 {
+			a := int(ops[1])<<8 | int(ops[2])
+			b := int(ops[3])<<8 | int(ops[4])
+			r, s = edit(t, r, s, text, ops[0], a, b)
+			compare(t, r, s)
+			ops = ops[5:]
+		}
+		compareReaders(t, r, s)
+	})
+}
+
+// pieces that random text is made of: 1 to 4 byte runes, newlines, and
+// bytes that are not valid UTF-8 on their own
+var pieces = []string{"a", "b", " ", "\n", "é", "世", "🙂", "\xff", "\x80", "\xe4\xb8", "\x96"}
+
+func randText(r *rand.Rand, max int) string {
+	var b strings.Builder
+	for range r.Intn(max + 1) {
+		b.WriteString(pieces[r.Intn(len(pieces))])
+	}
+	return b.String()
+}
+
+// TestRandomEdits runs longer edit sequences than the fuzz seeds, on texts
+// of many leaves.
+func TestRandomEdits(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	n := 3000
+	if testing.Short() {
+		n = 300
+	}
+	s := randText(r, 3000)
+	rp := New(s)
+	for range n {
+		text := randText(r, 20)
+		if r.Intn(50) == 0 {
+			text = randText(r, 5000)
+		}
+		rp, s = edit(t, rp, s, text, byte(r.Intn(256)), r.Intn(1<<16), r.Intn(1<<16))
+		compare(t, rp, s)
+	}
+	compareReaders(t, rp, s)
+}
+
+func TestZero(t *testing.T) {
+	var r Rope
+	compare(t, r, "")
+	compareReaders(t, r, "")
+	compare(t, r.Insert(0, "x"), "x")
+	compare(t, New("").Concat(r), "")
+}
+
+var sink int
+
+// BenchmarkTyping types 100 characters, one at a time, into the middle of
+// 1 MB of text and deletes them again, with a string and with a Rope.
+func BenchmarkTyping(b *testing.B) {
+	const size, typed = 1 << 20, 100
+	text := strings.Repeat("the quick brown fox jumps over the lazy dog\n", size/44+1)[:size]
+	b.Run("string", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			s := text
+			for k := range typed {
+				mid := len(s)/2 + k
+				s = s[:mid] + "x" + s[mid:]
+			}
+			for range typed {
+				mid := len(s) / 2
+				s = s[:mid] + s[mid+1:]
+			}
+			sink += len(s)
+		}
+	})
+	b.Run("Rope", func(b *testing.B) {
+		b.ReportAllocs()
+		rp := New(text)
+		for b.Loop() {
+			r := rp
+			for k := range typed {
+				r = r.Insert(r.Len()/2+k, "x")
+			}
+			for range typed {
+				mid := r.Len() / 2
+				r = r.Delete(mid, mid+1)
+			}
+			sink += r.Len()
+		}
+	})
+}
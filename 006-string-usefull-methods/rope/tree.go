@@ -0,0 +1,194 @@
+package rope
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// maxLeaf is the most bytes New puts in a leaf, and the size up to which
+// join merges neighbouring leaves, so that many small edits do not leave a
+// tree of one-byte leaves.
+const maxLeaf = 1024
+
+// node is a leaf holding a piece of the text, or a branch whose text is
+// that of left followed by that of right. the tree is an AVL tree: the
+// heights of the children of a branch differ by at most one.
+//
+// no rune is split between two leaves: every leaf starts where decoding the
+// whole text starts a rune (or an invalid byte), so the rune counts of the
+// leaves add up to that of the text.
+type node struct {
+	left, right *node  // nil in a leaf
+	s           string // the text of a leaf
+	height      int    // 0 for a leaf
+	bytes       int
+	runes       int
+	newlines    int
+}
+
+func leaf(s string) *node {
+	if s == "" {
+		return nil
+	}
+	return &node{s: s, bytes: len(s), runes: utf8.RuneCountInString(s), newlines: strings.Count(s, "\n")}
+}
+
+func branch(l, r *node) *node {
+	return &node{
+		left: l, right: r,
+		height:   max(l.height, r.height) + 1,
+		bytes:    l.bytes + r.bytes,
+		runes:    l.runes + r.runes,
+		newlines: l.newlines + r.newlines,
+	}
+}
+
+// build returns a balanced tree of the leaves, in order.
+func build(leaves []*node) *node {
+	switch len(leaves) {
+	case 0:
+		return nil
+	case 1:
+		return leaves[0]
+	}
+	mid := len(leaves) / 2
+	return branch(build(leaves[:mid]), build(leaves[mid:]))
+}
+
+// cut returns where to end a leaf of s that is at most n bytes long, n > 0:
+// n itself unless that is inside a UTF-8 sequence, else the start of the
+// sequence.
+func cut(s string, n int) int {
+	if n >= len(s) {
+		return len(s)
+	}
+	for i := n; i > n-utf8.UTFMax && i > 0; i-- {
+		if utf8.RuneStart(s[i]) {
+			return i
+		}
+	}
+	// a run of continuation bytes: each one decodes on its own
+	return n
+}
+
+// join returns the tree for the text of l followed by that of r, in time
+// proportional to the difference of their heights.
+func join(l, r *node) *node {
+	switch {
+	case l == nil:
+		return r
+	case r == nil:
+		return l
+	case l.left == nil && r.left == nil && l.bytes+r.bytes <= maxLeaf:
+		return leaf(l.s + r.s)
+	case l.height > r.height+1:
+		return balance(l.left, join(l.right, r))
+	case r.height > l.height+1:
+		return balance(join(l, r.left), r.right)
+	}
+	return branch(l, r)
+}
+
+// balance returns a branch of l and r, whose heights differ by at most two,
+// rotating it if they do.
+func balance(l, r *node) *node {
+	switch {
+	case l.height > r.height+1:
+		if l.left.height >= l.right.height {
+			return branch(l.left, branch(l.right, r))
+		}
+		return branch(branch(l.left, l.right.left), branch(l.right.right, r))
+	case r.height > l.height+1:
+		if r.right.height >= r.left.height {
+			return branch(branch(l, r.left), r.right)
+		}
+		return branch(branch(l, r.left.left), branch(r.left.right, r.right))
+	}
+	return branch(l, r)
+}
+
+// split returns the trees for the first i bytes of n and the rest. the
+// leaf that holds byte i is sliced, not copied.
+func split(n *node, i int) (*node, *node) {
+	switch {
+	case n == nil || i <= 0:
+		return nil, n
+	case i >= n.bytes:
+		return n, nil
+	case n.left == nil:
+		return leaf(n.s[:i]), leaf(n.s[i:])
+	case i < n.left.bytes:
+		l, r := split(n.left, i)
+		return l, join(r, n.right)
+	}
+	l, r := split(n.right, i-n.left.bytes)
+	return join(n.left, l), r
+}
+
+// mend joins l and r when r starts with continuation bytes, which Split
+// leaves when it cuts a UTF-8 sequence in two: on their own they are
+// invalid, but after l they may complete its last rune. the end of l and
+// the start of r go into one leaf, so that no rune crosses a leaf boundary.
+func mend(l, r *node) *node {
+	last := l
+	for last.left != nil {
+		last = last.right
+	}
+	// the last rune of l starts in its last leaf, in the last UTFMax-1 bytes
+	// or before
+	k := 0
+	for k < len(last.s)-(utf8.UTFMax-1) {
+		_, size := utf8.DecodeRuneInString(last.s[k:])
+		k += size
+	}
+	tail := last.s[k:]
+	var head []byte
+	for len(head) < utf8.UTFMax-1 && len(head) < r.bytes {
+		b := byteAt(r, len(head))
+		if utf8.RuneStart(b) {
+			break
+		}
+		head = append(head, b)
+	}
+	prefix, _ := split(l, l.bytes-len(tail))
+	_, rest := split(r, len(head))
+	return join(join(prefix, leaf(tail+string(head))), rest)
+}
+
+func byteAt(n *node, i int) byte {
+	for n.left != nil {
+		if i < n.left.bytes {
+			n = n.left
+		} else {
+			i -= n.left.bytes
+			n = n.right
+		}
+	}
+	return n.s[i]
+}
+
+// leafAt returns the leaf that holds byte i, and the offset of its start.
+func leafAt(n *node, i int) (string, int) {
+	off := 0
+	for n.left != nil {
+		if i < n.left.bytes {
+			n = n.left
+		} else {
+			i -= n.left.bytes
+			off += n.left.bytes
+			n = n.right
+		}
+	}
+	return n.s, off
+}
+
+// walk calls yield with the leaves of n in order until it returns false.
+func walk(n *node, yield func(string) bool) bool {
+	if n == nil {
+		return true
+	}
+	if n.left == nil {
+		return yield(n.s)
+	}
+	return walk(n.left, yield) && walk(n.right, yield)
+}